@@ -6,3 +6,10 @@ import "embed"
 //
 //go:embed definitions/*
 var Assets embed.FS
+
+// LibScrapliChecksums is the embedded manifest of pinned libscrapli release checksums, a json
+// object of libscrapli version to an object of release filename to sha256 hex digest. Should be
+// updated along with the libscrapli version via build/update_libscrapli_tag.sh.
+//
+//go:embed libscrapli/checksums.json
+var LibScrapliChecksums []byte
@@ -0,0 +1,179 @@
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"regexp"
+	"sync"
+	"time"
+)
+
+const redacted = "<redacted>"
+
+// Record is a single audit record -- one is written for each cli operation and netconf rpc a
+// driver executes (or refuses to execute).
+type Record struct {
+	Time     time.Time `json:"time"`
+	Operator string    `json:"operator,omitempty"`
+	// Driver is the kind of driver that executed the operation, "cli" or "netconf".
+	Driver   string `json:"driver"`
+	Host     string `json:"host"`
+	Port     uint16 `json:"port"`
+	Platform string `json:"platform,omitempty"`
+	Mode     string `json:"mode,omitempty"`
+	// Operation is the cli operation kind (i.e. "send-inputs") or the netconf rpc name (i.e.
+	// "edit-config").
+	Operation string   `json:"operation"`
+	Inputs    []string `json:"inputs,omitempty"`
+	// Payload is the rendered netconf rpc.
+	Payload string   `json:"payload,omitempty"`
+	Outputs []string `json:"outputs,omitempty"`
+	// OutputHashes hold the (hex) sha256 hashes of the outputs in place of the outputs when the
+	// sink is set to hash outputs, see WithOutputHashes.
+	OutputHashes    []string `json:"output_hashes,omitempty"`
+	Failed          bool     `json:"failed"`
+	FailedIndicator string   `json:"failed_indicator,omitempty"`
+	RPCErrors       []string `json:"rpc_errors,omitempty"`
+	// Error is the error the operation returned, if any.
+	Error          string  `json:"error,omitempty"`
+	ElapsedSeconds float64 `json:"elapsed_seconds"`
+	// DryRun is true if the operation was recorded rather than sent, see options.WithDryRun.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+type operatorCtxKey struct{}
+
+// WithOperator returns a context carrying the identity of the operator on whose behalf operations
+// executed with the context are executed, the identity ends up in the audit records of those
+// operations.
+func WithOperator(ctx context.Context, operator string) context.Context {
+	return context.WithValue(ctx, operatorCtxKey{}, operator)
+}
+
+// Operator returns the identity of the operator carried by ctx, see WithOperator.
+func Operator(ctx context.Context) string {
+	operator, _ := ctx.Value(operatorCtxKey{}).(string)
+
+	return operator
+}
+
+// Option is a func that configures a Sink.
+type Option func(s *Sink)
+
+// WithRedactInputs redacts the inputs and netconf payloads of records entirely.
+func WithRedactInputs() Option {
+	return func(s *Sink) {
+		s.redactInputs = true
+	}
+}
+
+// WithOutputHashes records (sha256) hashes of outputs rather than the outputs themselves, this
+// proves what a device returned without retaining (possibly sensitive) output.
+func WithOutputHashes() Option {
+	return func(s *Sink) {
+		s.hashOutputs = true
+	}
+}
+
+// WithRedactPatterns redacts anything the patterns match in the inputs, payloads, outputs, rpc
+// errors and errors of records. Outputs are hashed (see WithOutputHashes) before redaction.
+func WithRedactPatterns(patterns ...*regexp.Regexp) Option {
+	return func(s *Sink) {
+		s.redactPatterns = append(s.redactPatterns, patterns...)
+	}
+}
+
+// Sink writes audit records as json lines to a writer. It is independent of the logger of the
+// drivers, and a single Sink can (and generally should) be shared by all drivers.
+type Sink struct {
+	redactInputs   bool
+	hashOutputs    bool
+	redactPatterns []*regexp.Regexp
+
+	lock sync.Mutex
+	w    io.Writer
+}
+
+// NewSink returns a Sink writing to w, see NewRotatingFile for a rotating file writer.
+func NewSink(w io.Writer, opts ...Option) *Sink {
+	s := &Sink{
+		w: w,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Write redacts and writes the record as a single json line.
+func (s *Sink) Write(record *Record) error {
+	r := *record
+
+	r.Inputs = s.redactAll(r.Inputs, s.redactInputs)
+	r.Payload = s.redact(r.Payload, s.redactInputs)
+
+	if s.hashOutputs {
+		r.OutputHashes = make([]string, len(r.Outputs))
+
+		for idx, output := range r.Outputs {
+			sum := sha256.Sum256([]byte(output))
+
+			r.OutputHashes[idx] = hex.EncodeToString(sum[:])
+		}
+
+		r.Outputs = nil
+	}
+
+	r.Outputs = s.redactAll(r.Outputs, false)
+	r.RPCErrors = s.redactAll(r.RPCErrors, false)
+	r.Error = s.redact(r.Error, false)
+
+	b, err := json.Marshal(&r)
+	if err != nil {
+		return err
+	}
+
+	b = append(b, '\n')
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_, err = s.w.Write(b)
+
+	return err
+}
+
+func (s *Sink) redact(v string, all bool) string {
+	if v == "" {
+		return v
+	}
+
+	if all {
+		return redacted
+	}
+
+	for _, pattern := range s.redactPatterns {
+		v = pattern.ReplaceAllString(v, redacted)
+	}
+
+	return v
+}
+
+func (s *Sink) redactAll(vs []string, all bool) []string {
+	if len(vs) == 0 {
+		return vs
+	}
+
+	out := make([]string, len(vs))
+
+	for idx, v := range vs {
+		out[idx] = s.redact(v, all)
+	}
+
+	return out
+}
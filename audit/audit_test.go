@@ -0,0 +1,114 @@
+package audit_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	scrapligoaudit "github.com/scrapli/scrapligo/v2/audit"
+)
+
+func writeRecord(
+	t *testing.T,
+	record *scrapligoaudit.Record,
+	opts ...scrapligoaudit.Option,
+) *scrapligoaudit.Record {
+	t.Helper()
+
+	var b bytes.Buffer
+
+	err := scrapligoaudit.NewSink(&b, opts...).Write(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Count(b.Bytes(), []byte("\n")) != 1 {
+		t.Fatalf("expected a single json line, got %q", b.String())
+	}
+
+	var written scrapligoaudit.Record
+
+	err = json.Unmarshal(b.Bytes(), &written)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &written
+}
+
+func TestSinkWrite(t *testing.T) {
+	ctx := scrapligoaudit.WithOperator(context.Background(), "carl")
+
+	record := &scrapligoaudit.Record{
+		Operator:  scrapligoaudit.Operator(ctx),
+		Driver:    "cli",
+		Host:      "localhost",
+		Operation: "send-inputs",
+		Inputs:    []string{"username carl secret hunter2", "show version"},
+		Outputs:   []string{"", "version 1.2.3"},
+	}
+
+	cases := map[string]struct {
+		opts     []scrapligoaudit.Option
+		validate func(t *testing.T, r *scrapligoaudit.Record)
+	}{
+		"plain": {
+			validate: func(t *testing.T, r *scrapligoaudit.Record) {
+				t.Helper()
+
+				if r.Operator != "carl" || r.Inputs[0] != record.Inputs[0] ||
+					r.Outputs[1] != "version 1.2.3" {
+					t.Fatalf("unexpected record %+v", r)
+				}
+			},
+		},
+		"redact-inputs": {
+			opts: []scrapligoaudit.Option{scrapligoaudit.WithRedactInputs()},
+			validate: func(t *testing.T, r *scrapligoaudit.Record) {
+				t.Helper()
+
+				if r.Inputs[0] != "<redacted>" || r.Inputs[1] != "<redacted>" {
+					t.Fatalf("expected redacted inputs, got %v", r.Inputs)
+				}
+			},
+		},
+		"redact-patterns": {
+			opts: []scrapligoaudit.Option{
+				scrapligoaudit.WithRedactPatterns(regexp.MustCompile(`secret \S+`)),
+			},
+			validate: func(t *testing.T, r *scrapligoaudit.Record) {
+				t.Helper()
+
+				if r.Inputs[0] != "username carl <redacted>" || r.Inputs[1] != "show version" {
+					t.Fatalf("expected redacted secret, got %v", r.Inputs)
+				}
+			},
+		},
+		"output-hashes": {
+			opts: []scrapligoaudit.Option{scrapligoaudit.WithOutputHashes()},
+			validate: func(t *testing.T, r *scrapligoaudit.Record) {
+				t.Helper()
+
+				sum := sha256.Sum256([]byte("version 1.2.3"))
+
+				if r.Outputs != nil || r.OutputHashes[1] != hex.EncodeToString(sum[:]) {
+					t.Fatalf("expected output hashes only, got %+v", r)
+				}
+			},
+		},
+	}
+
+	for caseName, caseData := range cases {
+		t.Run(caseName, func(t *testing.T) {
+			caseData.validate(t, writeRecord(t, record, caseData.opts...))
+		})
+	}
+
+	if record.Inputs[0] != "username carl secret hunter2" {
+		t.Fatal("expected sink to not modify the record")
+	}
+}
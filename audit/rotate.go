@@ -0,0 +1,167 @@
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+)
+
+const auditFilePermissions = 0o600
+
+// RotatingFile is an io.WriteCloser appending to the file at a path that is rotated once it
+// reaches a maximum size -- the file is renamed to path.1 (path.1 to path.2 and so on) and a new
+// file is started, at most maxBackups rotated files are kept. A single write is never split across
+// files, so each (json line) record ends up whole in one file.
+type RotatingFile struct {
+	path       string
+	maxBytes   int64
+	maxBackups int
+
+	lock sync.Mutex
+	f    *os.File
+	size int64
+}
+
+// NewRotatingFile returns a RotatingFile appending to the file at path, rotating it once it
+// reaches maxBytes (zero never rotates).
+func NewRotatingFile(path string, maxBytes int64, maxBackups int) (*RotatingFile, error) {
+	f := &RotatingFile{
+		path:       path,
+		maxBytes:   maxBytes,
+		maxBackups: maxBackups,
+	}
+
+	err := f.open()
+	if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile( //nolint: gosec
+		f.path,
+		os.O_CREATE|os.O_WRONLY|os.O_APPEND,
+		auditFilePermissions,
+	)
+	if err != nil {
+		return scrapligoerrors.NewUtilError(
+			fmt.Sprintf("failed opening audit file at path %q", f.path),
+			err,
+		)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+
+		return scrapligoerrors.NewUtilError(
+			fmt.Sprintf("failed checking audit file at path %q", f.path),
+			err,
+		)
+	}
+
+	f.f = file
+	f.size = info.Size()
+
+	return nil
+}
+
+// rotate rotates the file, if rotating fails (after closing the file) the file at path is reopened
+// so that records keep being written, just not rotated.
+func (f *RotatingFile) rotate() error {
+	err := f.f.Close()
+
+	f.f = nil
+
+	if err != nil {
+		return f.reopenAfter(
+			scrapligoerrors.NewUtilError(
+				fmt.Sprintf("failed closing audit file at path %q", f.path),
+				err,
+			),
+		)
+	}
+
+	for idx := f.maxBackups - 1; idx > 0; idx-- {
+		err = os.Rename(fmt.Sprintf("%s.%d", f.path, idx), fmt.Sprintf("%s.%d", f.path, idx+1))
+		if err != nil && !os.IsNotExist(err) {
+			// backups that do not exist yet are... fine, anything else is not
+			return f.reopenAfter(
+				scrapligoerrors.NewUtilError(
+					fmt.Sprintf("failed rotating audit file backup at path %q.%d", f.path, idx),
+					err,
+				),
+			)
+		}
+	}
+
+	if f.maxBackups > 0 {
+		err = os.Rename(f.path, f.path+".1")
+	} else {
+		err = os.Remove(f.path)
+	}
+
+	if err != nil {
+		return f.reopenAfter(
+			scrapligoerrors.NewUtilError(
+				fmt.Sprintf("failed rotating audit file at path %q", f.path),
+				err,
+			),
+		)
+	}
+
+	return f.open()
+}
+
+// reopenAfter reopens the file at path after rotating failed with err, returning err (joined with
+// the error reopening, if that fails too).
+func (f *RotatingFile) reopenAfter(err error) error {
+	return errors.Join(err, f.open())
+}
+
+// Write writes b to the file, rotating it first if b would push it past the maximum size. If
+// rotating fails b is still written (to the unrotated file) and the rotation error is returned.
+func (f *RotatingFile) Write(b []byte) (int, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.f == nil {
+		return 0, scrapligoerrors.NewUtilError("audit file closed", nil)
+	}
+
+	var rotateErr error
+
+	if f.maxBytes > 0 && f.size > 0 && f.size+int64(len(b)) > f.maxBytes {
+		rotateErr = f.rotate()
+		if f.f == nil {
+			return 0, rotateErr
+		}
+	}
+
+	n, err := f.f.Write(b)
+
+	f.size += int64(n)
+
+	return n, errors.Join(rotateErr, err)
+}
+
+// Close closes the file.
+func (f *RotatingFile) Close() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.f == nil {
+		return nil
+	}
+
+	err := f.f.Close()
+
+	f.f = nil
+
+	return err
+}
@@ -0,0 +1,157 @@
+package audit_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	scrapligoaudit "github.com/scrapli/scrapligo/v2/audit"
+)
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	f, err := scrapligoaudit.NewRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{"one\n", "two\n", "three\n", "four\n", "five\n"} {
+		_, err = f.Write([]byte(line))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		path:        "four\nfive\n",
+		path + ".1": "three\n",
+		path + ".2": "one\ntwo\n",
+	}
+
+	for p, content := range expected {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(b) != content {
+			t.Fatalf("expected %q in %s, got %q", content, p, string(b))
+		}
+	}
+
+	_, err = os.Stat(path + ".3")
+	if !os.IsNotExist(err) {
+		t.Fatalf("expected at most 2 backups, got %v", err)
+	}
+
+	_, err = f.Write([]byte("six\n"))
+	if err == nil || !strings.Contains(err.Error(), "closed") {
+		t.Fatalf("expected write after close to fail, got %v", err)
+	}
+}
+
+func TestRotatingFileRotateFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	// a (non empty) directory in place of the backup the file is rotated to, so rotating fails
+	err := os.MkdirAll(filepath.Join(path+".1", "blocker"), 0o700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := scrapligoaudit.NewRotatingFile(path, 5, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	_, err = f.Write([]byte("one\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = f.Write([]byte("two\n"))
+	if err == nil || !strings.Contains(err.Error(), "failed rotating") {
+		t.Fatalf("expected rotating to fail, got %v", err)
+	}
+
+	err = os.RemoveAll(path + ".1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the file was reopened, so once rotating works again records are written and rotated as usual
+	_, err = f.Write([]byte("three\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		path:        "three\n",
+		path + ".1": "one\ntwo\n",
+	}
+
+	for p, content := range expected {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(b) != content {
+			t.Fatalf("expected %q in %s, got %q", content, p, string(b))
+		}
+	}
+}
+
+func TestRotatingFileRotateBackupFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	err := os.WriteFile(path+".1", []byte("zero\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a (non empty) directory in place of the oldest backup, so shifting the backups fails
+	err = os.MkdirAll(filepath.Join(path+".2", "blocker"), 0o700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := scrapligoaudit.NewRotatingFile(path, 5, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	_, err = f.Write([]byte("one\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = f.Write([]byte("two\n"))
+	if err == nil || !strings.Contains(err.Error(), "backup") {
+		t.Fatalf("expected rotating the backups to fail, got %v", err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != "one\ntwo\n" {
+		t.Fatalf("expected records to be kept in the unrotated file, got %q", string(b))
+	}
+}
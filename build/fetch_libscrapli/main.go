@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	scrapligoconstants "github.com/scrapli/scrapligo/v2/constants"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
+	scrapligoutil "github.com/scrapli/scrapligo/v2/util"
+)
+
+// pre-fetches (and verifies) libscrapli for one or more targets in to a directory, for example to
+// bake in to an image and point LIBSCRAPLI_BUNDLE_PATH at for air-gapped networks:
+// `go run ./build/fetch_libscrapli -out ./libscrapli -targets x86_64-linux-gnu,x86_64-linux-musl`.
+func main() {
+	out := flag.String("out", "libscrapli", "directory to write the libraries to")
+	targets := flag.String(
+		"targets",
+		strings.Join(scrapligoffi.LibscrapliTargets(), ","),
+		"comma separated target triples to fetch",
+	)
+	version := flag.String(
+		"version",
+		scrapligoutil.GetEnvStrOrDefault(
+			scrapligoconstants.LibScrapliVersionOverrideEnv,
+			scrapligoconstants.LibScrapliVersion,
+		),
+		"libscrapli version to fetch",
+	)
+
+	flag.Parse()
+
+	for _, target := range strings.Split(*targets, ",") {
+		p, err := scrapligoffi.FetchLibscrapli(
+			context.Background(),
+			*version,
+			strings.TrimSpace(target),
+			*out,
+		)
+		if err != nil {
+			panic(err)
+		}
+
+		fmt.Printf("libscrapli for %q is available at path %q\n", target, p) //nolint: forbidigo
+	}
+}
@@ -118,7 +118,7 @@ fi
 
 echo
 
-CHANGES=$(git diff -- constants/versions.go assets/definitions)
+CHANGES=$(git diff -- constants/versions.go assets/definitions assets/libscrapli)
 
 if [[ -z "$CHANGES" ]]; then
     echo "no changes to commit, exiting..."
@@ -0,0 +1,56 @@
+#!/bin/bash
+set -euo pipefail
+
+LIBSCRAPLI_TAG="${1:-}"
+
+if [[ -z "$LIBSCRAPLI_TAG" ]]; then
+    echo "error: libscrapli tag must be set"
+    exit 1
+fi
+
+LIBSCRAPLI_TAG="${LIBSCRAPLI_TAG#v}"
+
+CHECKSUMS_FILE="assets/libscrapli/checksums.json"
+
+TARGETS=(
+    "x86_64-linux-gnu"
+    "x86_64-linux-musl"
+    "aarch64-linux-gnu"
+    "aarch64-linux-musl"
+    "x86_64-macos"
+    "aarch64-macos"
+)
+
+TMP_DIR=$(mktemp -d)
+trap 'rm -rf "$TMP_DIR"' EXIT
+
+VERSION_CHECKSUMS='{}'
+
+for target in "${TARGETS[@]}"; do
+    if [[ "$target" == *-macos ]]; then
+        filename="libscrapli-${target}.${LIBSCRAPLI_TAG}.dylib"
+    else
+        filename="libscrapli-${target}.so.${LIBSCRAPLI_TAG}"
+    fi
+
+    echo "fetching ${filename}..."
+
+    curl -sfL \
+        -o "${TMP_DIR}/${filename}" \
+        "https://github.com/scrapli/libscrapli/releases/download/v${LIBSCRAPLI_TAG}/${filename}"
+
+    checksum=$(sha256sum "${TMP_DIR}/${filename}" | cut -d ' ' -f1)
+
+    VERSION_CHECKSUMS=$(
+        echo "$VERSION_CHECKSUMS" |
+            jq --arg f "$filename" --arg c "$checksum" '. + {($f): $c}'
+    )
+done
+
+jq \
+    --arg v "$LIBSCRAPLI_TAG" \
+    --argjson c "$VERSION_CHECKSUMS" \
+    '. + {($v): $c}' \
+    "$CHECKSUMS_FILE" >"${TMP_DIR}/checksums.json"
+
+mv "${TMP_DIR}/checksums.json" "$CHECKSUMS_FILE"
@@ -11,3 +11,5 @@ fi
 LIBSCRAPLI_TAG="${LIBSCRAPLI_TAG#v}"
 
 sed -i -E "s|(var LibScrapliVersion = )(.*)|\1\"${LIBSCRAPLI_TAG}\"|g" constants/versions.go
+
+./build/update_libscrapli_checksums.sh "$LIBSCRAPLI_TAG"
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	scrapligoaudit "github.com/scrapli/scrapligo/v2/audit"
+)
+
+// audit writes the audit record of the operation (sent in mode) to the audit sink, if any.
+func (c *Cli) audit(
+	ctx context.Context,
+	start time.Time,
+	operation *Operation,
+	mode string,
+	result *Result,
+	err error,
+) {
+	if c.options.Audit == nil {
+		return
+	}
+
+	record := &scrapligoaudit.Record{
+		Time:           start,
+		Operator:       scrapligoaudit.Operator(ctx),
+		Driver:         "cli",
+		Host:           operation.Host,
+		Port:           operation.Port,
+		Platform:       operation.Platform,
+		Mode:           mode,
+		Operation:      string(operation.Kind),
+		Inputs:         operation.Inputs,
+		ElapsedSeconds: time.Since(start).Seconds(),
+		DryRun:         c.dryRuns(operation, mode),
+	}
+
+	if result != nil {
+		record.Outputs = result.Results
+		record.Failed = result.Failed()
+		record.FailedIndicator = result.ResultsFailedIndicator
+	}
+
+	if err != nil {
+		record.Failed = true
+		record.Error = err.Error()
+	}
+
+	auditErr := c.options.Audit.Write(record)
+	if auditErr != nil {
+		c.l.Warn(fmt.Sprintf("failed writing audit record: %v", auditErr))
+	}
+}
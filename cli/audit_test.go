@@ -0,0 +1,92 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	scrapligoaudit "github.com/scrapli/scrapligo/v2/audit"
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligooptions "github.com/scrapli/scrapligo/v2/options"
+	scrapligopolicy "github.com/scrapli/scrapligo/v2/policy"
+)
+
+func TestAudit(t *testing.T) {
+	testFixturePath, err := filepath.Abs("./fixtures/send-input-simple")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	ctx = scrapligoaudit.WithOperator(ctx, "carl")
+
+	policy, err := scrapligopolicy.NewPolicy(
+		scrapligopolicy.Allow,
+		scrapligopolicy.Rule{Action: scrapligopolicy.Deny, Pattern: `^reload`},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+
+	c := getCli(
+		t,
+		testFixturePath,
+		scrapligooptions.WithAuditSink(scrapligoaudit.NewSink(&b)),
+		scrapligooptions.WithPolicy(policy),
+	)
+
+	_, err = c.Open(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_, _ = c.Close(ctx)
+	}()
+
+	_, err = c.SendInput(ctx, "reload")
+	if !errors.Is(err, scrapligoerrors.ErrPolicyDenied) {
+		t.Fatalf("expected ErrPolicyDenied, got %v", err)
+	}
+
+	r, err := c.SendInput(ctx, "show version | i Kern")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 audit records, got %d", len(lines))
+	}
+
+	records := make([]scrapligoaudit.Record, len(lines))
+
+	for idx, line := range lines {
+		err = json.Unmarshal([]byte(line), &records[idx])
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if !records[0].Failed || !strings.Contains(records[0].Error, "denied by policy") {
+		t.Fatalf("expected denied record, got %+v", records[0])
+	}
+
+	sent := records[1]
+
+	// no mode was requested, so the input was sent in the default mode the session opened in
+	if sent.Operator != "carl" || sent.Operation != "send-input" ||
+		sent.Mode != "privileged_exec" || sent.Platform != "arista_eos" || sent.Failed ||
+		sent.Inputs[0] != "show version | i Kern" || sent.Outputs[0] != r.Result() {
+		t.Fatalf("unexpected record %+v", sent)
+	}
+}
@@ -2,7 +2,6 @@ package cli
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -15,9 +14,10 @@ import (
 	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
 	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
 	scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
+	scrapligogobackend "github.com/scrapli/scrapligo/v2/internal/gobackend"
 	scrapligologging "github.com/scrapli/scrapligo/v2/logging"
+	scrapligometrics "github.com/scrapli/scrapligo/v2/metrics"
 	scrapligooptions "github.com/scrapli/scrapligo/v2/options"
-	"golang.org/x/sys/unix"
 )
 
 func loadDefinition(o *scrapligointernal.Options) error {
@@ -75,10 +75,24 @@ type Cli struct {
 	ptr      uintptr
 	userData uintptr
 	pollFd   int
+	readyFd  *scrapligointernal.ReadyFd
 	ffiMap   *scrapligoffi.Mapping
 	host     string
 	options  *scrapligointernal.Options
 	l        *scrapligologging.AnyLogger
+
+	interceptors []Interceptor
+
+	// defaultMode is the default mode of the definition, mode is the mode the session is known to
+	// be in (if any), see sessionMode
+	defaultMode string
+	modeLock    sync.Mutex
+	mode        string
+
+	// queueLock guards queueTail, the channel closed when the most recently queued operation is
+	// done, see enqueue
+	queueLock sync.Mutex
+	queueTail chan struct{}
 }
 
 // NewCli returns a new instance of Cli setup with the given options.
@@ -86,20 +100,14 @@ func NewCli(
 	host string,
 	opts ...scrapligooptions.Option,
 ) (*Cli, error) {
-	ffiMap, err := scrapligoffi.GetMapping()
-	if err != nil {
-		return nil, err
-	}
-
 	c := &Cli{
 		userData: scrapligointernal.GetUserDataDispatcherr().Register(),
-		ffiMap:   ffiMap,
 		host:     host,
 		options:  scrapligointernal.NewOptions(),
 	}
 
 	for _, opt := range opts {
-		err = opt(c.options)
+		err := opt(c.options)
 		if err != nil {
 			return nil, scrapligoerrors.NewOptionsError("failed applying option", err)
 		}
@@ -136,13 +144,26 @@ func NewCli(
 		// we apply those options. obviously this can be skipped with the appropriate option.
 		for _, opt := range scrapligoclidefinitionoptions.GetPlatformOptions().
 			GetOptionsForPlatform(c.options.Cli.DefinitionPlatform) {
-			err = opt(c.options)
+			err := opt(c.options)
 			if err != nil {
 				return nil, scrapligoerrors.NewOptionsError("failed applying (static) option", err)
 			}
 		}
 	}
 
+	// the mapping depends on the options -- the go backend binds the mapping to this driver's
+	// options rather than loading libscrapli
+	ffiMap, err := scrapligogobackend.GetMapping(c.options)
+	if err != nil {
+		return nil, err
+	}
+
+	c.ffiMap = ffiMap
+
+	c.loadInterceptors()
+
+	c.defaultMode = definitionDefaultMode(c.options.Cli.DefinitionString)
+
 	return c, nil
 }
 
@@ -190,7 +211,31 @@ func (c *Cli) GetOptions() (string, error) {
 // Open opens the driver object. This method spawns the underlying zig driver which the Cli then
 // holds a pointer to. All Cli operations operate against this pointer (though this is
 // transparent to the user).
+// If credential providers are set (see options.WithCredentialProvider) the credential sets they
+// supply are tried in order until one authenticates.
 func (c *Cli) Open(ctx context.Context) (*Result, error) {
+	return c.instrument(
+		ctx,
+		scrapligometrics.OperationOpen,
+		nil,
+		func(ctx context.Context) (*Result, error) {
+			return scrapligointernal.OpenWithCredentials(ctx, c.options, c.host, c.open)
+		},
+	)
+}
+
+func (c *Cli) open(ctx context.Context) (*Result, error) {
+	ctx, release, err := c.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer release()
+
+	// whatever mode a previous session was in, nothing is known about the mode of a new one until
+	// it is opened (and so has acquired the default mode)
+	c.setSessionMode("")
+
 	// ensure we dealloc if something happens, otherwise users calls to defer close would not be
 	// super handy
 	cleanup := true
@@ -203,6 +248,8 @@ func (c *Cli) Open(ctx context.Context) (*Result, error) {
 		scrapligointernal.GetLoggerDispatcher().Deregister(c.userData)
 		scrapligointernal.GetRecorderDispatcher().Deregister(c.userData)
 
+		c.closeReadyFd()
+
 		c.ffiMap.Shared.Free(c.ptr)
 
 		c.ptr = 0
@@ -211,7 +258,7 @@ func (c *Cli) Open(ctx context.Context) (*Result, error) {
 	optionsPtr := c.ffiMap.Shared.AllocDriverOptions()
 	defer c.ffiMap.Shared.FreeDriverOptions(optionsPtr)
 
-	err := c.options.Apply(c.userData, optionsPtr)
+	err = c.options.Apply(c.userData, optionsPtr)
 	if err != nil {
 		return nil, err
 	}
@@ -230,50 +277,75 @@ func (c *Cli) Open(ctx context.Context) (*Result, error) {
 		return nil, scrapligoerrors.NewFfiError("failed to allocate cli", nil)
 	}
 
-	cancel := false
+	c.readyFd, err = scrapligointernal.NewReadyFd(c.pollFd)
+	if err != nil {
+		return nil, scrapligoerrors.NewFfiError("failed to allocate cli", err)
+	}
+
+	cancel := scrapligoffi.NewCancelFlag()
 
 	var operationID uint32
 
-	err = c.ffiMap.Cli.Open(c.ptr, &operationID, &cancel)
+	err = c.ffiMap.Cli.Open(c.ptr, &operationID, cancel)
 	if err != nil {
 		return nil, err
 	}
 
-	result, err := c.getResult(ctx, &cancel, operationID)
+	result, err := c.getResult(ctx, cancel, operationID)
 	if err != nil {
 		return nil, err
 	}
 
 	cleanup = false
 
+	c.setSessionMode(c.defaultMode)
+
+	c.sessionOpened()
+
 	return result, nil
 }
 
 // Close closes the driver object. This also deallocates the underlying (zig) driver object.
 func (c *Cli) Close(ctx context.Context) (*Result, error) {
+	return c.instrument(ctx, scrapligometrics.OperationClose, nil, c.close)
+}
+
+func (c *Cli) close(ctx context.Context) (*Result, error) {
+	// wait for any queued operations to complete before tearing things down
+	ctx, release, err := c.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer release()
+
 	if c.ptr == 0 {
 		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
 	}
 
 	defer func() {
+		c.sessionClosed()
+
 		scrapligointernal.GetLoggerDispatcher().Deregister(c.userData)
 		scrapligointernal.GetRecorderDispatcher().Deregister(c.userData)
 
+		c.closeReadyFd()
+
 		c.ffiMap.Shared.Free(c.ptr)
 
 		c.ptr = 0
 	}()
 
-	cancel := false
+	cancel := scrapligoffi.NewCancelFlag()
 
 	var operationID uint32
 
-	err := c.ffiMap.Cli.Close(c.ptr, &operationID, &cancel)
+	err = c.ffiMap.Cli.Close(c.ptr, &operationID, cancel)
 	if err != nil {
 		return nil, err
 	}
 
-	return c.getResult(ctx, &cancel, operationID)
+	return c.getResult(ctx, cancel, operationID)
 }
 
 // ReplaceDefinition replaces the "definition" of the driver. Most importantly changes/updates
@@ -293,91 +365,34 @@ func (c *Cli) ReplaceDefinition(definitionFileOrString string) error {
 	return c.ffiMap.Cli.ReplaceDefinition(c.ptr, c.options.Cli.DefinitionString)
 }
 
-func (c *Cli) getResult( //nolint: funlen,gocyclo
+func (c *Cli) closeReadyFd() {
+	if c.readyFd == nil {
+		return
+	}
+
+	// nothing useful to do with a close error, the driver is going away regardless
+	_ = c.readyFd.Close()
+
+	c.readyFd = nil
+}
+
+func (c *Cli) getResult(
 	ctx context.Context,
-	cancel *bool,
+	cancel *scrapligoffi.CancelFlag,
 	operationID uint32,
 ) (*Result, error) {
-	done := make(chan struct{}, 1)
-	defer close(done)
-
 	var operationCount uint32
 
-	cancelLock := &sync.Mutex{}
-
 	// so in go flavor we actually use ctx to cause libscrapli to timeout vs python where we rely on
-	// the timeouts in libscrapli itself. so in this case we need to ensure that we do not block the
-	// context so it can properly cancel on timeout/cancellation...
-	go func() {
-		select {
-		case <-ctx.Done():
-			cancelLock.Lock()
-			defer cancelLock.Unlock()
-
-			*cancel = true
-
-			return
-		case <-done:
-			return
-		}
-	}()
-
-	var n int
-
-	pollFds := []unix.PollFd{{Fd: int32(c.pollFd), Events: unix.POLLIN}} //nolint: gosec
-
-	for {
-		if ctx.Err() != nil {
-			cancelLock.Lock()
-
-			*cancel = true
-
-			cancelLock.Unlock()
-
-			return nil, ctx.Err()
-		}
-
-		pollFds[0].Revents = 0
+	// the timeouts in libscrapli itself. once cancelled we still wait for libscrapli to signal the
+	// operation is done -- otherwise the ready signal for this operation would be left for whatever
+	// operation is next in the queue, libscrapli checks the cancel flag so this is short lived
+	stop := context.AfterFunc(ctx, cancel.Cancel)
+	defer stop()
 
-		var err error
-
-		n, err = unix.Poll(pollFds, scrapligoconstants.ReadyFDPollTimeoutMs)
-		if err != nil {
-			if errors.Is(err, unix.EINTR) {
-				// python automagically handles interrupts i guess go doesnt, so just act like
-				// we do on the python side when polling the wakeup fd
-				continue
-			}
-
-			return nil, scrapligoerrors.NewFfiError("waiting on operation ready signal", err)
-		}
-
-		if n > 0 {
-			if pollFds[0].Revents&unix.POLLNVAL != 0 {
-				return nil, scrapligoerrors.NewFfiError(
-					"waiting on operation ready signal",
-					unix.EBADF,
-				)
-			}
-
-			break
-		}
-	}
-
-	var out [1]byte
-
-	for {
-		_, err := unix.Read(c.pollFd, out[:])
-		if err == nil {
-			break
-		}
-
-		if errors.Is(err, unix.EINTR) {
-			// same as loop above -- retry on interrupts
-			continue
-		}
-
-		return nil, scrapligoerrors.NewFfiError("draining operation ready signal", err)
+	err := c.readyFd.Wait()
+	if err != nil {
+		return nil, scrapligoerrors.NewFfiError("waiting on operation ready signal", err)
 	}
 
 	var (
@@ -389,7 +404,7 @@ func (c *Cli) getResult( //nolint: funlen,gocyclo
 		lastErrStrSize             uintptr
 	)
 
-	err := c.ffiMap.Cli.FetchOperationSizes(
+	err = c.ffiMap.Cli.FetchOperationSizes(
 		c.ptr,
 		operationID,
 		&operationCount,
@@ -408,17 +423,22 @@ func (c *Cli) getResult( //nolint: funlen,gocyclo
 
 	splits := make([]uint64, operationCount)
 
+	// inputs, results and raw results end up owned by the Result so they are allocated exactly
+	// once here (and never copied again), the rest is scratch space so comes from the pool
 	inputs := make([]byte, inputsSize)
 
 	resultsRaw := make([]byte, resultsRawSize)
 
 	results := make([]byte, resultsSize)
 
-	resultsFailedWhenIndicator := make([]byte, resultsFailedIndicatorSize)
+	resultsFailedWhenIndicator := scrapligointernal.GetBuffer(resultsFailedIndicatorSize)
+	defer scrapligointernal.PutBuffer(resultsFailedWhenIndicator)
 
-	errString := make([]byte, errSize)
+	errString := scrapligointernal.GetBuffer(errSize)
+	defer scrapligointernal.PutBuffer(errString)
 
-	lastErrString := make([]byte, lastErrStrSize)
+	lastErrString := scrapligointernal.GetBuffer(lastErrStrSize)
+	defer scrapligointernal.PutBuffer(lastErrString)
 
 	err = c.ffiMap.Cli.FetchOperation(
 		c.ptr,
@@ -428,24 +448,24 @@ func (c *Cli) getResult( //nolint: funlen,gocyclo
 		&inputs,
 		&resultsRaw,
 		&results,
-		&resultsFailedWhenIndicator,
-		&errString,
-		&lastErrString,
+		resultsFailedWhenIndicator,
+		errString,
+		lastErrString,
 	)
 	if err != nil {
 		return nil, err
 	}
 
 	if errSize != 0 {
-		// always wrap the context error (even if nil) so we catch cancels/deadline exceeded and
+		// always wrap the context cause (even if nil) so we catch cancels/deadline exceeded and
 		// users can errors.Is with that
-		outErrMsg := string(errString)
+		outErrMsg := string(*errString)
 
 		if lastErrStrSize > 0 {
-			outErrMsg += fmt.Sprintf(": %s", string(lastErrString))
+			outErrMsg += fmt.Sprintf(": %s", string(*lastErrString))
 		}
 
-		return nil, scrapligoerrors.NewFfiError(outErrMsg, ctx.Err())
+		return nil, scrapligoerrors.NewOperationError(outErrMsg, context.Cause(ctx))
 	}
 
 	return NewResult(
@@ -456,6 +476,6 @@ func (c *Cli) getResult( //nolint: funlen,gocyclo
 		splits,
 		resultsRaw,
 		results,
-		resultsFailedWhenIndicator,
+		*resultsFailedWhenIndicator,
 	), nil
 }
@@ -27,6 +27,11 @@ func TestMain(m *testing.M) {
 
 	exitCode := m.Run()
 
+	if scrapligotesthelper.GoBackend {
+		// libscrapli is never loaded w/ the go backend, so there is nothing to check for leaks
+		os.Exit(exitCode)
+	}
+
 	if scrapligoffi.AssertNoLeaks() != nil {
 		_, _ = fmt.Fprintln(os.Stderr, "memory leak(s) detected!")
 
@@ -195,7 +200,11 @@ func TestLoggerCallbackLimit(t *testing.T) {
 	}
 }
 
-func getCli(t *testing.T, f string) *scrapligocli.Cli {
+func getCli(
+	t testing.TB,
+	f string,
+	extraOpts ...scrapligooptions.Option,
+) *scrapligocli.Cli {
 	t.Helper()
 
 	opts := []scrapligooptions.Option{
@@ -205,6 +214,8 @@ func getCli(t *testing.T, f string) *scrapligocli.Cli {
 		scrapligooptions.WithDefinitionFileOrName(scrapligocli.AristaEos),
 	}
 
+	opts = append(opts, extraOpts...)
+
 	if *scrapligotesthelper.Record {
 		opts = append(
 			opts,
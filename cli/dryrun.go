@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"strings"
+	"time"
+
+	scrapligoconstants "github.com/scrapli/scrapligo/v2/constants"
+	scrapligodryrun "github.com/scrapli/scrapligo/v2/dryrun"
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligoutil "github.com/scrapli/scrapligo/v2/util"
+)
+
+// sendsInputs returns true if the operation sends inputs that dry-run mode may record rather than
+// execute.
+func sendsInputs(operation *Operation) bool {
+	switch operation.Kind { //nolint: exhaustive
+	case OperationKindSendInput, OperationKindSendInputs, OperationKindSendPromptedInput:
+		return true
+	default:
+		return false
+	}
+}
+
+// checkDryRunMode refuses operations in dry-run mode whose mode is not known, there is no telling
+// if their inputs would change configuration or not.
+func (c *Cli) checkDryRunMode(operation *Operation, mode string) error {
+	if c.options.DryRun.Plan == nil || !sendsInputs(operation) || mode != "" {
+		return nil
+	}
+
+	return scrapligoerrors.NewOptionsError(
+		"the mode of the session is not known, dry-run operations must request a mode (or the "+
+			"mode must be entered with EnterMode first)",
+		nil,
+	)
+}
+
+// dryRuns returns true if the operation is recorded rather than executed -- the Cli is in dry-run
+// mode and the operation sends inputs in a configuration mode.
+func (c *Cli) dryRuns(operation *Operation, mode string) bool {
+	if c.options.DryRun.Plan == nil {
+		return false
+	}
+
+	return sendsInputs(operation) && c.options.DryRun.IsConfigMode(mode)
+}
+
+// dryRun records the operation in the dry-run plan and returns a synthetic Result holding an
+// empty result for each input.
+func (c *Cli) dryRun(operation *Operation, mode string) *Result {
+	c.options.DryRun.Plan.Record(&scrapligodryrun.Step{
+		Time:      time.Now(),
+		Driver:    "cli",
+		Host:      operation.Host,
+		Port:      operation.Port,
+		Platform:  operation.Platform,
+		Mode:      mode,
+		Operation: string(operation.Kind),
+		Inputs:    operation.Inputs,
+	})
+
+	now := scrapligoutil.SafeInt64ToUint64(time.Now().UnixNano())
+
+	splits := make([]uint64, len(operation.Inputs))
+	for idx := range splits {
+		splits[idx] = now
+	}
+
+	results := strings.Repeat(
+		scrapligoconstants.LibScrapliDelimiter,
+		max(len(operation.Inputs)-1, 0),
+	)
+
+	return NewResult(
+		operation.Host,
+		operation.Port,
+		[]byte(strings.Join(operation.Inputs, scrapligoconstants.LibScrapliDelimiter)),
+		now,
+		splits,
+		[]byte(results),
+		[]byte(results),
+		nil,
+	)
+}
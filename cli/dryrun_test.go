@@ -0,0 +1,69 @@
+package cli_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	scrapligocli "github.com/scrapli/scrapligo/v2/cli"
+	scrapligodryrun "github.com/scrapli/scrapligo/v2/dryrun"
+	scrapligooptions "github.com/scrapli/scrapligo/v2/options"
+)
+
+func TestDryRun(t *testing.T) {
+	testFixturePath, err := filepath.Abs("./fixtures/send-input-simple")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	plan := scrapligodryrun.NewPlan()
+
+	c := getCli(t, testFixturePath, scrapligooptions.WithDryRun(plan))
+
+	_, err = c.Open(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_, _ = c.Close(ctx)
+	}()
+
+	inputs := []string{"interface loopback0", "description dry-run"}
+
+	r, err := c.SendInputs(ctx, inputs, scrapligocli.WithRequestedMode("configuration"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(r.Inputs) != len(inputs) || len(r.Results) != len(inputs) {
+		t.Fatalf("expected synthetic result for each input, got %v", r.Inputs)
+	}
+
+	// the session is in the default mode (privileged_exec) once opened, and the recorded inputs
+	// were never sent, so read-only input w/out a requested mode is sent as usual -- the fixture
+	// holds nothing but this input
+	r, err = c.SendInput(ctx, "show version | i Kern")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Result() == "" {
+		t.Fatal("expected read-only input to be sent, got an empty result")
+	}
+
+	steps := plan.Steps()
+
+	if len(steps) != 1 {
+		t.Fatalf("expected 1 recorded step, got %d", len(steps))
+	}
+
+	if steps[0].Mode != "configuration" || steps[0].Platform != "arista_eos" ||
+		len(steps[0].Inputs) != len(inputs) {
+		t.Fatalf("unexpected recorded step %+v", steps[0])
+	}
+}
@@ -4,23 +4,45 @@ import (
 	"context"
 
 	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
+	scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
+	scrapligotracing "github.com/scrapli/scrapligo/v2/tracing"
 )
 
 // EnterMode is used to explicitly enter a mode (i.e. enter "config mode" or "shell" or some other
 // platform specific "mode").
 func (c *Cli) EnterMode(ctx context.Context, requestedMode string) (*Result, error) {
-	if c.ptr == 0 {
-		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
+	operation := c.newOperation(OperationKindEnterMode, nil, nil)
+	operation.RequestedMode = requestedMode
+
+	return c.submit(ctx, c.intercept(operation, c.enterMode)).Wait(ctx)
+}
+
+func (c *Cli) enterMode(
+	ctx context.Context,
+	op *OperationHandle,
+	operation *Operation,
+) (*Result, error) {
+	spanAttrs := []scrapligotracing.Attribute{
+		scrapligointernal.TraceAttributeMode.String(operation.RequestedMode),
 	}
 
-	cancel := false
+	return c.instrument(ctx, "enter-mode", spanAttrs, func(ctx context.Context) (*Result, error) {
+		if c.ptr == 0 {
+			return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
+		}
 
-	var operationID uint32
+		cancel := scrapligoffi.NewCancelFlag()
 
-	err := c.ffiMap.Cli.EnterMode(c.ptr, &operationID, &cancel, requestedMode)
-	if err != nil {
-		return nil, err
-	}
+		var operationID uint32
+
+		err := c.ffiMap.Cli.EnterMode(c.ptr, &operationID, cancel, operation.RequestedMode)
+		if err != nil {
+			return nil, err
+		}
+
+		op.setID(operationID)
 
-	return c.getResult(ctx, &cancel, operationID)
+		return c.getResult(ctx, cancel, operationID)
+	})
 }
@@ -4,22 +4,27 @@ import (
 	"context"
 
 	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
 )
 
 // GetPrompt returns a Result object containing the current "prompt" of the target device.
 func (c *Cli) GetPrompt(ctx context.Context) (*Result, error) {
-	if c.ptr == 0 {
-		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
-	}
+	return c.submit(ctx, func(ctx context.Context, op *OperationHandle) (*Result, error) {
+		if c.ptr == 0 {
+			return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
+		}
 
-	cancel := false
+		cancel := scrapligoffi.NewCancelFlag()
 
-	var operationID uint32
+		var operationID uint32
 
-	err := c.ffiMap.Cli.GetPrompt(c.ptr, &operationID, &cancel)
-	if err != nil {
-		return nil, err
-	}
+		err := c.ffiMap.Cli.GetPrompt(c.ptr, &operationID, cancel)
+		if err != nil {
+			return nil, err
+		}
 
-	return c.getResult(ctx, &cancel, operationID)
+		op.setID(operationID)
+
+		return c.getResult(ctx, cancel, operationID)
+	}).Wait(ctx)
 }
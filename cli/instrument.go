@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"context"
+	"time"
+
+	scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
+	scrapligometrics "github.com/scrapli/scrapligo/v2/metrics"
+	scrapligotracing "github.com/scrapli/scrapligo/v2/tracing"
+)
+
+// instrument executes f in a span named for the operation and reports the operation to the
+// metrics recorder (if any). The span is a child of whatever span is in ctx and f is given the
+// span's context.
+func (c *Cli) instrument(
+	ctx context.Context,
+	operation string,
+	attrs []scrapligotracing.Attribute,
+	f func(ctx context.Context) (*Result, error),
+) (*Result, error) {
+	ctx, span := c.options.StartSpan(
+		ctx,
+		"cli."+operation,
+		c.host,
+		time.Time{},
+		append(
+			attrs,
+			scrapligointernal.TraceAttributePlatform.String(c.options.Cli.DefinitionPlatform),
+		)...,
+	)
+
+	start := time.Now()
+
+	result, err := f(ctx)
+
+	if result != nil {
+		span.SetAttributes(
+			scrapligointernal.TraceAttributeResultBytes.Int(result.resultBytes()),
+			scrapligointernal.TraceAttributeFailed.Bool(result.Failed()),
+		)
+
+		if result.Failed() {
+			span.SetAttributes(
+				scrapligointernal.TraceAttributeFailedIndicator.String(
+					result.ResultsFailedIndicator,
+				),
+			)
+		}
+	}
+
+	span.End(err)
+
+	c.observe(operation, start, result, err)
+
+	return result, err
+}
+
+// observe reports an operation that began at start to the metrics recorder.
+func (c *Cli) observe(operation string, start time.Time, result *Result, err error) {
+	if c.options.Metrics == nil {
+		return
+	}
+
+	op := &scrapligometrics.Operation{
+		Driver:   scrapligometrics.Cli,
+		Host:     c.host,
+		Port:     c.options.Port,
+		Platform: c.options.Cli.DefinitionPlatform,
+		Name:     operation,
+		Duration: time.Since(start),
+		Err:      err,
+	}
+
+	if result != nil {
+		if len(result.Splits) > 0 {
+			op.Duration = result.EndTime().Sub(result.StartTime)
+		}
+
+		op.InputBytes = result.inputBytes()
+		op.ResultBytes = result.resultBytes()
+		op.Failed = result.Failed()
+	}
+
+	c.options.Metrics.ObserveOperation(op)
+}
+
+func (c *Cli) sessionOpened() {
+	if c.options.Metrics != nil {
+		c.options.Metrics.SessionOpened(scrapligometrics.Cli, c.host)
+	}
+}
+
+func (c *Cli) sessionClosed() {
+	if c.options.Metrics != nil {
+		c.options.Metrics.SessionClosed(scrapligometrics.Cli, c.host)
+	}
+}
@@ -0,0 +1,158 @@
+package cli_test
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
+	scrapligometrics "github.com/scrapli/scrapligo/v2/metrics"
+	scrapligooptions "github.com/scrapli/scrapligo/v2/options"
+	scrapligotracing "github.com/scrapli/scrapligo/v2/tracing"
+	scrapligotracingotel "github.com/scrapli/scrapligo/v2/tracing/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func spanAttribute(
+	span sdktrace.ReadOnlySpan,
+	key scrapligotracing.Key,
+) (attribute.Value, bool) {
+	for _, kv := range span.Attributes() {
+		if kv.Key == attribute.Key(key) {
+			return kv.Value, true
+		}
+	}
+
+	return attribute.Value{}, false
+}
+
+type testRecorder struct {
+	lock       sync.Mutex
+	operations []*scrapligometrics.Operation
+	sessions   int
+}
+
+func (r *testRecorder) ObserveOperation(op *scrapligometrics.Operation) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.operations = append(r.operations, op)
+}
+
+func (r *testRecorder) SessionOpened(_ scrapligometrics.DriverKind, _ string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.sessions++
+}
+
+func (r *testRecorder) SessionClosed(_ scrapligometrics.DriverKind, _ string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.sessions--
+}
+
+func TestInstrumentation(t *testing.T) {
+	testFixturePath, err := filepath.Abs("./fixtures/send-input-simple")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ctx, parent := provider.Tracer("test").Start(ctx, "parent")
+
+	recorder := &testRecorder{}
+
+	c := getCli(
+		t,
+		testFixturePath,
+		scrapligooptions.WithTracer(scrapligotracingotel.NewTracer(provider)),
+		scrapligooptions.WithTracingRedactInputs(),
+		scrapligooptions.WithMetricsRecorder(recorder),
+	)
+
+	_, err = c.Open(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if recorder.sessions != 1 {
+		t.Fatalf("expected 1 active session after open, got %d", recorder.sessions)
+	}
+
+	r, err := c.SendInput(ctx, "show version | i Kern")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = c.Close(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parent.End()
+
+	if recorder.sessions != 0 {
+		t.Fatalf("expected 0 active sessions after close, got %d", recorder.sessions)
+	}
+
+	if len(recorder.operations) != 3 || recorder.operations[1].Name != "send-input" {
+		t.Fatalf("expected open, send-input and close operations, got %v", recorder.operations)
+	}
+
+	if recorder.operations[1].ResultBytes != len(r.ResultRaw()) ||
+		recorder.operations[1].Duration != r.EndTime().Sub(r.StartTime) {
+		t.Fatalf("expected operation stats from the result, got %+v", recorder.operations[1])
+	}
+
+	spans := exporter.GetSpans().Snapshots()
+
+	expectedNames := []string{"cli.open", "cli.send-input", "cli.close", "parent"}
+
+	if len(spans) != len(expectedNames) {
+		t.Fatalf("expected %d spans, got %d", len(expectedNames), len(spans))
+	}
+
+	for idx, span := range spans {
+		if span.Name() != expectedNames[idx] {
+			t.Fatalf("expected span %d to be %q, got %q", idx, expectedNames[idx], span.Name())
+		}
+
+		if span.Name() != "parent" &&
+			span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Fatalf("expected span %q to be a child of the callers span", span.Name())
+		}
+	}
+
+	sendInputSpan := spans[1]
+
+	input, _ := spanAttribute(sendInputSpan, scrapligointernal.TraceAttributeInput)
+	if input.AsString() != "<redacted>" {
+		t.Fatalf("expected redacted input, got %q", input.AsString())
+	}
+
+	inputBytes, _ := spanAttribute(sendInputSpan, scrapligointernal.TraceAttributeInputBytes)
+	if inputBytes.AsInt64() != int64(len("show version | i Kern")) {
+		t.Fatalf("expected input byte count of the actual input, got %d", inputBytes.AsInt64())
+	}
+
+	resultBytes, _ := spanAttribute(sendInputSpan, scrapligointernal.TraceAttributeResultBytes)
+	if resultBytes.AsInt64() != int64(len(r.ResultRaw())) {
+		t.Fatalf("expected result byte count %d, got %d", len(r.ResultRaw()), resultBytes.AsInt64())
+	}
+
+	platform, _ := spanAttribute(sendInputSpan, scrapligointernal.TraceAttributePlatform)
+	if platform.AsString() != "arista_eos" {
+		t.Fatalf("expected platform attribute, got %q", platform.AsString())
+	}
+}
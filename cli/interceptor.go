@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"context"
+	"time"
+
+	scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
+	scrapligooptions "github.com/scrapli/scrapligo/v2/options"
+)
+
+// OperationKind is an enum(ish) representing the kind of a Cli operation passed through the
+// interceptor chain.
+type OperationKind string
+
+const (
+	// OperationKindSendInput represents SendInput(Async).
+	OperationKindSendInput OperationKind = "send-input"
+	// OperationKindSendInputs represents SendInputs(Async) (and SendInputsFromFile).
+	OperationKindSendInputs OperationKind = "send-inputs"
+	// OperationKindSendPromptedInput represents SendPromptedInput.
+	OperationKindSendPromptedInput OperationKind = "send-prompted-input"
+	// OperationKindEnterMode represents EnterMode.
+	OperationKindEnterMode OperationKind = "enter-mode"
+	// OperationKindReadWithCallbacks represents ReadWithCallbacks(Async).
+	OperationKindReadWithCallbacks OperationKind = "read-with-callbacks"
+)
+
+// Operation describes a Cli operation as it passes through the interceptor chain. Interceptors
+// may modify the Inputs, RequestedMode, Response and Options of the operation before invoking the
+// rest of the chain, the operation is executed with whatever values it holds at the end of the
+// chain.
+type Operation struct {
+	Kind     OperationKind
+	Host     string
+	Port     uint16
+	Platform string
+	// Inputs holds the input(s) of the operation -- a single input for SendInput and
+	// SendPromptedInput, all inputs for SendInputs, the initial input (if any) for
+	// ReadWithCallbacks and nothing for EnterMode.
+	Inputs []string
+	// RequestedMode is the mode the operation executes in -- the requested mode option for send
+	// operations, the mode to enter for EnterMode.
+	RequestedMode string
+	// Prompt and Response are the prompt and response of SendPromptedInput.
+	Prompt   string
+	Response string
+	// Options are the operation options, appended options are applied after the original ones.
+	Options []Option
+}
+
+// Invoker executes an operation -- the next interceptor in the chain, or, at the end of the
+// chain, the operation itself.
+type Invoker func(ctx context.Context, operation *Operation) (*Result, error)
+
+// Interceptor wraps the execution of Cli operations. An interceptor can inspect (and modify) the
+// operation before calling next, inspect (and modify) the Result/error next returned, or not call
+// next at all to short-circuit the operation -- in which case nothing is sent to the device and
+// whatever the interceptor returns is the result of the operation. Interceptors execute in the
+// order given, with the operation holding its spot in the Cli's operation queue.
+type Interceptor func(ctx context.Context, operation *Operation, next Invoker) (*Result, error)
+
+// WithInterceptors appends the interceptors to the interceptor chain of the Cli.
+func WithInterceptors(interceptors ...Interceptor) scrapligooptions.Option {
+	return func(o *scrapligointernal.Options) error {
+		for _, interceptor := range interceptors {
+			o.Cli.Interceptors = append(o.Cli.Interceptors, interceptor)
+		}
+
+		return nil
+	}
+}
+
+// loadInterceptors builds the interceptor chain from the options, the options can only hold the
+// interceptors as "any" since they live below this package.
+func (c *Cli) loadInterceptors() {
+	for _, interceptor := range c.options.Cli.Interceptors {
+		i, ok := interceptor.(Interceptor)
+		if ok {
+			c.interceptors = append(c.interceptors, i)
+		}
+	}
+}
+
+func (c *Cli) newOperation(kind OperationKind, inputs []string, options []Option) *Operation {
+	return &Operation{
+		Kind:     kind,
+		Host:     c.host,
+		Port:     c.options.Port,
+		Platform: c.options.Cli.DefinitionPlatform,
+		Inputs:   inputs,
+		Options:  options,
+	}
+}
+
+// interceptedFunc executes an operation at the end of the interceptor chain, see intercept.
+type interceptedFunc func(
+	ctx context.Context,
+	op *OperationHandle,
+	operation *Operation,
+) (*Result, error)
+
+// intercept returns the operationFunc that passes the operation through the interceptor chain
+// before executing it via f, see invoke.
+func (c *Cli) intercept(operation *Operation, f interceptedFunc) operationFunc {
+	return func(ctx context.Context, op *OperationHandle) (*Result, error) {
+		invoke := Invoker(func(ctx context.Context, operation *Operation) (*Result, error) {
+			return c.invoke(ctx, op, operation, f)
+		})
+
+		for idx := len(c.interceptors) - 1; idx >= 0; idx-- {
+			interceptor, next := c.interceptors[idx], invoke
+
+			invoke = func(ctx context.Context, operation *Operation) (*Result, error) {
+				return interceptor(ctx, operation, next)
+			}
+		}
+
+		return invoke(ctx, operation)
+	}
+}
+
+// invoke is the end of the interceptor chain. The operation is checked against the policy (if
+// any), and recorded rather than executed in dry-run mode, here so that whatever the interceptors
+// did to the operation is what is checked, recorded and audited. Results are redacted (if
+// configured) before they are audited or handed back up the chain.
+func (c *Cli) invoke(
+	ctx context.Context,
+	op *OperationHandle,
+	operation *Operation,
+	f interceptedFunc,
+) (*Result, error) {
+	invoked := c.options.Policy != nil ||
+		c.options.DryRun.Plan != nil ||
+		c.options.Audit != nil ||
+		c.options.Redaction.Results
+
+	if !invoked {
+		return f(ctx, op, operation)
+	}
+
+	start := time.Now()
+
+	mode := c.operationMode(operation)
+
+	result, err := c.execute(ctx, op, operation, mode, f)
+
+	if result != nil && c.options.Redaction.Results {
+		result.redact(c.options.GetRedactor())
+	}
+
+	c.audit(ctx, start, operation, mode, result, err)
+
+	return result, err
+}
+
+func (c *Cli) execute(
+	ctx context.Context,
+	op *OperationHandle,
+	operation *Operation,
+	mode string,
+	f interceptedFunc,
+) (*Result, error) {
+	err := c.checkPolicy(operation, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.checkDryRunMode(operation, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.dryRuns(operation, mode) {
+		return c.dryRun(operation, mode), nil
+	}
+
+	result, err := f(ctx, op, operation)
+
+	c.updateSessionMode(operation, err)
+
+	return result, err
+}
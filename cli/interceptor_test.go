@@ -0,0 +1,101 @@
+package cli_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	scrapligocli "github.com/scrapli/scrapligo/v2/cli"
+)
+
+func TestInterceptors(t *testing.T) {
+	testFixturePath, err := filepath.Abs("./fixtures/send-input-simple")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var intercepted []string
+
+	c := getCli(
+		t,
+		testFixturePath,
+		scrapligocli.WithInterceptors(
+			func(
+				ctx context.Context,
+				operation *scrapligocli.Operation,
+				next scrapligocli.Invoker,
+			) (*scrapligocli.Result, error) {
+				intercepted = append(intercepted, string(operation.Kind)+" "+operation.Inputs[0])
+
+				if operation.Inputs[0] == "show kernel" {
+					operation.Inputs[0] = "show version | i Kern"
+				}
+
+				return next(ctx, operation)
+			},
+			func(
+				ctx context.Context,
+				operation *scrapligocli.Operation,
+				next scrapligocli.Invoker,
+			) (*scrapligocli.Result, error) {
+				if operation.Inputs[0] != "reload" {
+					return next(ctx, operation)
+				}
+
+				return scrapligocli.NewResult(
+					operation.Host,
+					operation.Port,
+					[]byte(operation.Inputs[0]),
+					0,
+					nil,
+					nil,
+					[]byte("short-circuited"),
+					nil,
+				), nil
+			},
+		),
+	)
+
+	_, err = c.Open(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_, _ = c.Close(ctx)
+	}()
+
+	r, err := c.SendInput(ctx, "reload")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Result() != "short-circuited" {
+		t.Fatalf("expected short-circuited result, got %q", r.Result())
+	}
+
+	r, err = c.SendInput(ctx, "show kernel")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Inputs[0] != "show version | i Kern" {
+		t.Fatalf("expected modified input to be sent, got %q", r.Inputs[0])
+	}
+
+	expected := []string{"send-input reload", "send-input show kernel"}
+
+	if len(intercepted) != len(expected) {
+		t.Fatalf("expected intercepted operations %v, got %v", expected, intercepted)
+	}
+
+	for idx := range expected {
+		if intercepted[idx] != expected[idx] {
+			t.Fatalf("expected intercepted operations %v, got %v", expected, intercepted)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package cli_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	scrapligocli "github.com/scrapli/scrapligo/v2/cli"
+	scrapligooptions "github.com/scrapli/scrapligo/v2/options"
+)
+
+func getLookupCallbackCli(
+	t *testing.T,
+	f func(host, key string) (string, error),
+) *scrapligocli.Cli {
+	t.Helper()
+
+	testFixturePath, err := filepath.Abs("./fixtures/enter-mode-multi-stage-change-escalate")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// lookup callbacks are only supported by the go backend, so that is used whatever the default
+	c, err := scrapligocli.NewCli(
+		testHost,
+		scrapligooptions.WithBackendGo(),
+		scrapligooptions.WithUsername("admin"),
+		scrapligooptions.WithPassword("admin"),
+		scrapligooptions.WithLookupCallback(f),
+		scrapligooptions.WithDefinitionFileOrName(scrapligocli.AristaEos),
+		scrapligooptions.WithTransportTest(),
+		scrapligooptions.WithTestTransportF(testFixturePath),
+		scrapligooptions.WithReadSize(1),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func TestLookupCallback(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var lookups []string
+
+	c := getLookupCallbackCli(t, func(host, key string) (string, error) {
+		lookups = append(lookups, host+"/"+key)
+
+		return "libscrapli", nil
+	})
+
+	_, err := c.Open(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_, _ = c.Close(ctx)
+	}()
+
+	_, err = c.EnterMode(ctx, "exec")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = c.EnterMode(ctx, "configuration")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// once on open (acquiring the default privileged_exec mode) and once escalating again
+	if len(lookups) != 2 || lookups[0] != testHost+"/enable" || lookups[1] != testHost+"/enable" {
+		t.Fatalf("expected two lookups of %q, got %v", testHost+"/enable", lookups)
+	}
+}
+
+func TestLookupCallbackError(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c := getLookupCallbackCli(t, func(_, _ string) (string, error) {
+		return "", errors.New("otp service unavailable")
+	})
+
+	_, err := c.Open(ctx)
+	if err == nil {
+		_, _ = c.Close(ctx)
+
+		t.Fatal("expected error opening with failing lookup callback")
+	}
+}
@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"context"
+	"sync"
+
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+)
+
+// operationFunc is a (submitted) operation, see submit.
+type operationFunc func(ctx context.Context, op *OperationHandle) (*Result, error)
+
+// OperationHandle is a handle to a submitted cli operation. A Cli wraps a single session, so
+// operations are queued and executed one at a time in the order they were submitted -- this means
+// concurrent callers on one Cli are safe, they just wait their turn.
+type OperationHandle struct {
+	idLock sync.Mutex
+	id     uint32
+
+	cancel context.CancelCauseFunc
+
+	done   chan struct{}
+	result *Result
+	err    error
+}
+
+// ID returns the libscrapli operation id of the operation. This is zero until the operation has
+// reached the front of the queue and been submitted to libscrapli. Operations that are made up of
+// several libscrapli operations (ReadWithCallbacks) return the id of the most recent one.
+func (o *OperationHandle) ID() uint32 {
+	o.idLock.Lock()
+	defer o.idLock.Unlock()
+
+	return o.id
+}
+
+func (o *OperationHandle) setID(operationID uint32) {
+	o.idLock.Lock()
+	defer o.idLock.Unlock()
+
+	o.id = operationID
+}
+
+// Done returns a channel that is closed when the operation is complete.
+func (o *OperationHandle) Done() <-chan struct{} {
+	return o.done
+}
+
+// Cancel requests cancellation of the operation. If the operation is still queued it is dropped
+// from the queue, otherwise libscrapli is told to stop processing it.
+func (o *OperationHandle) Cancel() {
+	o.cancel(context.Canceled)
+}
+
+// Wait blocks until the operation completes or the context is cancelled, in the latter case the
+// operation is cancelled and the context error is returned immediately.
+func (o *OperationHandle) Wait(ctx context.Context) (*Result, error) {
+	select {
+	case <-o.done:
+		return o.result, o.err
+	default:
+	}
+
+	select {
+	case <-o.done:
+		return o.result, o.err
+	case <-ctx.Done():
+		o.cancel(ctx.Err())
+
+		return nil, ctx.Err()
+	}
+}
+
+func (o *OperationHandle) complete(result *Result, err error) {
+	o.result = result
+	o.err = err
+
+	close(o.done)
+}
+
+// queueHeldCtxKey is the context key used to mark that the holder of the context is the currently
+// executing operation of a Cli, see holdsQueue.
+type queueHeldCtxKey struct{}
+
+// holdsQueue returns true if ctx belongs to the operation currently executing on c. This is the
+// case for the context passed to ReadCallbackF callbacks, so that callbacks can issue operations
+// on the same Cli without deadlocking on the queue.
+func (c *Cli) holdsQueue(ctx context.Context) bool {
+	holder, _ := ctx.Value(queueHeldCtxKey{}).(*Cli)
+
+	return holder == c
+}
+
+// enqueue reserves the next spot in the operation queue. The returned channel is closed when it is
+// the callers turn, the returned func must be called (exactly once) once the caller is done in
+// order to hand off to the next spot in the queue.
+func (c *Cli) enqueue() (<-chan struct{}, func()) {
+	c.queueLock.Lock()
+	defer c.queueLock.Unlock()
+
+	prev := c.queueTail
+	if prev == nil {
+		prev = make(chan struct{})
+
+		close(prev)
+	}
+
+	next := make(chan struct{})
+
+	c.queueTail = next
+
+	return prev, func() { close(next) }
+}
+
+// waitTurn waits for the given turn in the queue, returning a context marked as holding the queue.
+// If ctx is cancelled first an error is returned -- in that case the spot in the queue is
+// still handed off in order once the turn comes up.
+func (c *Cli) waitTurn(
+	ctx context.Context,
+	turn <-chan struct{},
+	release func(),
+) (context.Context, error) {
+	select {
+	case <-turn:
+		return context.WithValue(ctx, queueHeldCtxKey{}, c), nil
+	case <-ctx.Done():
+		go func() {
+			<-turn
+
+			release()
+		}()
+
+		return nil, scrapligoerrors.NewFfiError(
+			"waiting for queued operations",
+			context.Cause(ctx),
+		)
+	}
+}
+
+// acquire waits for a turn in the queue for operations (Open/Close) that execute directly in the
+// calling goroutine, the returned func releases the queue.
+func (c *Cli) acquire(ctx context.Context) (context.Context, func(), error) {
+	if c.holdsQueue(ctx) {
+		return ctx, func() {}, nil
+	}
+
+	turn, release := c.enqueue()
+
+	queuedCtx, err := c.waitTurn(ctx, turn, release)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return queuedCtx, release, nil
+}
+
+// submit queues the operation f and returns its handle. The spot in the queue is reserved before
+// submit returns, so operations execute in the order they were submitted. f is executed with a
+// context that is cancelled if ctx is cancelled or the handle is cancelled. If ctx already holds
+// the queue (i.e. submit is called from a ReadWithCallbacks callback) f is executed immediately
+// in the calling goroutine.
+func (c *Cli) submit(
+	ctx context.Context,
+	f operationFunc,
+) *OperationHandle {
+	opCtx, cancel := context.WithCancelCause(ctx)
+
+	op := &OperationHandle{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	if c.holdsQueue(ctx) {
+		defer cancel(nil)
+
+		op.complete(f(opCtx, op))
+
+		return op
+	}
+
+	turn, release := c.enqueue()
+
+	go func() {
+		defer cancel(nil)
+
+		queuedCtx, err := c.waitTurn(opCtx, turn, release)
+		if err != nil {
+			op.complete(nil, err)
+
+			return
+		}
+
+		defer release()
+
+		op.complete(f(queuedCtx, op))
+	}()
+
+	return op
+}
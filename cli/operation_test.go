@@ -0,0 +1,137 @@
+package cli_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	mathrand "math/rand"
+	"path/filepath"
+	"testing"
+	"time"
+
+	scrapligocli "github.com/scrapli/scrapligo/v2/cli"
+	scrapligotesthelper "github.com/scrapli/scrapligo/v2/testhelper"
+)
+
+func TestSendInputAsync(t *testing.T) {
+	// the multi input fixture is simply the same input sent twice, so we can use it to ensure that
+	// queued operations are executed in submission order
+	testName := "send-inputs-simple-multi-input"
+
+	testFixturePath, err := filepath.Abs(fmt.Sprintf("./fixtures/%s", testName))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c := getCli(t, testFixturePath)
+
+	_, err = c.Open(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_, _ = c.Close(ctx)
+	}()
+
+	handles := []*scrapligocli.OperationHandle{
+		c.SendInputAsync(ctx, "show version | i Kern"),
+		c.SendInputAsync(ctx, "show version | i Kern"),
+	}
+
+	// wait in reverse order, the queue should still execute them in submission order
+	for idx := len(handles) - 1; idx >= 0; idx-- {
+		r, err := handles[idx].Wait(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		scrapligotesthelper.AssertEqual(t, false, r.Failed())
+		scrapligotesthelper.AssertNotDefault(t, handles[idx].ID())
+	}
+
+	if handles[0].ID() >= handles[1].ID() {
+		t.Fatalf(
+			"expected operations to execute in submission order, got ids %d and %d",
+			handles[0].ID(),
+			handles[1].ID(),
+		)
+	}
+}
+
+func TestOperationHandleCancelQueued(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c := getCli(t, "")
+
+	cancelledCtx, cancelledCancel := context.WithCancel(ctx)
+
+	// the cli is never opened and the context is cancelled before submission, either way the
+	// operation must complete with an error and never reach libscrapli
+	cancelledCancel()
+
+	op := c.SendInputAsync(cancelledCtx, "show version")
+
+	select {
+	case <-op.Done():
+	case <-ctx.Done():
+		t.Fatal("operation never completed")
+	}
+
+	_, err := op.Wait(ctx)
+	if err == nil {
+		t.Fatal("expected error from cancelled operation")
+	}
+
+	scrapligotesthelper.AssertEqual(t, uint32(0), op.ID())
+}
+
+// TestOperationCancelRandom cancels operations at random points (while queued, in flight, or
+// after completion), this is primarily useful with the race detector enabled (make test-race).
+func TestOperationCancelRandom(t *testing.T) {
+	// enough iterations for the race detector to have a fair shot at catching something
+	iterations := 100
+	if testing.Short() {
+		iterations = 10
+	}
+
+	testFixturePath, err := filepath.Abs("./fixtures/send-input-simple")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for idx := range iterations {
+		t.Run(fmt.Sprintf("iteration-%d", idx), func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			c := getCli(t, testFixturePath)
+
+			_, err := c.Open(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer func() {
+				_, _ = c.Close(ctx)
+			}()
+
+			op := c.SendInputAsync(ctx, "show version | i Kern")
+
+			time.Sleep(time.Duration(mathrand.Intn(5_000)) * time.Microsecond) //nolint: gosec
+
+			op.Cancel()
+
+			_, err = op.Wait(ctx)
+			if err != nil && !errors.Is(err, context.Canceled) {
+				t.Fatalf("expected nil or cancelled error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	scrapligopolicy "github.com/scrapli/scrapligo/v2/policy"
+	"go.yaml.in/yaml/v3"
+)
+
+// definitionDefaultMode returns the default mode of the definition, the definition is loaded (and
+// validated) by libscrapli (or the go backend) when the Cli is opened, so there is nothing useful
+// to do with an error here.
+func definitionDefaultMode(definitionString string) string {
+	var definition struct {
+		DefaultMode string `yaml:"default_mode"`
+	}
+
+	_ = yaml.Unmarshal([]byte(definitionString), &definition)
+
+	return definition.DefaultMode
+}
+
+// sessionMode returns the mode the session is known to be in, or an empty string if that is not
+// known -- the session is in the default mode of the definition once opened, and in the requested
+// mode once an operation requesting a mode succeeds. Inputs sent w/out requesting a mode are
+// assumed to leave the mode as is, a failed mode change leaves the mode unknown.
+func (c *Cli) sessionMode() string {
+	c.modeLock.Lock()
+	defer c.modeLock.Unlock()
+
+	return c.mode
+}
+
+func (c *Cli) setSessionMode(mode string) {
+	c.modeLock.Lock()
+	defer c.modeLock.Unlock()
+
+	c.mode = mode
+}
+
+// updateSessionMode updates the mode the session is known to be in after the operation executed
+// (with err as its outcome).
+func (c *Cli) updateSessionMode(operation *Operation, err error) {
+	if operation.RequestedMode == "" {
+		return
+	}
+
+	if err != nil {
+		c.setSessionMode("")
+
+		return
+	}
+
+	c.setSessionMode(operation.RequestedMode)
+}
+
+// operationMode returns the mode the inputs of the operation are sent in -- the requested mode, or
+// the mode the session is known to be in, or an empty string if the mode is not known.
+func (c *Cli) operationMode(operation *Operation) string {
+	if operation.RequestedMode != "" {
+		return operation.RequestedMode
+	}
+
+	return c.sessionMode()
+}
+
+// checkPolicy evaluates each input of the operation (sent in mode) against the policy of the Cli,
+// if any. If the mode is not known the inputs are evaluated as sent in the default mode of the
+// definition.
+func (c *Cli) checkPolicy(operation *Operation, mode string) error {
+	if c.options.Policy == nil || len(operation.Inputs) == 0 {
+		return nil
+	}
+
+	if mode == "" {
+		mode = c.defaultMode
+	}
+
+	for _, input := range operation.Inputs {
+		err := c.options.Policy.Evaluate(&scrapligopolicy.Subject{
+			Host:     operation.Host,
+			Platform: operation.Platform,
+			Mode:     mode,
+			Input:    input,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package cli_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	scrapligocli "github.com/scrapli/scrapligo/v2/cli"
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligooptions "github.com/scrapli/scrapligo/v2/options"
+	scrapligopolicy "github.com/scrapli/scrapligo/v2/policy"
+)
+
+func TestPolicy(t *testing.T) {
+	testFixturePath, err := filepath.Abs("./fixtures/send-input-simple")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	policy, err := scrapligopolicy.NewPolicy(
+		scrapligopolicy.Allow,
+		scrapligopolicy.Rule{
+			Name:      "no-reload",
+			Action:    scrapligopolicy.Deny,
+			Pattern:   `^reload`,
+			Platforms: []string{"arista_eos"},
+			Modes:     []string{"privileged_exec"},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := getCli(t, testFixturePath, scrapligooptions.WithPolicy(policy))
+
+	_, err = c.Open(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_, _ = c.Close(ctx)
+	}()
+
+	_, err = c.SendInput(ctx, "reload")
+	if !errors.Is(err, scrapligoerrors.ErrPolicyDenied) {
+		t.Fatalf("expected ErrPolicyDenied, got %v", err)
+	}
+
+	_, err = c.SendInput(ctx, "show version | i Kern")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPolicyModeScoped(t *testing.T) {
+	testFixturePath, err := filepath.Abs("./fixtures/send-input-simple")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	policy, err := scrapligopolicy.NewPolicy(
+		scrapligopolicy.Allow,
+		scrapligopolicy.Rule{
+			Name:    "no-show-in-config",
+			Action:  scrapligopolicy.Deny,
+			Pattern: `^show`,
+			Modes:   []string{"configuration"},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := getCli(t, testFixturePath, scrapligooptions.WithPolicy(policy))
+
+	_, err = c.Open(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_, _ = c.Close(ctx)
+	}()
+
+	_, err = c.SendInput(
+		ctx,
+		"show version | i Kern",
+		scrapligocli.WithRequestedMode("configuration"),
+	)
+	if !errors.Is(err, scrapligoerrors.ErrPolicyDenied) {
+		t.Fatalf("expected ErrPolicyDenied, got %v", err)
+	}
+
+	// the session is in the default mode (privileged_exec), a configuration mode rule does not
+	// apply to input sent w/out requesting a mode
+	_, err = c.SendInput(ctx, "show version | i Kern")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
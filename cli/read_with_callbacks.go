@@ -8,6 +8,7 @@ import (
 	"time"
 
 	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
 	scrapligoutil "github.com/scrapli/scrapligo/v2/util"
 )
 
@@ -57,11 +58,58 @@ func (r *ReadCallback) ok() bool {
 // ReadWithCallbacks optionally sends an initial "input" to the device and then continually reads
 // from the session, checking new session output against the provided callbacks (in the order
 // provided). When a callback is triggered, the callback is executed, if the callback is marked as
-// "completes" then the parent function exits, otherwise this continues forever.
-func (c *Cli) ReadWithCallbacks( //nolint: gocyclo
+// "completes" then the parent function exits, otherwise this continues forever. Callbacks may
+// execute other operations against the Cli using the context they are passed.
+func (c *Cli) ReadWithCallbacks(
 	ctx context.Context,
 	initialInput string,
 	callbacks ...*ReadCallback,
+) (*Result, error) {
+	return c.ReadWithCallbacksAsync(ctx, initialInput, callbacks...).Wait(ctx)
+}
+
+// ReadWithCallbacksAsync is the async flavor of ReadWithCallbacks -- the operation is queued and
+// an OperationHandle is returned immediately, cancelling ctx (or the handle) stops reading.
+func (c *Cli) ReadWithCallbacksAsync(
+	ctx context.Context,
+	initialInput string,
+	callbacks ...*ReadCallback,
+) *OperationHandle {
+	var inputs []string
+
+	if initialInput != "" {
+		inputs = []string{initialInput}
+	}
+
+	operation := c.newOperation(OperationKindReadWithCallbacks, inputs, nil)
+
+	return c.submit(ctx, c.intercept(operation, func(
+		ctx context.Context,
+		op *OperationHandle,
+		operation *Operation,
+	) (*Result, error) {
+		if len(operation.Inputs) > 1 {
+			return nil, scrapligoerrors.NewOptionsError(
+				"read-with-callbacks accepts at most one initial input",
+				nil,
+			)
+		}
+
+		var initialInput string
+
+		if len(operation.Inputs) == 1 {
+			initialInput = operation.Inputs[0]
+		}
+
+		return c.readWithCallbacks(ctx, op, initialInput, callbacks)
+	}))
+}
+
+func (c *Cli) readWithCallbacks( //nolint: gocyclo
+	ctx context.Context,
+	op *OperationHandle,
+	initialInput string,
+	callbacks []*ReadCallback,
 ) (*Result, error) {
 	if c.ptr == 0 {
 		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
@@ -80,7 +128,7 @@ func (c *Cli) ReadWithCallbacks( //nolint: gocyclo
 
 	startTime := time.Now()
 
-	cancel := false
+	cancel := scrapligoffi.NewCancelFlag()
 
 	if initialInput != "" {
 		err := c.WriteAndReturn(initialInput)
@@ -103,13 +151,15 @@ func (c *Cli) ReadWithCallbacks( //nolint: gocyclo
 		err := c.ffiMap.Cli.ReadAny(
 			c.ptr,
 			&operationID,
-			&cancel,
+			cancel,
 		)
 		if err != nil {
 			return nil, err
 		}
 
-		r, err := c.getResult(ctx, &cancel, operationID)
+		op.setID(operationID)
+
+		r, err := c.getResult(ctx, cancel, operationID)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,51 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	scrapligooptions "github.com/scrapli/scrapligo/v2/options"
+)
+
+func TestRedactResults(t *testing.T) {
+	testFixturePath, err := filepath.Abs("./fixtures/send-input-simple")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	c := getCli(
+		t,
+		testFixturePath,
+		scrapligooptions.WithRedactResults(),
+		scrapligooptions.WithRedactionPatterns(`(Kernel version: )\S+`),
+	)
+
+	_, err = c.Open(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_, _ = c.Close(ctx)
+	}()
+
+	r, err := c.SendInput(ctx, "show version | i Kern")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Result() != "Kernel version: <redacted>" {
+		t.Fatalf("expected redacted result, got %q", r.Result())
+	}
+
+	if !bytes.Contains(r.ResultRaw(), []byte("Kernel version: <redacted>")) ||
+		bytes.Contains(r.ResultRaw(), []byte("linuxkit")) {
+		t.Fatalf("expected redacted raw result, got %q", r.ResultRaw())
+	}
+}
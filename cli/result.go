@@ -8,6 +8,7 @@ import (
 	"time"
 
 	scrapligoconstants "github.com/scrapli/scrapligo/v2/constants"
+	scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
 	scrapligoutil "github.com/scrapli/scrapligo/v2/util"
 )
 
@@ -29,7 +30,10 @@ type Result struct {
 }
 
 // NewResult prepares a new Result object from ffi integration pointers (the pointers we pass to
-// zig for it to populate the values of stuff).
+// zig for it to populate the values of stuff). The Result takes ownership of the inputs, results
+// and raw results buffers -- they are split up front (the exported fields are always populated),
+// but the per input elements are views into them rather than copies, so the buffers must not be
+// modified after calling NewResult.
 func NewResult(
 	host string,
 	port uint16,
@@ -40,9 +44,6 @@ func NewResult(
 	results []byte,
 	resultsFailedIndicator []byte,
 ) *Result {
-	inputsS := strings.Split(string(inputs), scrapligoconstants.LibScrapliDelimiter)
-	resultsS := strings.Split(string(results), scrapligoconstants.LibScrapliDelimiter)
-
 	start := time.Unix(0, scrapligoutil.SafeUint64ToInt64(startTime))
 	splitTimes := make([]time.Time, len(splits))
 
@@ -59,14 +60,20 @@ func NewResult(
 	}
 
 	return &Result{
-		Host:   host,
-		Port:   port,
-		Inputs: inputsS,
+		Host: host,
+		Port: port,
+		Inputs: strings.Split(
+			scrapligointernal.BytesToString(inputs),
+			scrapligoconstants.LibScrapliDelimiter,
+		),
 		ResultsRaw: bytes.Split(
 			resultsRaw,
 			[]byte(scrapligoconstants.LibScrapliDelimiter),
 		),
-		Results:                resultsS,
+		Results: strings.Split(
+			scrapligointernal.BytesToString(results),
+			scrapligoconstants.LibScrapliDelimiter,
+		),
 		StartTime:              start,
 		Splits:                 splitTimes,
 		ElapsedTimeSeconds:     elapsed,
@@ -74,6 +81,44 @@ func NewResult(
 	}
 }
 
+// redact redacts the inputs and results of the Result, each input/result is redacted on its own so
+// nothing is ever redacted across inputs.
+func (r *Result) redact(redactor *scrapligointernal.Redactor) {
+	for idx, input := range r.Inputs {
+		r.Inputs[idx] = redactor.Redact(input)
+	}
+
+	for idx, result := range r.Results {
+		r.Results[idx] = redactor.Redact(result)
+	}
+
+	for idx, resultRaw := range r.ResultsRaw {
+		r.ResultsRaw[idx] = []byte(redactor.Redact(string(resultRaw)))
+	}
+}
+
+// inputBytes returns the total size of the inputs of the Result.
+func (r *Result) inputBytes() int {
+	var n int
+
+	for _, input := range r.Inputs {
+		n += len(input)
+	}
+
+	return n
+}
+
+// resultBytes returns the total size of the raw results of the Result.
+func (r *Result) resultBytes() int {
+	var n int
+
+	for _, resultRaw := range r.ResultsRaw {
+		n += len(resultRaw)
+	}
+
+	return n
+}
+
 // EndTime returns the end time of the Result. If there are no split times, it returns the start
 // time.
 func (r *Result) EndTime() time.Time {
@@ -93,7 +138,8 @@ func (r *Result) Result() string {
 	return strings.Join(r.Results, "\n")
 }
 
-// ResultRaw returns all raw results joined on newline chars.
+// ResultRaw returns all raw results joined on newline chars. The returned slice is always a copy,
+// modifying it never modifies the Result.
 func (r *Result) ResultRaw() []byte {
 	if len(r.ResultsRaw) == 0 {
 		return nil
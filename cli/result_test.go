@@ -0,0 +1,130 @@
+package cli_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	scrapligocli "github.com/scrapli/scrapligo/v2/cli"
+	scrapligoconstants "github.com/scrapli/scrapligo/v2/constants"
+	scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
+)
+
+const (
+	benchmarkResultInputs    = 16
+	benchmarkResultSize      = 4_096
+	benchmarkErrorBufferSize = 256
+)
+
+// benchmarkErrString keeps the compiler from eliding the scratch buffers in the benchmarks.
+var benchmarkErrString []byte //nolint: gochecknoglobals
+
+type benchmarkFetched struct {
+	inputs     []byte
+	resultsRaw []byte
+	results    []byte
+	splits     []uint64
+	errSize    int
+}
+
+func newBenchmarkFetched() *benchmarkFetched {
+	inputs := make([]string, benchmarkResultInputs)
+	results := make([]string, benchmarkResultInputs)
+	splits := make([]uint64, benchmarkResultInputs)
+
+	for idx := range benchmarkResultInputs {
+		inputs[idx] = "show interfaces"
+		results[idx] = strings.Repeat("x", benchmarkResultSize)
+		splits[idx] = uint64(idx)
+	}
+
+	return &benchmarkFetched{
+		inputs:     []byte(strings.Join(inputs, scrapligoconstants.LibScrapliDelimiter)),
+		resultsRaw: []byte(strings.Join(results, scrapligoconstants.LibScrapliDelimiter)),
+		results:    []byte(strings.Join(results, scrapligoconstants.LibScrapliDelimiter)),
+		splits:     splits,
+		errSize:    benchmarkErrorBufferSize,
+	}
+}
+
+// fill copies the fetched content into fresh buffers as libscrapli does when fetching an
+// operation, the buffers are owned by the result so are always allocated.
+func (f *benchmarkFetched) fill() ([]byte, []byte, []byte) {
+	inputs := make([]byte, len(f.inputs))
+	copy(inputs, f.inputs)
+
+	resultsRaw := make([]byte, len(f.resultsRaw))
+	copy(resultsRaw, f.resultsRaw)
+
+	results := make([]byte, len(f.results))
+	copy(results, f.results)
+
+	return inputs, resultsRaw, results
+}
+
+// legacyNewResult is the unpooled, copying NewResult path from before scratch buffers were pooled
+// and results were split into views of the fetched buffers.
+func legacyNewResult(f *benchmarkFetched) *scrapligocli.Result {
+	inputs, resultsRaw, results := f.fill()
+
+	resultsFailedIndicator := make([]byte, f.errSize)
+	benchmarkErrString = make([]byte, f.errSize)
+
+	return &scrapligocli.Result{
+		Inputs: strings.Split(string(inputs), scrapligoconstants.LibScrapliDelimiter),
+		ResultsRaw: bytes.Split(
+			resultsRaw,
+			[]byte(scrapligoconstants.LibScrapliDelimiter),
+		),
+		Results:                strings.Split(string(results), scrapligoconstants.LibScrapliDelimiter),
+		ResultsFailedIndicator: string(resultsFailedIndicator[:0]),
+	}
+}
+
+func pooledNewResult(f *benchmarkFetched) *scrapligocli.Result {
+	inputs, resultsRaw, results := f.fill()
+
+	resultsFailedIndicator := scrapligointernal.GetBuffer(uintptr(f.errSize))
+	defer scrapligointernal.PutBuffer(resultsFailedIndicator)
+
+	errString := scrapligointernal.GetBuffer(uintptr(f.errSize))
+	defer scrapligointernal.PutBuffer(errString)
+
+	benchmarkErrString = *errString
+
+	return scrapligocli.NewResult(
+		"localhost",
+		22,
+		inputs,
+		0,
+		f.splits,
+		resultsRaw,
+		results,
+		(*resultsFailedIndicator)[:0],
+	)
+}
+
+func benchmarkNewResult(b *testing.B, newResult func(f *benchmarkFetched) *scrapligocli.Result) {
+	b.Helper()
+
+	f := newBenchmarkFetched()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for b.Loop() {
+		r := newResult(f)
+
+		if len(r.Results) != benchmarkResultInputs {
+			b.Fatalf("expected %d results, got %d", benchmarkResultInputs, len(r.Results))
+		}
+	}
+}
+
+func BenchmarkNewResult(b *testing.B) {
+	benchmarkNewResult(b, pooledNewResult)
+}
+
+func BenchmarkLegacyNewResult(b *testing.B) {
+	benchmarkNewResult(b, legacyNewResult)
+}
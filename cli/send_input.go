@@ -4,6 +4,8 @@ import (
 	"context"
 
 	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
+	scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
 )
 
 func newSendInputOptions(options ...Option) *sendInputOptions {
@@ -42,29 +44,67 @@ func (c *Cli) SendInput(
 	input string,
 	options ...Option,
 ) (*Result, error) {
-	if c.ptr == 0 {
-		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
-	}
+	return c.SendInputAsync(ctx, input, options...).Wait(ctx)
+}
 
-	cancel := false
+// SendInputAsync is the async flavor of SendInput -- the operation is queued and an
+// OperationHandle is returned immediately, cancelling ctx cancels the operation. See SendInput for
+// supported options.
+func (c *Cli) SendInputAsync(
+	ctx context.Context,
+	input string,
+	options ...Option,
+) *OperationHandle {
+	operation := c.newOperation(OperationKindSendInput, []string{input}, options)
+	operation.RequestedMode = newSendInputOptions(options...).requestedMode
 
-	var operationID uint32
+	return c.submit(ctx, c.intercept(operation, c.sendInput))
+}
 
-	loadedOptions := newSendInputOptions(options...)
+func (c *Cli) sendInput(
+	ctx context.Context,
+	op *OperationHandle,
+	operation *Operation,
+) (*Result, error) {
+	if len(operation.Inputs) != 1 {
+		return nil, scrapligoerrors.NewOptionsError("send-input requires exactly one input", nil)
+	}
 
-	err := c.ffiMap.Cli.SendInput(
-		c.ptr,
-		&operationID,
-		&cancel,
-		input,
-		loadedOptions.requestedMode,
-		loadedOptions.getInputHandling(),
-		loadedOptions.retainInput,
-		loadedOptions.retainTrailingPrompt,
+	loadedOptions := newSendInputOptions(operation.Options...)
+	loadedOptions.requestedMode = operation.RequestedMode
+
+	input := operation.Inputs[0]
+
+	spanAttrs := append(
+		c.options.TraceInput(input),
+		scrapligointernal.TraceAttributeMode.String(loadedOptions.requestedMode),
 	)
-	if err != nil {
-		return nil, err
-	}
 
-	return c.getResult(ctx, &cancel, operationID)
+	return c.instrument(ctx, "send-input", spanAttrs, func(ctx context.Context) (*Result, error) {
+		if c.ptr == 0 {
+			return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
+		}
+
+		cancel := scrapligoffi.NewCancelFlag()
+
+		var operationID uint32
+
+		err := c.ffiMap.Cli.SendInput(
+			c.ptr,
+			&operationID,
+			cancel,
+			input,
+			loadedOptions.requestedMode,
+			loadedOptions.getInputHandling(),
+			loadedOptions.retainInput,
+			loadedOptions.retainTrailingPrompt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		op.setID(operationID)
+
+		return c.getResult(ctx, cancel, operationID)
+	})
 }
@@ -138,3 +138,45 @@ func TestSendInput(t *testing.T) {
 		})
 	}
 }
+
+// BenchmarkSendInput measures a send input round trip (and accessing the result) over the test
+// transport, run with -benchmem to see allocations per operation.
+func BenchmarkSendInput(b *testing.B) {
+	testFixturePath, err := filepath.Abs("./fixtures/send-input-simple")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		// each iteration needs a fresh cli since the test transport replays the fixture from the
+		// start, so open/close are excluded from the measurement
+		b.StopTimer()
+
+		c := getCli(b, testFixturePath)
+
+		_, err = c.Open(ctx)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.StartTimer()
+
+		r, err := c.SendInput(ctx, "show version | i Kern")
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		_ = r.Result()
+
+		b.StopTimer()
+
+		_, _ = c.Close(ctx)
+
+		b.StartTimer()
+	}
+}
@@ -6,6 +6,8 @@ import (
 
 	scrapligoconstants "github.com/scrapli/scrapligo/v2/constants"
 	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
+	scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
 	scrapligoutil "github.com/scrapli/scrapligo/v2/util"
 )
 
@@ -43,33 +45,65 @@ func (c *Cli) SendInputs(
 	inputs []string,
 	options ...Option,
 ) (*Result, error) {
-	if c.ptr == 0 {
-		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
-	}
+	return c.SendInputsAsync(ctx, inputs, options...).Wait(ctx)
+}
 
-	cancel := false
+// SendInputsAsync is the async flavor of SendInputs -- the operation is queued and an
+// OperationHandle is returned immediately, cancelling ctx cancels the operation. See SendInputs
+// for supported options.
+func (c *Cli) SendInputsAsync(
+	ctx context.Context,
+	inputs []string,
+	options ...Option,
+) *OperationHandle {
+	operation := c.newOperation(OperationKindSendInputs, inputs, options)
+	operation.RequestedMode = newSendInputsOptions(options...).requestedMode
 
-	loadedOptions := newSendInputsOptions(options...)
+	return c.submit(ctx, c.intercept(operation, c.sendInputs))
+}
 
-	joinedInputs := strings.Join(inputs, scrapligoconstants.LibScrapliDelimiter)
+func (c *Cli) sendInputs(
+	ctx context.Context,
+	op *OperationHandle,
+	operation *Operation,
+) (*Result, error) {
+	loadedOptions := newSendInputsOptions(operation.Options...)
+	loadedOptions.requestedMode = operation.RequestedMode
 
-	var operationID uint32
+	joinedInputs := strings.Join(operation.Inputs, scrapligoconstants.LibScrapliDelimiter)
 
-	err := c.ffiMap.Cli.SendInputs(
-		c.ptr,
-		&operationID,
-		&cancel,
-		joinedInputs,
-		loadedOptions.requestedMode,
-		loadedOptions.getInputHandling(),
-		loadedOptions.retainInput,
-		loadedOptions.retainTrailingPrompt,
+	spanAttrs := append(
+		c.options.TraceInput(strings.Join(operation.Inputs, "\n")),
+		scrapligointernal.TraceAttributeMode.String(loadedOptions.requestedMode),
 	)
-	if err != nil {
-		return nil, err
-	}
 
-	return c.getResult(ctx, &cancel, operationID)
+	return c.instrument(ctx, "send-inputs", spanAttrs, func(ctx context.Context) (*Result, error) {
+		if c.ptr == 0 {
+			return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
+		}
+
+		cancel := scrapligoffi.NewCancelFlag()
+
+		var operationID uint32
+
+		err := c.ffiMap.Cli.SendInputs(
+			c.ptr,
+			&operationID,
+			cancel,
+			joinedInputs,
+			loadedOptions.requestedMode,
+			loadedOptions.getInputHandling(),
+			loadedOptions.retainInput,
+			loadedOptions.retainTrailingPrompt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		op.setID(operationID)
+
+		return c.getResult(ctx, cancel, operationID)
+	})
 }
 
 // SendInputsFromFile is a conveince wrapper to load inputs from a file then pass those to
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
 )
 
 func newSendPromptedInputOptions(options ...Option) *sendPromptedInputOptions {
@@ -44,24 +45,45 @@ func (c *Cli) SendPromptedInput(
 	response string,
 	options ...Option,
 ) (*Result, error) {
+	operation := c.newOperation(OperationKindSendPromptedInput, []string{input}, options)
+	operation.RequestedMode = newSendPromptedInputOptions(options...).requestedMode
+	operation.Prompt = prompt
+	operation.Response = response
+
+	return c.submit(ctx, c.intercept(operation, c.sendPromptedInput)).Wait(ctx)
+}
+
+func (c *Cli) sendPromptedInput(
+	ctx context.Context,
+	op *OperationHandle,
+	operation *Operation,
+) (*Result, error) {
+	if len(operation.Inputs) != 1 {
+		return nil, scrapligoerrors.NewOptionsError(
+			"send-prompted-input requires exactly one input",
+			nil,
+		)
+	}
+
+	loadedOptions := newSendPromptedInputOptions(operation.Options...)
+	loadedOptions.requestedMode = operation.RequestedMode
+
 	if c.ptr == 0 {
 		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
 	}
 
-	cancel := false
-
-	loadedOptions := newSendPromptedInputOptions(options...)
+	cancel := scrapligoffi.NewCancelFlag()
 
 	var operationID uint32
 
 	err := c.ffiMap.Cli.SendPromptedInput(
 		c.ptr,
 		&operationID,
-		&cancel,
-		input,
-		prompt,
+		cancel,
+		operation.Inputs[0],
+		operation.Prompt,
 		loadedOptions.promptPattern,
-		response,
+		operation.Response,
 		loadedOptions.abortInput,
 		loadedOptions.requestedMode,
 		loadedOptions.getInputHandling(),
@@ -72,5 +94,7 @@ func (c *Cli) SendPromptedInput(
 		return nil, err
 	}
 
-	return c.getResult(ctx, &cancel, operationID)
+	op.setID(operationID)
+
+	return c.getResult(ctx, cancel, operationID)
 }
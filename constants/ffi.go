@@ -2,7 +2,9 @@ package constants
 
 const (
 	// LibScrapliPathOverrideEnv holds the key of the environment variable, that when set will force
-	// the ffi loader to load libscrapli from the provided path.
+	// the ffi loader to load libscrapli from the provided path. The library must be named like its
+	// release (i.e. "libscrapli-x86_64-linux-gnu.so.<version>") as that is how its version is
+	// known, and must be version LibScrapliAbiVersion.
 	LibScrapliPathOverrideEnv = "LIBSCRAPLI_PATH"
 
 	// LibScrapliCacheOverrideEnv holds the key for the environment variable that can be used to
@@ -12,9 +14,26 @@ const (
 	// LibScrapliVersionOverrideEnv overrides the value of constants.LibscrapliVersion -- you can
 	// set this to have the auto ffi installer install a specific commit or tag. Note that to use a
 	// hash you will need docker available the machine running the program (not required for setting
-	// a tagged release version).
+	// a tagged release version). The installed library is only loaded if it is the version the ffi
+	// mapping is written against (LibScrapliAbiVersion).
 	LibScrapliVersionOverrideEnv = "LIBSCRAPLI_VERSION"
 
+	// LibScrapliMirrorEnv holds the key of the environment variable that can be used to download
+	// libscrapli from a mirror rather than from GitHub releases. The mirror must follow the layout
+	// of GitHub releases -- that is "<mirror>/v<version>/<release filename>".
+	LibScrapliMirrorEnv = "LIBSCRAPLI_MIRROR_URL"
+
+	// LibScrapliBundlePathEnv holds the key of the environment variable that points to a directory
+	// of pre-fetched libscrapli libraries (see build/fetch_libscrapli) -- when set, and the
+	// directory holds the library for this version and platform, the library is loaded from there
+	// and nothing is downloaded. Handy for air-gapped networks.
+	LibScrapliBundlePathEnv = "LIBSCRAPLI_BUNDLE_PATH"
+
+	// LibScrapliRequireChecksumEnv holds the key of the environment variable, that when set to
+	// anything, makes loading libscrapli fail if there is no pinned checksum to verify the library
+	// against (i.e. for a version override or hash) rather than just logging a warning.
+	LibScrapliRequireChecksumEnv = "LIBSCRAPLI_REQUIRE_CHECKSUM"
+
 	// XdgCacheHomeEnv is the key for env var for XDG_CACHE_HOME -- we use this to try to see where
 	// a user would want us to cache the libscrapli dynamic library file.
 	XdgCacheHomeEnv = "XDG_CACHE_HOME"
@@ -8,5 +8,8 @@ const (
 
 	// ReadyFDPollTimeoutMs is the timeout in milliseconds to use when polling the driver's
 	// ready signal.
+	//
+	// Deprecated: ready signals are now waited on via the go runtime netpoller and no longer
+	// polled, this is unused.
 	ReadyFDPollTimeoutMs int = 100
 )
@@ -4,6 +4,11 @@ package constants
 // a release via build/update_all.sh to the version of libscrapli bundled in assets.
 var LibScrapliVersion = "0.0.1-rc.31"
 
+// LibScrapliAbiVersion is the version of libscrapli whose ffi signatures the ffi Mapping is written
+// against, a loaded library of any other version is refused. This must be bumped (by hand) along
+// with the Mapping whenever LibScrapliVersion is.
+const LibScrapliAbiVersion = "0.0.1-rc.31"
+
 // ScrapliDefinitionsVersion is the version of scrapli definitions embedded in assets in this build.
 // This should be set prior to a release via build/update_all.sh.
 var ScrapliDefinitionsVersion = "0.0.5"
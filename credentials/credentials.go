@@ -0,0 +1,47 @@
+package credentials
+
+import (
+	"context"
+)
+
+// Credentials is a set of credentials for a device. Empty fields leave the corresponding auth
+// options of the driver as they are, Lookups are added to (and override entries of) the lookup map
+// of the driver -- i.e. {"enable": "..."} provides the enable secret.
+type Credentials struct {
+	Username             string            `json:"username,omitempty"`
+	Password             string            `json:"password,omitempty"`
+	PrivateKey           string            `json:"privateKey,omitempty"`
+	PrivateKeyPassphrase string            `json:"privateKeyPassphrase,omitempty"`
+	Lookups              map[string]string `json:"lookups,omitempty"`
+}
+
+func (c *Credentials) empty() bool {
+	return c.Username == "" &&
+		c.Password == "" &&
+		c.PrivateKey == "" &&
+		c.PrivateKeyPassphrase == "" &&
+		len(c.Lookups) == 0
+}
+
+// Provider supplies credentials for a device. Providers are queried each time a driver is opened,
+// so credentials are only fetched when they are needed and can change (rotate) between opens. A
+// provider may return several credential sets, they are tried in order until one authenticates,
+// returning no sets (and no error) means the provider has nothing to offer.
+type Provider interface {
+	Credentials(ctx context.Context, host string) ([]*Credentials, error)
+}
+
+// ProviderFunc is a func that satisfies the Provider interface.
+type ProviderFunc func(ctx context.Context, host string) ([]*Credentials, error)
+
+// Credentials returns the result of calling f.
+func (f ProviderFunc) Credentials(ctx context.Context, host string) ([]*Credentials, error) {
+	return f(ctx, host)
+}
+
+// NewStaticProvider returns a Provider that always supplies the given credential sets.
+func NewStaticProvider(sets ...*Credentials) Provider { //nolint: ireturn
+	return ProviderFunc(func(_ context.Context, _ string) ([]*Credentials, error) {
+		return sets, nil
+	})
+}
@@ -0,0 +1,56 @@
+package credentials
+
+import (
+	"context"
+	"os"
+	"strings"
+)
+
+// EnvProvider supplies a credential set from environment variables, see NewEnvProvider.
+type EnvProvider struct {
+	prefix string
+}
+
+// NewEnvProvider returns an EnvProvider reading the variables PREFIX_USERNAME, PREFIX_PASSWORD,
+// PREFIX_PRIVATE_KEY (key content), PREFIX_PRIVATE_KEY_PASSPHRASE and PREFIX_LOOKUP_<KEY> (the
+// lookup value for the lowercased key, i.e. PREFIX_LOOKUP_ENABLE for "enable"). The variables are
+// read when the provider is queried, not when it is created.
+func NewEnvProvider(prefix string) *EnvProvider {
+	return &EnvProvider{
+		prefix: strings.TrimSuffix(prefix, "_") + "_",
+	}
+}
+
+// Credentials returns the credential set from the environment, or nothing if none of the
+// variables are set.
+func (p *EnvProvider) Credentials(_ context.Context, _ string) ([]*Credentials, error) {
+	c := &Credentials{
+		Username:             os.Getenv(p.prefix + "USERNAME"),
+		Password:             os.Getenv(p.prefix + "PASSWORD"),
+		PrivateKey:           os.Getenv(p.prefix + "PRIVATE_KEY"),
+		PrivateKeyPassphrase: os.Getenv(p.prefix + "PRIVATE_KEY_PASSPHRASE"),
+	}
+
+	lookupPrefix := p.prefix + "LOOKUP_"
+
+	for _, env := range os.Environ() {
+		k, v, _ := strings.Cut(env, "=")
+
+		key, ok := strings.CutPrefix(k, lookupPrefix)
+		if !ok || key == "" {
+			continue
+		}
+
+		if c.Lookups == nil {
+			c.Lookups = map[string]string{}
+		}
+
+		c.Lookups[strings.ToLower(key)] = v
+	}
+
+	if c.empty() {
+		return nil, nil
+	}
+
+	return []*Credentials{c}, nil
+}
@@ -0,0 +1,82 @@
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+)
+
+// ExecInfoEnv is the environment variable that carries the ExecInfo of a request to exec
+// credential plugins.
+const ExecInfoEnv = "SCRAPLIGO_EXEC_INFO"
+
+// ExecInfo is the (json encoded) request information handed to exec credential plugins.
+type ExecInfo struct {
+	Host string `json:"host"`
+}
+
+// ExecResponse is what exec credential plugins write (json encoded) to stdout.
+type ExecResponse struct {
+	Credentials []*Credentials `json:"credentials"`
+}
+
+// ExecProvider supplies credentials from an exec credential plugin, see NewExecProvider.
+type ExecProvider struct {
+	command string
+	args    []string
+}
+
+// NewExecProvider returns an ExecProvider that runs command with args each time it is queried,
+// similar to kubectl credential plugins. The plugin receives the ExecInfo of the request in the
+// SCRAPLIGO_EXEC_INFO environment variable and must write an ExecResponse to stdout and exit zero,
+// anything it writes to stderr is included in the error if it does not.
+func NewExecProvider(command string, args ...string) *ExecProvider {
+	return &ExecProvider{
+		command: command,
+		args:    args,
+	}
+}
+
+// Credentials returns the credential sets the plugin supplies for host.
+func (p *ExecProvider) Credentials(ctx context.Context, host string) ([]*Credentials, error) {
+	info, err := json.Marshal(&ExecInfo{Host: host})
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, p.command, p.args...) //nolint: gosec
+	cmd.Env = append(os.Environ(), ExecInfoEnv+"="+string(info))
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err = cmd.Run()
+	if err != nil {
+		msg := fmt.Sprintf("credential plugin %q failed", p.command)
+
+		if s := strings.TrimSpace(stderr.String()); s != "" {
+			msg += ": " + s
+		}
+
+		return nil, scrapligoerrors.NewUtilError(msg, err)
+	}
+
+	var response ExecResponse
+
+	err = json.Unmarshal(stdout.Bytes(), &response)
+	if err != nil {
+		return nil, scrapligoerrors.NewUtilError(
+			fmt.Sprintf("failed decoding response of credential plugin %q", p.command),
+			err,
+		)
+	}
+
+	return response.Credentials, nil
+}
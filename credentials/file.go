@@ -0,0 +1,112 @@
+package credentials
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+)
+
+const lookupFilePrefix = "lookup-"
+
+// FileProvider supplies a credential set from the files in a directory, see NewFileProvider.
+type FileProvider struct {
+	dir string
+}
+
+// NewFileProvider returns a FileProvider reading a file per field from dir: "username",
+// "password", "ssh-privatekey" (or "private-key"), "private-key-passphrase" and "lookup-<key>"
+// for lookup values. This is the layout of docker secrets and kubernetes (ssh-auth/basic-auth or
+// opaque) secret volume mounts. Trailing newlines are trimmed from file contents, and the files are
+// read when the provider is queried so rotated secrets are picked up on the next open.
+func NewFileProvider(dir string) *FileProvider {
+	return &FileProvider{
+		dir: dir,
+	}
+}
+
+// Credentials returns the credential set from the directory, or nothing if it holds none of the
+// files.
+func (p *FileProvider) Credentials(_ context.Context, _ string) ([]*Credentials, error) {
+	entries, err := os.ReadDir(p.dir)
+	if err != nil {
+		return nil, scrapligoerrors.NewUtilError(
+			fmt.Sprintf("failed reading credentials directory %q", p.dir),
+			err,
+		)
+	}
+
+	c := &Credentials{}
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		// kubernetes mounts carry "..data" and timestamped dirs that the files link into
+		if strings.HasPrefix(name, ".") || entry.IsDir() {
+			continue
+		}
+
+		var target *string
+
+		switch {
+		case name == "username":
+			target = &c.Username
+		case name == "password":
+			target = &c.Password
+		case name == "ssh-privatekey", name == "private-key":
+			target = &c.PrivateKey
+		case name == "private-key-passphrase":
+			target = &c.PrivateKeyPassphrase
+		case strings.HasPrefix(name, lookupFilePrefix) && len(name) > len(lookupFilePrefix):
+			if c.Lookups == nil {
+				c.Lookups = map[string]string{}
+			}
+
+			v, err := p.read(name)
+			if err != nil {
+				return nil, err
+			}
+
+			c.Lookups[strings.TrimPrefix(name, lookupFilePrefix)] = v
+
+			continue
+		default:
+			continue
+		}
+
+		*target, err = p.read(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.empty() {
+		return nil, nil
+	}
+
+	return []*Credentials{c}, nil
+}
+
+func (p *FileProvider) read(name string) (string, error) {
+	path := filepath.Join(p.dir, name)
+
+	b, err := os.ReadFile(path) //nolint: gosec
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			// dangling link (i.e. mid kubernetes secret update), treat as unset
+			return "", nil
+		}
+
+		return "", scrapligoerrors.NewUtilError(
+			fmt.Sprintf("failed reading credentials file %q", path),
+			err,
+		)
+	}
+
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
@@ -0,0 +1,84 @@
+package keyring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	scrapligocredentials "github.com/scrapli/scrapligo/v2/credentials"
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	gokeyring "github.com/zalando/go-keyring"
+)
+
+var _ scrapligocredentials.Provider = (*Provider)(nil)
+
+// Provider is a scrapligocredentials.Provider that supplies a credential set from the system
+// keyring (macos keychain, windows credential manager or the linux secret service), see
+// NewProvider.
+type Provider struct {
+	service    string
+	username   string
+	lookupKeys []string
+}
+
+// NewProvider returns a Provider reading the password of username from the keyring
+// entry (service, username), and each lookup value of lookupKeys from the entry
+// (service, username/key) -- i.e. "admin/enable" for the enable secret of "admin".
+func NewProvider(service, username string, lookupKeys ...string) *Provider {
+	return &Provider{
+		service:    service,
+		username:   username,
+		lookupKeys: lookupKeys,
+	}
+}
+
+// Credentials returns the credential set from the keyring, entries that do not exist are skipped.
+func (p *Provider) Credentials(
+	_ context.Context,
+	_ string,
+) ([]*scrapligocredentials.Credentials, error) {
+	password, err := p.get(p.username)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &scrapligocredentials.Credentials{
+		Username: p.username,
+		Password: password,
+	}
+
+	for _, key := range p.lookupKeys {
+		v, err := p.get(p.username + "/" + key)
+		if err != nil {
+			return nil, err
+		}
+
+		if v == "" {
+			continue
+		}
+
+		if c.Lookups == nil {
+			c.Lookups = map[string]string{}
+		}
+
+		c.Lookups[key] = v
+	}
+
+	return []*scrapligocredentials.Credentials{c}, nil
+}
+
+func (p *Provider) get(user string) (string, error) {
+	v, err := gokeyring.Get(p.service, user)
+	if err != nil {
+		if errors.Is(err, gokeyring.ErrNotFound) {
+			return "", nil
+		}
+
+		return "", scrapligoerrors.NewUtilError(
+			fmt.Sprintf("failed reading keyring entry %q for service %q", user, p.service),
+			err,
+		)
+	}
+
+	return v, nil
+}
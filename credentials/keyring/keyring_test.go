@@ -0,0 +1,47 @@
+package keyring_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	scrapligocredentials "github.com/scrapli/scrapligo/v2/credentials"
+	scrapligocredentialskeyring "github.com/scrapli/scrapligo/v2/credentials/keyring"
+	gokeyring "github.com/zalando/go-keyring"
+)
+
+func TestProvider(t *testing.T) {
+	gokeyring.MockInit()
+
+	err := gokeyring.Set("scrapligo", "admin", "hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = gokeyring.Set("scrapligo", "admin/enable", "hunter2-enable")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sets, err := scrapligocredentialskeyring.NewProvider(
+		"scrapligo",
+		"admin",
+		"enable",
+		"missing",
+	).Credentials(context.Background(), "1.2.3.4")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []*scrapligocredentials.Credentials{
+		{
+			Username: "admin",
+			Password: "hunter2",
+			Lookups:  map[string]string{"enable": "hunter2-enable"},
+		},
+	}
+
+	if !reflect.DeepEqual(expected, sets) {
+		t.Fatalf("expected credentials %+v, got %+v", expected, sets)
+	}
+}
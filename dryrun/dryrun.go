@@ -0,0 +1,72 @@
+package dryrun
+
+import (
+	"encoding/json"
+	"io"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Step is a single (configuration changing) operation recorded instead of being sent to a device.
+type Step struct {
+	Time time.Time `json:"time"`
+	// Driver is the kind of driver that recorded the step, "cli" or "netconf".
+	Driver   string `json:"driver"`
+	Host     string `json:"host"`
+	Port     uint16 `json:"port"`
+	Platform string `json:"platform,omitempty"`
+	// Mode is the mode cli inputs would have been sent in.
+	Mode string `json:"mode,omitempty"`
+	// Operation is the cli operation kind (i.e. "send-inputs") or the netconf rpc name (i.e.
+	// "edit-config").
+	Operation string   `json:"operation"`
+	Inputs    []string `json:"inputs,omitempty"`
+	// Payload is the rendered netconf rpc.
+	Payload string `json:"payload,omitempty"`
+}
+
+// Plan is the set of steps recorded by drivers in dry-run mode, in the order they were recorded. A
+// single Plan can (and generally should) be shared by all drivers taking part in a change.
+type Plan struct {
+	lock  sync.Mutex
+	steps []Step
+}
+
+// NewPlan returns a new, empty, Plan.
+func NewPlan() *Plan {
+	return &Plan{}
+}
+
+// Record appends the step to the plan.
+func (p *Plan) Record(step *Step) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.steps = append(p.steps, *step)
+}
+
+// Steps returns (a copy of) the steps recorded so far.
+func (p *Plan) Steps() []Step {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return slices.Clone(p.steps)
+}
+
+// MarshalJSON encodes the plan as an object holding the recorded steps.
+func (p *Plan) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Steps []Step `json:"steps"`
+	}{
+		Steps: p.Steps(),
+	})
+}
+
+// WriteJSON writes the plan (indented) to w.
+func (p *Plan) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(p)
+}
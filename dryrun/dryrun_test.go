@@ -0,0 +1,63 @@
+package dryrun_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	scrapligodryrun "github.com/scrapli/scrapligo/v2/dryrun"
+)
+
+func TestPlanWriteJSON(t *testing.T) {
+	plan := scrapligodryrun.NewPlan()
+
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	plan.Record(&scrapligodryrun.Step{
+		Time:      ts,
+		Driver:    "cli",
+		Host:      "localhost",
+		Port:      22,
+		Platform:  "arista_eos",
+		Mode:      "configuration",
+		Operation: "send-inputs",
+		Inputs:    []string{"interface loopback0", "description foo"},
+	})
+	plan.Record(&scrapligodryrun.Step{
+		Time:      ts,
+		Driver:    "netconf",
+		Host:      "localhost",
+		Port:      830,
+		Operation: "commit",
+		Payload:   "<commit/>",
+	})
+
+	var b bytes.Buffer
+
+	err := plan.WriteJSON(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded struct {
+		Steps []scrapligodryrun.Step `json:"steps"`
+	}
+
+	err = json.Unmarshal(b.Bytes(), &decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decoded.Steps) != 2 {
+		t.Fatalf("expected 2 steps, got %d", len(decoded.Steps))
+	}
+
+	if decoded.Steps[0].Inputs[1] != "description foo" || decoded.Steps[1].Payload != "<commit/>" {
+		t.Fatalf("unexpected steps %+v", decoded.Steps)
+	}
+
+	if !decoded.Steps[0].Time.Equal(ts) {
+		t.Fatalf("expected time %s, got %s", ts, decoded.Steps[0].Time)
+	}
+}
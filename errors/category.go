@@ -0,0 +1,231 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"syscall"
+	"unicode"
+)
+
+// ErrAuthenticationFailed is an error category for failures to authenticate to a device, whether
+// that is ssh/telnet authentication or in session (i.e. enable) authentication.
+var ErrAuthenticationFailed = errors.New("authentication failed")
+
+// ErrHostKeyMismatch is an error category for failures to verify the host key of a device against
+// the known hosts.
+var ErrHostKeyMismatch = errors.New("host key mismatch")
+
+// ErrConnectionRefused is an error category for failures to connect to a device because the
+// connection was refused.
+var ErrConnectionRefused = errors.New("connection refused")
+
+// ErrNameResolution is an error category for failures to resolve the address of a device.
+var ErrNameResolution = errors.New("name resolution failed")
+
+// ErrPromptTimeout is an error category for operations that timed out waiting for a prompt (or
+// any other expected output) from a device.
+var ErrPromptTimeout = errors.New("timeout waiting for prompt")
+
+// ErrUnexpectedEOF is an error category for connections that were unexpectedly closed by the
+// device.
+var ErrUnexpectedEOF = errors.New("unexpected eof")
+
+// categoryPhrases maps categories to the phrases (as words, see errorMessageWords) of libscrapli
+// (and go backend) error messages that identify them, phrases only ever match whole words. Order
+// matters, the first category with a matching phrase wins -- i.e. a timeout while authenticating
+// is an auth failure.
+var categoryPhrases = []struct { //nolint: gochecknoglobals
+	category error
+	phrases  [][]string
+}{
+	{
+		category: ErrHostKeyMismatch,
+		phrases: [][]string{
+			{"host", "key", "mismatch"},
+			{"host", "key", "verification", "failed"},
+			{"key", "mismatch"},
+			{"remote", "host", "identification", "has", "changed"},
+		},
+	},
+	{
+		category: ErrAuthenticationFailed,
+		phrases: [][]string{
+			{"authentication", "failed"},
+			{"auth", "failed"},
+			{"unable", "to", "authenticate"},
+			{"permission", "denied"},
+			{"access", "denied"},
+			{"login", "incorrect"},
+		},
+	},
+	{
+		category: ErrConnectionRefused,
+		phrases: [][]string{
+			{"connection", "refused"},
+		},
+	},
+	{
+		category: ErrNameResolution,
+		phrases: [][]string{
+			{"no", "such", "host"},
+			{"unknown", "host"},
+			{"name", "resolution"},
+			{"nodename", "nor", "servname"},
+			{"could", "not", "resolve"},
+			{"host", "lookup", "failed"},
+		},
+	},
+	{
+		category: ErrPromptTimeout,
+		phrases: [][]string{
+			{"timeout"},
+			{"timed", "out"},
+		},
+	},
+	{
+		category: ErrUnexpectedEOF,
+		phrases: [][]string{
+			{"eof"},
+			{"end", "of", "stream"},
+			{"connection", "reset", "by", "peer"},
+			{"broken", "pipe"},
+		},
+	},
+}
+
+// errorMessageWords splits the message in to lowercase words on anything but letters and digits
+// and on camel case boundaries, so that the "Connection refused" of a C library, the
+// "error.ConnectionRefused" of zig and the "connection_refused" of whatever else are all the words
+// "connection" and "refused".
+func errorMessageWords(message string) []string {
+	var (
+		words []string
+		word  []rune
+	)
+
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+
+	runes := []rune(message)
+
+	for idx, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+
+			continue
+		}
+
+		if unicode.IsUpper(r) && len(word) > 0 {
+			prev := word[len(word)-1]
+
+			// a new word starts at "eR" of "timeoutError" and at "Er" of "EOFError"
+			if !unicode.IsUpper(prev) ||
+				(idx+1 < len(runes) && unicode.IsLower(runes[idx+1])) {
+				flush()
+			}
+		}
+
+		word = append(word, r)
+	}
+
+	flush()
+
+	return words
+}
+
+// containsPhrase returns true if phrase appears (as consecutive whole words) in words.
+func containsPhrase(words, phrase []string) bool {
+	for idx := 0; idx+len(phrase) <= len(words); idx++ {
+		if slices.Equal(words[idx:idx+len(phrase)], phrase) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// categorizeInner returns the error category of a typed inner error -- libscrapli return codes and
+// the network errors of the go backend -- or nil if the inner error does not identify a category.
+func categorizeInner(inner error) error {
+	var dnsErr *net.DNSError
+
+	switch {
+	case errors.Is(inner, context.DeadlineExceeded),
+		errors.Is(inner, os.ErrDeadlineExceeded),
+		errors.Is(inner, ErrTimeout):
+		return ErrPromptTimeout
+	case errors.Is(inner, ErrEOF),
+		errors.Is(inner, io.EOF),
+		errors.Is(inner, io.ErrUnexpectedEOF),
+		errors.Is(inner, syscall.ECONNRESET),
+		errors.Is(inner, syscall.EPIPE):
+		return ErrUnexpectedEOF
+	case errors.Is(inner, syscall.ECONNREFUSED):
+		return ErrConnectionRefused
+	case errors.As(inner, &dnsErr):
+		return ErrNameResolution
+	default:
+		return nil
+	}
+}
+
+// categorize returns the error category (i.e. ErrAuthenticationFailed) of a failed operation from
+// its inner error or, failing that, its message (and the message of the inner error), or nil if it
+// does not fit any category.
+func categorize(message string, inner error) error {
+	category := categorizeInner(inner)
+	if category != nil {
+		return category
+	}
+
+	words := errorMessageWords(message)
+	if inner != nil {
+		// an empty "word" between the two so no phrase matches across them
+		words = append(words, "")
+		words = append(words, errorMessageWords(inner.Error())...)
+	}
+
+	for _, p := range categoryPhrases {
+		for _, phrase := range p.phrases {
+			if containsPhrase(words, phrase) {
+				return p.category
+			}
+		}
+	}
+
+	return nil
+}
+
+// Category returns the error category of err -- one of ErrAuthenticationFailed,
+// ErrHostKeyMismatch, ErrConnectionRefused, ErrNameResolution, ErrPromptTimeout or
+// ErrUnexpectedEOF -- or nil if err is not a ScrapliError or does not fit any category. Generally
+// errors.Is(err, scrapligoerrors.ErrAuthenticationFailed) and friends are the simpler option.
+func Category(err error) error {
+	var se *ScrapliError
+	if !errors.As(err, &se) {
+		return nil
+	}
+
+	return se.category
+}
+
+// NewOperationError returns a "ffi" flavor ScrapliError for a failed libscrapli operation, wrapping
+// the inner error if provided. The error is categorized from the message (libscrapli error and
+// last error strings) and inner error, so that i.e. errors.Is(err, ErrAuthenticationFailed) works.
+func NewOperationError(message string, inner error) error {
+	return &ScrapliError{
+		kind:     Ffi,
+		Message:  message,
+		Inner:    inner,
+		category: categorize(message, inner),
+	}
+}
@@ -0,0 +1,112 @@
+package errors_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+)
+
+func TestNewOperationError(t *testing.T) {
+	cases := map[string]struct {
+		message  string
+		inner    error
+		expected error
+	}{
+		"auth": {
+			message:  "error opening driver: error.AuthenticationFailed",
+			expected: scrapligoerrors.ErrAuthenticationFailed,
+		},
+		"auth-go-backend": {
+			message:  "ssh: handshake failed: ssh: unable to authenticate, attempted methods [none]",
+			expected: scrapligoerrors.ErrAuthenticationFailed,
+		},
+		"host-key": {
+			message:  "ssh: handshake failed: knownhosts: key mismatch",
+			expected: scrapligoerrors.ErrHostKeyMismatch,
+		},
+		"connection-refused": {
+			message:  "failed dialing: dial tcp 127.0.0.1:22: connect: connection refused",
+			expected: scrapligoerrors.ErrConnectionRefused,
+		},
+		"connection-refused-zig": {
+			message:  "error opening driver: error.ConnectionRefused",
+			expected: scrapligoerrors.ErrConnectionRefused,
+		},
+		"name-resolution": {
+			message:  "failed dialing: dial tcp: lookup nope.invalid: no such host",
+			expected: scrapligoerrors.ErrNameResolution,
+		},
+		"timeout": {
+			message:  "error executing operation",
+			inner:    fmt.Errorf("opening: %w", context.DeadlineExceeded),
+			expected: scrapligoerrors.ErrPromptTimeout,
+		},
+		"eof": {
+			message:  "error executing operation: error.EOF",
+			expected: scrapligoerrors.ErrUnexpectedEOF,
+		},
+		"eof-zig": {
+			message:  "error executing operation: error.EndOfStream",
+			expected: scrapligoerrors.ErrUnexpectedEOF,
+		},
+		"auth-zig": {
+			message:  "error opening driver: error.PermissionDenied",
+			expected: scrapligoerrors.ErrAuthenticationFailed,
+		},
+		"connection-refused-typed": {
+			message: "failed dialing",
+			inner: &net.OpError{
+				Op:  "dial",
+				Net: "tcp",
+				Err: os.NewSyscallError("connect", syscall.ECONNREFUSED),
+			},
+			expected: scrapligoerrors.ErrConnectionRefused,
+		},
+		"name-resolution-typed": {
+			message:  "failed dialing",
+			inner:    &net.DNSError{Err: "server misbehaving", Name: "nope.invalid"},
+			expected: scrapligoerrors.ErrNameResolution,
+		},
+		"eof-typed": {
+			message:  "error executing operation",
+			inner:    fmt.Errorf("reading: %w", io.EOF),
+			expected: scrapligoerrors.ErrUnexpectedEOF,
+		},
+		"none": {
+			message: "error executing operation: error.SomethingElse",
+		},
+		"none-partial-words": {
+			message: "error executing operation: sizeof mismatch, keymismatches, timeouts unset",
+		},
+	}
+
+	for caseName, caseData := range cases {
+		t.Run(caseName, func(t *testing.T) {
+			err := scrapligoerrors.NewOperationError(caseData.message, caseData.inner)
+
+			if !scrapligoerrors.IsKind(err, scrapligoerrors.Ffi) {
+				t.Fatalf("expected ffi kind error, got %v", err)
+			}
+
+			actual := scrapligoerrors.Category(err)
+			if actual != caseData.expected { //nolint: errorlint
+				t.Fatalf("expected category %v, got %v", caseData.expected, actual)
+			}
+
+			if caseData.expected != nil && !errors.Is(err, caseData.expected) {
+				t.Fatalf("expected errors.Is(err, %v) to be true", caseData.expected)
+			}
+
+			if caseData.inner != nil && !errors.Is(err, caseData.inner) {
+				t.Fatal("expected inner error to still be wrapped")
+			}
+		})
+	}
+}
@@ -21,6 +21,14 @@ var ErrNoMessages = errors.New("no messages")
 // ErrSubscriptionID is an error returned when failing to parse a subscription id from a message.
 var ErrSubscriptionID = errors.New("subscription id")
 
+// ErrMissingCapability is an error returned when attempting a netconf operation (or operation
+// option) that requires a capability the server did not advertise.
+var ErrMissingCapability = errors.New("missing capability")
+
+// ErrPolicyDenied is an error returned when an operation is denied by the (command) policy of the
+// driver.
+var ErrPolicyDenied = errors.New("denied by policy")
+
 // ErrorKind is an enum(ish) representing the kind of error -- i.e. "ffi" or "auth".
 type ErrorKind string
 
@@ -34,13 +42,16 @@ const (
 	Netconf ErrorKind = "netconf"
 	// Util represents errors encountered during utility funcs like parsing output.
 	Util ErrorKind = "util"
+	// Policy represents operations denied by the (command) policy of the driver.
+	Policy ErrorKind = "policy"
 )
 
 // ScrapliError is the base error type used for all scrapli errors.
 type ScrapliError struct {
-	kind    ErrorKind
-	Message string
-	Inner   error
+	kind     ErrorKind
+	category error
+	Message  string
+	Inner    error
 }
 
 func (e *ScrapliError) Error() string {
@@ -55,6 +66,12 @@ func (e *ScrapliError) Unwrap() error {
 	return e.Inner
 }
 
+// Is reports whether target is the category of the error (see Category), this is what makes
+// errors.Is(err, ErrAuthenticationFailed) and friends work.
+func (e *ScrapliError) Is(target error) bool {
+	return e.category != nil && e.category == target
+}
+
 // Kind returns the scrapli error category.
 func (e *ScrapliError) Kind() ErrorKind {
 	return e.kind
@@ -102,3 +119,8 @@ func NewUtilError(message string, inner error) error {
 func NewMessagesError() error {
 	return newScrapliError(Netconf, "no more messages available", ErrNoMessages)
 }
+
+// NewPolicyError returns a "policy" flavor ScrapliError, wrapping the ErrPolicyDenied error type.
+func NewPolicyError(message string) error {
+	return newScrapliError(Policy, message, ErrPolicyDenied)
+}
@@ -0,0 +1,126 @@
+package ffi
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/ebitengine/purego"
+	scrapligoconstants "github.com/scrapli/scrapligo/v2/constants"
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+)
+
+// expectedSymbols returns the names of all symbols the Mapping binds, in registration order.
+func expectedSymbols() []string {
+	var names []string
+
+	register(&Mapping{}, func(_ any, name string) {
+		names = append(names, name)
+	})
+
+	return names
+}
+
+// getLoadedLibscrapliVersion returns the version of the library at path as per its release
+// filename (see LibscrapliFilename), or an empty string if path is not named like a release (i.e.
+// a LibScrapliPathOverrideEnv path). libscrapli does not export its version, so the filename the
+// library was released, fetched, cached or bundled as is all there is to go on.
+func getLoadedLibscrapliVersion(path string) string {
+	name := filepath.Base(path)
+
+	for _, target := range libscrapliTargets {
+		rest, ok := strings.CutPrefix(name, "libscrapli-"+target)
+		if !ok {
+			continue
+		}
+
+		version, ok := strings.CutPrefix(rest, ".so.")
+		if ok {
+			return version
+		}
+
+		version, ok = strings.CutSuffix(rest, ".dylib")
+		if ok {
+			return strings.TrimPrefix(version, ".")
+		}
+	}
+
+	return ""
+}
+
+// checkAbi is the handshake with the library loaded from path -- the library must be the version
+// whose signatures the Mapping was written against (constants.LibScrapliAbiVersion, symbols can
+// only be checked for existence, not for their signatures), so a library of another or an unknown
+// version is refused, and every symbol the Mapping binds must be exported (binding a missing
+// symbol panics).
+func checkAbi(lib uintptr, path string) error {
+	loadedVersion := getLoadedLibscrapliVersion(path)
+
+	if loadedVersion == "" {
+		return scrapligoerrors.NewFfiError(
+			fmt.Sprintf(
+				"libscrapli at %q is not named like a release so its version is unknown, ffi"+
+					" signatures may differ from the expected version %s -- name the library like"+
+					" its release (i.e. %q)",
+				path,
+				scrapligoconstants.LibScrapliAbiVersion,
+				getLibscrapliTargetFilename(scrapligoconstants.LibScrapliAbiVersion),
+			),
+			nil,
+		)
+	}
+
+	if loadedVersion != scrapligoconstants.LibScrapliAbiVersion {
+		return scrapligoerrors.NewFfiError(
+			fmt.Sprintf(
+				"libscrapli version %s does not match the expected version %s, ffi signatures may"+
+					" differ -- unset %s or point %s at a library of the expected version",
+				loadedVersion,
+				scrapligoconstants.LibScrapliAbiVersion,
+				scrapligoconstants.LibScrapliVersionOverrideEnv,
+				scrapligoconstants.LibScrapliPathOverrideEnv,
+			),
+			nil,
+		)
+	}
+
+	var missing []string
+
+	for _, name := range expectedSymbols() {
+		_, err := purego.Dlsym(lib, name)
+		if err != nil {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return scrapligoerrors.NewFfiError(
+			fmt.Sprintf(
+				"libscrapli (version %s) is incompatible with this scrapligo, missing symbol(s):"+
+					" %s",
+				loadedVersion,
+				strings.Join(missing, ", "),
+			),
+			nil,
+		)
+	}
+
+	return nil
+}
+
+// CheckLibscrapli loads the libscrapli library at path and checks that it is compatible with this
+// version of scrapligo (see GetMapping), without binding anything. Handy to validate a bundle (see
+// FetchLibscrapli) ahead of time.
+func CheckLibscrapli(path string) error {
+	lib, err := purego.Dlopen(path, purego.RTLD_NOW|purego.RTLD_LOCAL)
+	if err != nil {
+		return scrapligoerrors.NewFfiError(
+			fmt.Sprintf("error loading libscrapli at file %q", path),
+			err,
+		)
+	}
+
+	defer purego.Dlclose(lib) //nolint: errcheck
+
+	return checkAbi(lib, path)
+}
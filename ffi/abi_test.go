@@ -0,0 +1,118 @@
+package ffi_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	scrapligoconstants "github.com/scrapli/scrapligo/v2/constants"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
+)
+
+// notLibscrapliPath returns the path of a shared library that is not libscrapli, any will do, it
+// certainly does not export libscrapli's symbols.
+func notLibscrapliPath(t *testing.T) string {
+	t.Helper()
+
+	libPath := "/lib/x86_64-linux-gnu/libc.so.6"
+	if runtime.GOOS == "darwin" {
+		return "/usr/lib/libSystem.B.dylib"
+	}
+
+	_, err := os.Stat(libPath)
+	if err != nil {
+		t.Skipf("no shared library to check at %q", libPath)
+	}
+
+	return libPath
+}
+
+// releaseNamedLibrary returns a path named like the libscrapli release for version, that links to
+// a shared library that is not libscrapli.
+func releaseNamedLibrary(t *testing.T, version string) string {
+	t.Helper()
+
+	if runtime.GOOS != "linux" {
+		t.Skip("release named library links are only set up on linux")
+	}
+
+	filename, err := scrapligoffi.LibscrapliFilename(testLibscrapliTarget, version)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	libPath := filepath.Join(t.TempDir(), filename)
+
+	// a link rather than a copy, so the already loaded library is what gets "loaded" again
+	err = os.Symlink(notLibscrapliPath(t), libPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return libPath
+}
+
+func TestCheckLibscrapli(t *testing.T) {
+	// not named like a release, so the version is unknown and the library must be refused
+	err := scrapligoffi.CheckLibscrapli(notLibscrapliPath(t))
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	if !strings.Contains(err.Error(), "its version is unknown") {
+		t.Fatalf("expected error refusing a library of an unknown version, got %q", err)
+	}
+}
+
+func TestCheckLibscrapliVersionMatch(t *testing.T) {
+	// the version matches so the check moves on to the symbols, which are still missing
+	err := scrapligoffi.CheckLibscrapli(
+		releaseNamedLibrary(t, scrapligoconstants.LibScrapliAbiVersion),
+	)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	if !strings.Contains(err.Error(), "(version "+scrapligoconstants.LibScrapliAbiVersion+")") ||
+		!strings.Contains(err.Error(), "missing symbol(s): ls_") {
+		t.Fatalf("expected error naming missing symbols of the abi version, got %q", err)
+	}
+}
+
+func TestCheckLibscrapliVersionMismatch(t *testing.T) {
+	// the version override is what gets installed, not what the mapping is written against
+	t.Setenv(scrapligoconstants.LibScrapliVersionOverrideEnv, testLibscrapliVersion)
+
+	err := scrapligoffi.CheckLibscrapli(releaseNamedLibrary(t, testLibscrapliVersion))
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	if !strings.Contains(
+		err.Error(),
+		"version "+testLibscrapliVersion+" does not match the expected version "+
+			scrapligoconstants.LibScrapliAbiVersion,
+	) {
+		t.Fatalf("expected version mismatch error, got %q", err)
+	}
+}
+
+func TestLibscrapliAbiVersion(t *testing.T) {
+	// the default library must be loadable, so the mapping must be written against it
+	if scrapligoconstants.LibScrapliVersion != scrapligoconstants.LibScrapliAbiVersion {
+		t.Fatalf(
+			"libscrapli version %s does not match the abi version %s, update the mapping",
+			scrapligoconstants.LibScrapliVersion,
+			scrapligoconstants.LibScrapliAbiVersion,
+		)
+	}
+}
+
+func TestCheckLibscrapliNotALibrary(t *testing.T) {
+	err := scrapligoffi.CheckLibscrapli(t.TempDir())
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
@@ -0,0 +1,48 @@
+package ffi
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+// CancelFlag is the cancellation flag passed to libscrapli with each operation -- libscrapli reads
+// it (as a bool) for the duration of the operation and stops the operation once it is set. The
+// flag is backed by a 32 bit word that is only ever accessed atomically on the go side (the go
+// backend included), so cancelling is safe from any goroutine at any point without any locking.
+// libscrapli itself reads the flag w/ a plain (non atomic) load, that can only be fixed by
+// libscrapli exposing a cancel call or reading the flag atomically. A CancelFlag must be created
+// with NewCancelFlag and must be kept alive until the operation it was passed to is done.
+type CancelFlag struct {
+	v uint32
+}
+
+// NewCancelFlag returns a new (unset) CancelFlag.
+func NewCancelFlag() *CancelFlag {
+	return &CancelFlag{}
+}
+
+// Cancel sets the flag, requesting libscrapli stop the operation. Cancel is idempotent.
+func (f *CancelFlag) Cancel() {
+	atomic.StoreUint32(&f.v, 1)
+}
+
+// Cancelled returns true if the flag has been set.
+func (f *CancelFlag) Cancelled() bool {
+	return atomic.LoadUint32(&f.v) != 0
+}
+
+// ptr returns a pointer to the byte of the backing word that holds the flag value -- the least
+// significant byte, which is the first byte on little endian systems and the last on big endian.
+func (f *CancelFlag) ptr() *bool {
+	return (*bool)(unsafe.Add(unsafe.Pointer(&f.v), cancelFlagByteOffset()))
+}
+
+func cancelFlagByteOffset() uintptr {
+	probe := uint32(1)
+
+	if *(*byte)(unsafe.Pointer(&probe)) == 1 {
+		return 0
+	}
+
+	return unsafe.Sizeof(probe) - 1
+}
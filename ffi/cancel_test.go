@@ -0,0 +1,40 @@
+package ffi_test
+
+import (
+	"sync"
+	"testing"
+
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
+)
+
+// TestCancelFlag cancels and reads flags from many goroutines at once, this is primarily useful
+// with the race detector enabled (make test-race).
+func TestCancelFlag(t *testing.T) {
+	const goroutines = 64
+
+	f := scrapligoffi.NewCancelFlag()
+
+	if f.Cancelled() {
+		t.Fatal("expected new flag to not be cancelled")
+	}
+
+	wg := &sync.WaitGroup{}
+
+	for idx := range goroutines {
+		wg.Go(func() {
+			if idx%2 == 0 {
+				f.Cancel()
+
+				return
+			}
+
+			_ = f.Cancelled()
+		})
+	}
+
+	wg.Wait()
+
+	if !f.Cancelled() {
+		t.Fatal("expected flag to be cancelled")
+	}
+}
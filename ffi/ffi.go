@@ -143,23 +143,8 @@ func getLibscrapliAbi() string {
 }
 
 func getLibscrapliTargetFilename(version string) string {
-	var libFilename string
-
-	switch runtime.GOOS {
-	case darwin:
-		libFilename = fmt.Sprintf(
-			"libscrapli-%s-macos.%s.dylib",
-			getZigStyleArch(),
-			version,
-		)
-	case linux:
-		libFilename = fmt.Sprintf(
-			"libscrapli-%s-linux-%s.so.%s",
-			getZigStyleArch(),
-			getLibscrapliAbi(),
-			version,
-		)
-	default:
+	libFilename, err := LibscrapliFilename(getLibscrapliTargetZigTriple(), version)
+	if err != nil {
 		panic("unsupported platform")
 	}
 
@@ -182,7 +167,9 @@ func getLibscrapliTargetZigTriple() string {
 }
 
 // EnsureLibscrapli ensures libscrapli is present at the cache path. It returns the final path
-// or an error.
+// or an error. If a bundle directory is set (LibScrapliBundlePathEnv) and holds the library that is
+// used rather than the cache, bundled, cached and downloaded libraries are all verified against the
+// pinned checksums.
 func EnsureLibscrapli(ctx context.Context) (string, error) {
 	overridePath := os.Getenv(scrapligoconstants.LibScrapliPathOverrideEnv)
 	if overridePath != "" {
@@ -199,6 +186,21 @@ func EnsureLibscrapli(ctx context.Context) (string, error) {
 
 	libFilename := getLibscrapliTargetFilename(version)
 
+	bundledLibFilename, ok, err := getLibscrapliFromBundle(version, libFilename)
+	if err != nil {
+		return "", err
+	}
+
+	if ok {
+		scrapligologging.Logger(
+			scrapligologging.Info,
+			"using bundled libscrapli at %q...",
+			bundledLibFilename,
+		)
+
+		return bundledLibFilename, nil
+	}
+
 	cachePath := getLibscrapliCachePath()
 
 	cachedLibFilename := fmt.Sprintf("%s/%s", cachePath, libFilename)
@@ -209,9 +211,20 @@ func EnsureLibscrapli(ctx context.Context) (string, error) {
 		cachedLibFilename,
 	)
 
-	_, err := os.Stat(cachedLibFilename)
-	if err == nil || !errors.Is(err, os.ErrNotExist) {
-		return cachedLibFilename, err
+	_, err = os.Stat(cachedLibFilename)
+	if err == nil {
+		// the cache is just a directory on disk, so the library is verified each time rather than
+		// trusting whatever is there
+		err = verifyLibscrapliFile(version, libFilename, cachedLibFilename)
+		if err != nil {
+			return "", err
+		}
+
+		return cachedLibFilename, nil
+	}
+
+	if !errors.Is(err, os.ErrNotExist) {
+		return "", err
 	}
 
 	scrapligologging.Logger(
@@ -331,22 +344,19 @@ func writeLibScrapliToCache( //nolint: nonamedreturns
 		if err != nil {
 			return scrapligoerrors.NewFfiError("failed running build container", err)
 		}
+
+		// built libraries are never pinned, but this lets LibScrapliRequireChecksumEnv refuse them
+		err = verifyLibscrapliFile(version, releaseFilename, cachedLibFilename)
+		if err != nil {
+			return err
+		}
 	} else {
 		scrapligologging.Logger(
 			scrapligologging.Debug,
-			"libscrapli target version is tag, attempting to fetch from github...",
+			"libscrapli target version is tag, attempting to fetch from github (or mirror)...",
 		)
 
-		err = writeHTTPContentsFromPath(
-			ctx,
-			fmt.Sprintf(
-				"%s/releases/download/v%s/%s",
-				libscrapliRepo,
-				version,
-				releaseFilename,
-			),
-			f,
-		)
+		err = downloadLibscrapli(ctx, version, releaseFilename, f)
 		if err != nil {
 			return err
 		}
@@ -356,7 +366,10 @@ func writeLibScrapliToCache( //nolint: nonamedreturns
 }
 
 // GetMapping returns the singleton Mapping instance that holds the bindings to the underlying
-// libscrapli shared library.
+// libscrapli shared library. Before anything is bound the library is checked for compatibility --
+// it must be the version the Mapping is written against (constants.LibScrapliAbiVersion) and
+// export every symbol the Mapping binds -- returning an FfiError rather than crashing on a
+// mismatch.
 func GetMapping() (*Mapping, error) {
 	var onceErrorString string
 
@@ -387,6 +400,17 @@ func GetMapping() (*Mapping, error) {
 			return
 		}
 
+		err = checkAbi(libScrapliFfi, libscrapliPath)
+		if err != nil {
+			onceErrorString = fmt.Sprintf(
+				"libscrapli at file %q failed compatibility check, err: %s",
+				libscrapliPath,
+				err,
+			)
+
+			return
+		}
+
 		mappingInst = &Mapping{
 			Shared:  SharedMapping{},
 			Session: SessionMapping{},
@@ -394,12 +418,9 @@ func GetMapping() (*Mapping, error) {
 			Netconf: NetconfMapping{},
 		}
 
-		purego.RegisterLibFunc(&mappingInst.AssertNoLeaks, libScrapliFfi, "ls_assert_no_leaks")
-
-		registerShared(mappingInst, libScrapliFfi)
-		registerSession(mappingInst, libScrapliFfi)
-		registerCli(mappingInst, libScrapliFfi)
-		registerNetconf(mappingInst, libScrapliFfi)
+		register(mappingInst, func(fptr any, name string) {
+			purego.RegisterLibFunc(fptr, libScrapliFfi, name)
+		})
 
 		scrapligologging.Logger(
 			scrapligologging.Debug,
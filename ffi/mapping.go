@@ -1,6 +1,14 @@
 package ffi
 
-import "github.com/ebitengine/purego"
+import (
+	"fmt"
+	"reflect"
+
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+)
+
+// binder binds the (libscrapli) symbol name to the func pointed to by fptr.
+type binder func(fptr any, name string)
 
 // Mapping holds mappings to the libscrapli external functions.
 type Mapping struct {
@@ -13,15 +21,78 @@ type Mapping struct {
 	Netconf NetconfMapping
 }
 
-func registerShared(m *Mapping, libScrapliFfi uintptr) {
-	purego.RegisterLibFunc(&m.Shared.GetPollFd, libScrapliFfi, "ls_shared_get_poll_fd")
-	purego.RegisterLibFunc(&m.Shared.Free, libScrapliFfi, "ls_shared_free")
+func register(m *Mapping, bind binder) {
+	bind(&m.AssertNoLeaks, "ls_assert_no_leaks")
+
+	registerShared(m, bind)
+	registerSession(m, bind)
+	registerCli(m, bind)
+	registerNetconf(m, bind)
+}
+
+// NewMappingFromSymbols returns a Mapping bound to the given symbols rather than to libscrapli,
+// symbols is a map of libscrapli symbol name (i.e. "ls_cli_open") to a go func with the exact
+// signature libscrapli would have for that symbol. This exists so that other (non libscrapli)
+// driver implementations can sit behind the exact same Mapping the Cli and Netconf objects use.
+// All symbols must be provided.
+func NewMappingFromSymbols(symbols map[string]any) (*Mapping, error) {
+	m := &Mapping{
+		Shared:  SharedMapping{},
+		Session: SessionMapping{},
+		Cli:     CliMapping{},
+		Netconf: NetconfMapping{},
+	}
+
+	var bindErr error
+
+	register(m, func(fptr any, name string) {
+		if bindErr != nil {
+			return
+		}
+
+		f := reflect.ValueOf(fptr).Elem()
+
+		sym, ok := symbols[name]
+		if !ok {
+			bindErr = scrapligoerrors.NewFfiError(fmt.Sprintf("symbol %q not provided", name), nil)
+
+			return
+		}
+
+		symV := reflect.ValueOf(sym)
+		if symV.Type() != f.Type() {
+			bindErr = scrapligoerrors.NewFfiError(
+				fmt.Sprintf(
+					"symbol %q has type %s, expected %s",
+					name,
+					symV.Type(),
+					f.Type(),
+				),
+				nil,
+			)
+
+			return
+		}
+
+		f.Set(symV)
+	})
+
+	if bindErr != nil {
+		return nil, bindErr
+	}
+
+	return m, nil
+}
+
+func registerShared(m *Mapping, bind binder) {
+	bind(&m.Shared.GetPollFd, "ls_shared_get_poll_fd")
+	bind(&m.Shared.Free, "ls_shared_free")
 
-	purego.RegisterLibFunc(&m.Shared.AllocDriverOptions, libScrapliFfi, "ls_alloc_driver_options")
-	purego.RegisterLibFunc(&m.Shared.FreeDriverOptions, libScrapliFfi, "ls_free_driver_options")
+	bind(&m.Shared.AllocDriverOptions, "ls_alloc_driver_options")
+	bind(&m.Shared.FreeDriverOptions, "ls_free_driver_options")
 
-	purego.RegisterLibFunc(&m.Shared.fetchOptionsSize, libScrapliFfi, "ls_fetch_options_size")
-	purego.RegisterLibFunc(&m.Shared.fetchOptions, libScrapliFfi, "ls_fetch_options")
+	bind(&m.Shared.fetchOptionsSize, "ls_fetch_options_size")
+	bind(&m.Shared.fetchOptions, "ls_fetch_options")
 }
 
 // SharedMapping holds common mappings for both cli and netconf drivers.
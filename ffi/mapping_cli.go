@@ -1,39 +1,25 @@
 package ffi
 
-import "github.com/ebitengine/purego"
-
-func registerCli(m *Mapping, libScrapliFfi uintptr) {
-	purego.RegisterLibFunc(&m.Cli.Alloc, libScrapliFfi, "ls_cli_alloc")
-
-	purego.RegisterLibFunc(&m.Cli.open, libScrapliFfi, "ls_cli_open")
-	purego.RegisterLibFunc(&m.Cli.close, libScrapliFfi, "ls_cli_close")
-
-	purego.RegisterLibFunc(
-		&m.Cli.fetchOperationSizes,
-		libScrapliFfi,
-		"ls_cli_fetch_operation_sizes",
-	)
-	purego.RegisterLibFunc(&m.Cli.fetchOperation, libScrapliFfi, "ls_cli_fetch_operation")
-
-	purego.RegisterLibFunc(&m.Cli.enterMode, libScrapliFfi, "ls_cli_enter_mode")
-	purego.RegisterLibFunc(&m.Cli.getPrompt, libScrapliFfi, "ls_cli_get_prompt")
-	purego.RegisterLibFunc(&m.Cli.sendInput, libScrapliFfi, "ls_cli_send_input")
-	purego.RegisterLibFunc(&m.Cli.sendInputs, libScrapliFfi, "ls_cli_send_inputs")
-	purego.RegisterLibFunc(&m.Cli.sendPromptedInput, libScrapliFfi, "ls_cli_send_prompted_input")
-
-	purego.RegisterLibFunc(&m.Cli.readAny, libScrapliFfi, "ls_cli_read_any")
-
-	purego.RegisterLibFunc(
-		&m.Cli.readCallbackShouldExecute,
-		libScrapliFfi,
-		"ls_cli_read_callback_should_execute",
-	)
-
-	purego.RegisterLibFunc(
-		&m.Cli.replaceDefinition,
-		libScrapliFfi,
-		"ls_cli_replace_definition",
-	)
+func registerCli(m *Mapping, bind binder) {
+	bind(&m.Cli.Alloc, "ls_cli_alloc")
+
+	bind(&m.Cli.open, "ls_cli_open")
+	bind(&m.Cli.close, "ls_cli_close")
+
+	bind(&m.Cli.fetchOperationSizes, "ls_cli_fetch_operation_sizes")
+	bind(&m.Cli.fetchOperation, "ls_cli_fetch_operation")
+
+	bind(&m.Cli.enterMode, "ls_cli_enter_mode")
+	bind(&m.Cli.getPrompt, "ls_cli_get_prompt")
+	bind(&m.Cli.sendInput, "ls_cli_send_input")
+	bind(&m.Cli.sendInputs, "ls_cli_send_inputs")
+	bind(&m.Cli.sendPromptedInput, "ls_cli_send_prompted_input")
+
+	bind(&m.Cli.readAny, "ls_cli_read_any")
+
+	bind(&m.Cli.readCallbackShouldExecute, "ls_cli_read_callback_should_execute")
+
+	bind(&m.Cli.replaceDefinition, "ls_cli_replace_definition")
 }
 
 // CliMapping holds libscrapli mappings specifically for cli drivers.
@@ -156,13 +142,13 @@ type CliMapping struct {
 func (m *CliMapping) Open(
 	driverPtr uintptr,
 	operationID *uint32,
-	cancel *bool,
+	cancel *CancelFlag,
 ) error {
 	return newLibScrapliResult(
 		m.open(
 			driverPtr,
 			operationID,
-			cancel,
+			cancel.ptr(),
 		),
 		"failed to submit open operation",
 	).check()
@@ -172,13 +158,13 @@ func (m *CliMapping) Open(
 func (m *CliMapping) Close(
 	driverPtr uintptr,
 	operationID *uint32,
-	cancel *bool,
+	cancel *CancelFlag,
 ) error {
 	return newLibScrapliResult(
 		m.close(
 			driverPtr,
 			operationID,
-			cancel,
+			cancel.ptr(),
 		),
 		"failed to submit close operation",
 	).check()
@@ -249,14 +235,14 @@ func (m *CliMapping) FetchOperation(
 func (m *CliMapping) EnterMode(
 	driverPtr uintptr,
 	operationID *uint32,
-	cancel *bool,
+	cancel *CancelFlag,
 	requestedMode string,
 ) error {
 	return newLibScrapliResult(
 		m.enterMode(
 			driverPtr,
 			operationID,
-			cancel,
+			cancel.ptr(),
 			requestedMode,
 		),
 		"failed to submit enterMode operation",
@@ -268,13 +254,13 @@ func (m *CliMapping) EnterMode(
 func (m *CliMapping) GetPrompt(
 	driverPtr uintptr,
 	operationID *uint32,
-	cancel *bool,
+	cancel *CancelFlag,
 ) error {
 	return newLibScrapliResult(
 		m.getPrompt(
 			driverPtr,
 			operationID,
-			cancel,
+			cancel.ptr(),
 		),
 		"failed to submit getPrompt operation",
 	).check()
@@ -285,7 +271,7 @@ func (m *CliMapping) GetPrompt(
 func (m *CliMapping) SendInput(
 	driverPtr uintptr,
 	operationID *uint32,
-	cancel *bool,
+	cancel *CancelFlag,
 	input string,
 	requestedMode string,
 	inputHandling *uint8,
@@ -296,7 +282,7 @@ func (m *CliMapping) SendInput(
 		m.sendInput(
 			driverPtr,
 			operationID,
-			cancel,
+			cancel.ptr(),
 			input,
 			requestedMode,
 			inputHandling,
@@ -312,7 +298,7 @@ func (m *CliMapping) SendInput(
 func (m *CliMapping) SendInputs(
 	driverPtr uintptr,
 	operationID *uint32,
-	cancel *bool,
+	cancel *CancelFlag,
 	inputs string,
 	requestedMode string,
 	inputHandling *uint8,
@@ -323,7 +309,7 @@ func (m *CliMapping) SendInputs(
 		m.sendInputs(
 			driverPtr,
 			operationID,
-			cancel,
+			cancel.ptr(),
 			inputs,
 			requestedMode,
 			inputHandling,
@@ -340,7 +326,7 @@ func (m *CliMapping) SendInputs(
 func (m *CliMapping) SendPromptedInput(
 	driverPtr uintptr,
 	operationID *uint32,
-	cancel *bool,
+	cancel *CancelFlag,
 	input string,
 	prompt string,
 	promptPattern string,
@@ -355,7 +341,7 @@ func (m *CliMapping) SendPromptedInput(
 		m.sendPromptedInput(
 			driverPtr,
 			operationID,
-			cancel,
+			cancel.ptr(),
 			input,
 			prompt,
 			promptPattern,
@@ -374,13 +360,13 @@ func (m *CliMapping) SendPromptedInput(
 func (m *CliMapping) ReadAny(
 	driverPtr uintptr,
 	operationID *uint32,
-	cancel *bool,
+	cancel *CancelFlag,
 ) error {
 	return newLibScrapliResult(
 		m.readAny(
 			driverPtr,
 			operationID,
-			cancel,
+			cancel.ptr(),
 		),
 		"failed to submit readAny operation",
 	).check()
@@ -1,74 +1,44 @@
 package ffi
 
-import "github.com/ebitengine/purego"
-
-func registerNetconf(m *Mapping, libScrapliFfi uintptr) {
-	purego.RegisterLibFunc(&m.Netconf.Alloc, libScrapliFfi, "ls_netconf_alloc")
-
-	purego.RegisterLibFunc(&m.Netconf.open, libScrapliFfi, "ls_netconf_open")
-	purego.RegisterLibFunc(&m.Netconf.close, libScrapliFfi, "ls_netconf_close")
-
-	purego.RegisterLibFunc(
-		&m.Netconf.fetchOperationSizes,
-		libScrapliFfi,
-		"ls_netconf_fetch_operation_sizes",
-	)
-	purego.RegisterLibFunc(
-		&m.Netconf.fetchOperation,
-		libScrapliFfi,
-		"ls_netconf_fetch_operation",
-	)
-
-	purego.RegisterLibFunc(&m.Netconf.getSessionID, libScrapliFfi, "ls_netconf_get_session_id")
-	purego.RegisterLibFunc(
-		&m.Netconf.getSubscriptionID,
-		libScrapliFfi,
-		"ls_netconf_get_subscription_id",
-	)
-
-	purego.RegisterLibFunc(
-		&m.Netconf.getNextNotificationSize,
-		libScrapliFfi,
-		"ls_netconf_next_notification_message_size",
-	)
-	purego.RegisterLibFunc(
-		&m.Netconf.getNextNotification,
-		libScrapliFfi,
-		"ls_netconf_next_notification_message",
-	)
-
-	purego.RegisterLibFunc(
-		&m.Netconf.getNextSubscriptionSize,
-		libScrapliFfi,
-		"ls_netconf_next_subscription_message_size",
-	)
-	purego.RegisterLibFunc(
-		&m.Netconf.getNextSubscription,
-		libScrapliFfi,
-		"ls_netconf_next_subscription_message",
-	)
-
-	purego.RegisterLibFunc(&m.Netconf.rawRPC, libScrapliFfi, "ls_netconf_raw_rpc")
-
-	purego.RegisterLibFunc(&m.Netconf.getConfig, libScrapliFfi, "ls_netconf_get_config")
-	purego.RegisterLibFunc(&m.Netconf.editConfig, libScrapliFfi, "ls_netconf_edit_config")
-	purego.RegisterLibFunc(&m.Netconf.copyConfig, libScrapliFfi, "ls_netconf_copy_config")
-	purego.RegisterLibFunc(&m.Netconf.deleteConfig, libScrapliFfi, "ls_netconf_delete_config")
-	purego.RegisterLibFunc(&m.Netconf.lock, libScrapliFfi, "ls_netconf_lock")
-	purego.RegisterLibFunc(&m.Netconf.unlock, libScrapliFfi, "ls_netconf_unlock")
-	purego.RegisterLibFunc(&m.Netconf.get, libScrapliFfi, "ls_netconf_get")
-	purego.RegisterLibFunc(&m.Netconf.closeSession, libScrapliFfi, "ls_netconf_close_session")
-	purego.RegisterLibFunc(&m.Netconf.killSession, libScrapliFfi, "ls_netconf_kill_session")
-
-	purego.RegisterLibFunc(&m.Netconf.commit, libScrapliFfi, "ls_netconf_commit")
-	purego.RegisterLibFunc(&m.Netconf.discard, libScrapliFfi, "ls_netconf_discard")
-	purego.RegisterLibFunc(&m.Netconf.cancelCommit, libScrapliFfi, "ls_netconf_cancel_commit")
-	purego.RegisterLibFunc(&m.Netconf.validate, libScrapliFfi, "ls_netconf_validate")
-
-	purego.RegisterLibFunc(&m.Netconf.getSchema, libScrapliFfi, "ls_netconf_get_schema")
-	purego.RegisterLibFunc(&m.Netconf.getData, libScrapliFfi, "ls_netconf_get_data")
-	purego.RegisterLibFunc(&m.Netconf.editData, libScrapliFfi, "ls_netconf_edit_data")
-	purego.RegisterLibFunc(&m.Netconf.action, libScrapliFfi, "ls_netconf_action")
+func registerNetconf(m *Mapping, bind binder) {
+	bind(&m.Netconf.Alloc, "ls_netconf_alloc")
+
+	bind(&m.Netconf.open, "ls_netconf_open")
+	bind(&m.Netconf.close, "ls_netconf_close")
+
+	bind(&m.Netconf.fetchOperationSizes, "ls_netconf_fetch_operation_sizes")
+	bind(&m.Netconf.fetchOperation, "ls_netconf_fetch_operation")
+
+	bind(&m.Netconf.getSessionID, "ls_netconf_get_session_id")
+	bind(&m.Netconf.getSubscriptionID, "ls_netconf_get_subscription_id")
+
+	bind(&m.Netconf.getNextNotificationSize, "ls_netconf_next_notification_message_size")
+	bind(&m.Netconf.getNextNotification, "ls_netconf_next_notification_message")
+
+	bind(&m.Netconf.getNextSubscriptionSize, "ls_netconf_next_subscription_message_size")
+	bind(&m.Netconf.getNextSubscription, "ls_netconf_next_subscription_message")
+
+	bind(&m.Netconf.rawRPC, "ls_netconf_raw_rpc")
+
+	bind(&m.Netconf.getConfig, "ls_netconf_get_config")
+	bind(&m.Netconf.editConfig, "ls_netconf_edit_config")
+	bind(&m.Netconf.copyConfig, "ls_netconf_copy_config")
+	bind(&m.Netconf.deleteConfig, "ls_netconf_delete_config")
+	bind(&m.Netconf.lock, "ls_netconf_lock")
+	bind(&m.Netconf.unlock, "ls_netconf_unlock")
+	bind(&m.Netconf.get, "ls_netconf_get")
+	bind(&m.Netconf.closeSession, "ls_netconf_close_session")
+	bind(&m.Netconf.killSession, "ls_netconf_kill_session")
+
+	bind(&m.Netconf.commit, "ls_netconf_commit")
+	bind(&m.Netconf.discard, "ls_netconf_discard")
+	bind(&m.Netconf.cancelCommit, "ls_netconf_cancel_commit")
+	bind(&m.Netconf.validate, "ls_netconf_validate")
+
+	bind(&m.Netconf.getSchema, "ls_netconf_get_schema")
+	bind(&m.Netconf.getData, "ls_netconf_get_data")
+	bind(&m.Netconf.editData, "ls_netconf_edit_data")
+	bind(&m.Netconf.action, "ls_netconf_action")
 }
 
 // NetconfMapping holds libscrapli mappings specifically for the netconf driver.
@@ -301,13 +271,13 @@ type NetconfMapping struct {
 func (m *NetconfMapping) Open(
 	driverPtr uintptr,
 	operationID *uint32,
-	cancel *bool,
+	cancel *CancelFlag,
 ) error {
 	return newLibScrapliResult(
 		m.open(
 			driverPtr,
 			operationID,
-			cancel,
+			cancel.ptr(),
 		),
 		"failed to submit open operation",
 	).check()
@@ -317,14 +287,14 @@ func (m *NetconfMapping) Open(
 func (m *NetconfMapping) Close(
 	driverPtr uintptr,
 	operationID *uint32,
-	cancel *bool,
+	cancel *CancelFlag,
 	force bool,
 ) error {
 	return newLibScrapliResult(
 		m.close(
 			driverPtr,
 			operationID,
-			cancel,
+			cancel.ptr(),
 			force,
 		),
 		"failed to submit close operation",
@@ -490,7 +460,7 @@ func (m *NetconfMapping) GetNextSubscription(
 func (m *NetconfMapping) RawRPC(
 	driverPtr uintptr,
 	operationID *uint32,
-	cancel *bool,
+	cancel *CancelFlag,
 	payload string,
 	baseNamespacePrefix string,
 	extraNamespaces string,
@@ -499,7 +469,7 @@ func (m *NetconfMapping) RawRPC(
 		m.rawRPC(
 			driverPtr,
 			operationID,
-			cancel,
+			cancel.ptr(),
 			payload,
 			baseNamespacePrefix,
 			extraNamespaces,
@@ -513,7 +483,7 @@ func (m *NetconfMapping) RawRPC(
 func (m *NetconfMapping) GetConfig(
 	driverPtr uintptr,
 	operationID *uint32,
-	cancel *bool,
+	cancel *CancelFlag,
 	source *uint8,
 	filter string,
 	filterType *uint8,
@@ -525,7 +495,7 @@ func (m *NetconfMapping) GetConfig(
 		m.getConfig(
 			driverPtr,
 			operationID,
-			cancel,
+			cancel.ptr(),
 			source,
 			filter,
 			filterType,
@@ -542,7 +512,7 @@ func (m *NetconfMapping) GetConfig(
 func (m *NetconfMapping) EditConfig(
 	driverPtr uintptr,
 	operationID *uint32,
-	cancel *bool,
+	cancel *CancelFlag,
 	config string,
 	target *uint8,
 	defaultOperation *uint8,
@@ -553,7 +523,7 @@ func (m *NetconfMapping) EditConfig(
 		m.editConfig(
 			driverPtr,
 			operationID,
-			cancel,
+			cancel.ptr(),
 			config,
 			target,
 			defaultOperation,
@@ -569,7 +539,7 @@ func (m *NetconfMapping) EditConfig(
 func (m *NetconfMapping) CopyConfig(
 	driverPtr uintptr,
 	operationID *uint32,
-	cancel *bool,
+	cancel *CancelFlag,
 	target *uint8,
 	source *uint8,
 ) error {
@@ -577,7 +547,7 @@ func (m *NetconfMapping) CopyConfig(
 		m.copyConfig(
 			driverPtr,
 			operationID,
-			cancel,
+			cancel.ptr(),
 			target,
 			source,
 		),
@@ -590,14 +560,14 @@ func (m *NetconfMapping) CopyConfig(
 func (m *NetconfMapping) DeleteConfig(
 	driverPtr uintptr,
 	operationID *uint32,
-	cancel *bool,
+	cancel *CancelFlag,
 	target *uint8,
 ) error {
 	return newLibScrapliResult(
 		m.deleteConfig(
 			driverPtr,
 			operationID,
-			cancel,
+			cancel.ptr(),
 			target,
 		),
 		"failed to submit deleteConfig operation",
@@ -609,14 +579,14 @@ func (m *NetconfMapping) DeleteConfig(
 func (m *NetconfMapping) Lock(
 	driverPtr uintptr,
 	operationID *uint32,
-	cancel *bool,
+	cancel *CancelFlag,
 	target *uint8,
 ) error {
 	return newLibScrapliResult(
 		m.lock(
 			driverPtr,
 			operationID,
-			cancel,
+			cancel.ptr(),
 			target,
 		),
 		"failed to submit lock operation",
@@ -628,14 +598,14 @@ func (m *NetconfMapping) Lock(
 func (m *NetconfMapping) Unlock(
 	driverPtr uintptr,
 	operationID *uint32,
-	cancel *bool,
+	cancel *CancelFlag,
 	target *uint8,
 ) error {
 	return newLibScrapliResult(
 		m.unlock(
 			driverPtr,
 			operationID,
-			cancel,
+			cancel.ptr(),
 			target,
 		),
 		"failed to submit unlock operation",
@@ -647,7 +617,7 @@ func (m *NetconfMapping) Unlock(
 func (m *NetconfMapping) Get(
 	driverPtr uintptr,
 	operationID *uint32,
-	cancel *bool,
+	cancel *CancelFlag,
 	filter string,
 	filterType *uint8,
 	filterNamespacePrefix string,
@@ -658,7 +628,7 @@ func (m *NetconfMapping) Get(
 		m.get(
 			driverPtr,
 			operationID,
-			cancel,
+			cancel.ptr(),
 			filter,
 			filterType,
 			filterNamespacePrefix,
@@ -674,13 +644,13 @@ func (m *NetconfMapping) Get(
 func (m *NetconfMapping) CloseSession(
 	driverPtr uintptr,
 	operationID *uint32,
-	cancel *bool,
+	cancel *CancelFlag,
 ) error {
 	return newLibScrapliResult(
 		m.closeSession(
 			driverPtr,
 			operationID,
-			cancel,
+			cancel.ptr(),
 		),
 		"failed to submit closeSession operation",
 	).check()
@@ -691,14 +661,14 @@ func (m *NetconfMapping) CloseSession(
 func (m *NetconfMapping) KillSession(
 	driverPtr uintptr,
 	operationID *uint32,
-	cancel *bool,
+	cancel *CancelFlag,
 	sessionID uint64,
 ) error {
 	return newLibScrapliResult(
 		m.killSession(
 			driverPtr,
 			operationID,
-			cancel,
+			cancel.ptr(),
 			sessionID,
 		),
 		"failed to submit killSession operation",
@@ -710,13 +680,13 @@ func (m *NetconfMapping) KillSession(
 func (m *NetconfMapping) Commit(
 	driverPtr uintptr,
 	operationID *uint32,
-	cancel *bool,
+	cancel *CancelFlag,
 ) error {
 	return newLibScrapliResult(
 		m.commit(
 			driverPtr,
 			operationID,
-			cancel,
+			cancel.ptr(),
 		),
 		"failed to submit commit operation",
 	).check()
@@ -727,13 +697,13 @@ func (m *NetconfMapping) Commit(
 func (m *NetconfMapping) Discard(
 	driverPtr uintptr,
 	operationID *uint32,
-	cancel *bool,
+	cancel *CancelFlag,
 ) error {
 	return newLibScrapliResult(
 		m.discard(
 			driverPtr,
 			operationID,
-			cancel,
+			cancel.ptr(),
 		),
 		"failed to submit discard operation",
 	).check()
@@ -744,14 +714,14 @@ func (m *NetconfMapping) Discard(
 func (m *NetconfMapping) CancelCommit(
 	driverPtr uintptr,
 	operationID *uint32,
-	cancel *bool,
+	cancel *CancelFlag,
 	persistID string,
 ) error {
 	return newLibScrapliResult(
 		m.cancelCommit(
 			driverPtr,
 			operationID,
-			cancel,
+			cancel.ptr(),
 			persistID,
 		),
 		"failed to submit cancelCommit operation",
@@ -763,14 +733,14 @@ func (m *NetconfMapping) CancelCommit(
 func (m *NetconfMapping) Validate(
 	driverPtr uintptr,
 	operationID *uint32,
-	cancel *bool,
+	cancel *CancelFlag,
 	source *uint8,
 ) error {
 	return newLibScrapliResult(
 		m.validate(
 			driverPtr,
 			operationID,
-			cancel,
+			cancel.ptr(),
 			source,
 		),
 		"failed to submit validate operation",
@@ -782,7 +752,7 @@ func (m *NetconfMapping) Validate(
 func (m *NetconfMapping) GetSchema(
 	driverPtr uintptr,
 	operationID *uint32,
-	cancel *bool,
+	cancel *CancelFlag,
 	identifier string,
 	version string,
 	format *uint8,
@@ -791,7 +761,7 @@ func (m *NetconfMapping) GetSchema(
 		m.getSchema(
 			driverPtr,
 			operationID,
-			cancel,
+			cancel.ptr(),
 			identifier,
 			version,
 			format,
@@ -805,7 +775,7 @@ func (m *NetconfMapping) GetSchema(
 func (m *NetconfMapping) GetData(
 	driverPtr uintptr,
 	operationID *uint32,
-	cancel *bool,
+	cancel *CancelFlag,
 	datastore *uint8,
 	filter string,
 	filterType *uint8,
@@ -821,7 +791,7 @@ func (m *NetconfMapping) GetData(
 		m.getData(
 			driverPtr,
 			operationID,
-			cancel,
+			cancel.ptr(),
 			datastore,
 			filter,
 			filterType,
@@ -842,7 +812,7 @@ func (m *NetconfMapping) GetData(
 func (m *NetconfMapping) EditData(
 	driverPtr uintptr,
 	operationID *uint32,
-	cancel *bool,
+	cancel *CancelFlag,
 	datastore *uint8,
 	content string,
 	defaultOperation *uint8,
@@ -851,7 +821,7 @@ func (m *NetconfMapping) EditData(
 		m.editData(
 			driverPtr,
 			operationID,
-			cancel,
+			cancel.ptr(),
 			datastore,
 			content,
 			defaultOperation,
@@ -865,14 +835,14 @@ func (m *NetconfMapping) EditData(
 func (m *NetconfMapping) Action(
 	driverPtr uintptr,
 	operationID *uint32,
-	cancel *bool,
+	cancel *CancelFlag,
 	action string,
 ) error {
 	return newLibScrapliResult(
 		m.action(
 			driverPtr,
 			operationID,
-			cancel,
+			cancel.ptr(),
 			action,
 		),
 		"failed to submit action operation",
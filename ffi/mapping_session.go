@@ -1,12 +1,10 @@
 package ffi
 
-import "github.com/ebitengine/purego"
-
-func registerSession(m *Mapping, libScrapliFfi uintptr) {
-	purego.RegisterLibFunc(&m.Session.read, libScrapliFfi, "ls_session_read")
-	purego.RegisterLibFunc(&m.Session.write, libScrapliFfi, "ls_session_write")
-	purego.RegisterLibFunc(&m.Session.writeAndReturn, libScrapliFfi, "ls_session_write_and_return")
-	purego.RegisterLibFunc(&m.Session.writeReturn, libScrapliFfi, "ls_session_write_return")
+func registerSession(m *Mapping, bind binder) {
+	bind(&m.Session.read, "ls_session_read")
+	bind(&m.Session.write, "ls_session_write")
+	bind(&m.Session.writeAndReturn, "ls_session_write_and_return")
+	bind(&m.Session.writeReturn, "ls_session_write_return")
 }
 
 // SessionMapping holds session specific mappings.
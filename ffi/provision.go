@@ -0,0 +1,268 @@
+package ffi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	scrapligoassets "github.com/scrapli/scrapligo/v2/assets"
+	scrapligoconstants "github.com/scrapli/scrapligo/v2/constants"
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligologging "github.com/scrapli/scrapligo/v2/logging"
+	scrapligoutil "github.com/scrapli/scrapligo/v2/util"
+)
+
+const (
+	libscrapliReleasesPath = "/releases/download"
+	macosTargetSuffix      = "-macos"
+	linuxTargetInfix       = "-linux-"
+)
+
+var (
+	// libscrapliTargets are the zig style target triples libscrapli releases are published for.
+	libscrapliTargets = []string{ //nolint: gochecknoglobals
+		"x86_64-linux-gnu",
+		"x86_64-linux-musl",
+		"aarch64-linux-gnu",
+		"aarch64-linux-musl",
+		"x86_64-macos",
+		"aarch64-macos",
+	}
+
+	libscrapliChecksums     map[string]map[string]string //nolint: gochecknoglobals
+	libscrapliChecksumsErr  error                        //nolint: gochecknoglobals
+	libscrapliChecksumsOnce sync.Once                    //nolint: gochecknoglobals
+)
+
+// LibscrapliTargets returns the (zig style) target triples that libscrapli releases are published
+// for, i.e. "x86_64-linux-gnu" or "aarch64-macos".
+func LibscrapliTargets() []string {
+	return slices.Clone(libscrapliTargets)
+}
+
+// LibscrapliFilename returns the release filename of libscrapli for the given target triple and
+// version, this is also the filename libscrapli is cached/bundled as.
+func LibscrapliFilename(target, version string) (string, error) {
+	switch {
+	case strings.HasSuffix(target, macosTargetSuffix):
+		return fmt.Sprintf("libscrapli-%s.%s.dylib", target, version), nil
+	case strings.Contains(target, linuxTargetInfix):
+		return fmt.Sprintf("libscrapli-%s.so.%s", target, version), nil
+	default:
+		return "", scrapligoerrors.NewFfiError(
+			fmt.Sprintf("unsupported libscrapli target %q", target),
+			nil,
+		)
+	}
+}
+
+func getLibscrapliChecksums() (map[string]map[string]string, error) {
+	libscrapliChecksumsOnce.Do(func() {
+		libscrapliChecksumsErr = json.Unmarshal(
+			scrapligoassets.LibScrapliChecksums,
+			&libscrapliChecksums,
+		)
+	})
+
+	return libscrapliChecksums, libscrapliChecksumsErr
+}
+
+// verifyLibscrapliChecksum checks the given sha256 digest of a libscrapli library against the
+// pinned checksum for the version and filename. The default version (constants.LibScrapliVersion)
+// must always be pinned, for anything else (a version override, a hash version...) a missing pin
+// is only logged as a warning, unless checksums are required in which case that is an error.
+func verifyLibscrapliChecksum(version, filename, digest string) error {
+	checksums, err := getLibscrapliChecksums()
+	if err != nil {
+		return scrapligoerrors.NewFfiError("failed loading pinned libscrapli checksums", err)
+	}
+
+	expected, ok := checksums[version][filename]
+	if !ok {
+		if version == scrapligoconstants.LibScrapliVersion {
+			return scrapligoerrors.NewFfiError(
+				fmt.Sprintf(
+					"no pinned checksum for libscrapli %q default version %q, refusing to use "+
+						"an unverified library",
+					filename,
+					version,
+				),
+				nil,
+			)
+		}
+
+		if os.Getenv(scrapligoconstants.LibScrapliRequireChecksumEnv) != "" {
+			return scrapligoerrors.NewFfiError(
+				fmt.Sprintf(
+					"no pinned checksum for libscrapli %q version %q and %s is set",
+					filename,
+					version,
+					scrapligoconstants.LibScrapliRequireChecksumEnv,
+				),
+				nil,
+			)
+		}
+
+		scrapligologging.Logger(
+			scrapligologging.Warn,
+			"no pinned checksum for libscrapli %q version %q, skipping verification...",
+			filename,
+			version,
+		)
+
+		return nil
+	}
+
+	if !strings.EqualFold(expected, digest) {
+		return scrapligoerrors.NewFfiError(
+			fmt.Sprintf(
+				"checksum mismatch for libscrapli %q version %q, expected %s got %s",
+				filename,
+				version,
+				expected,
+				digest,
+			),
+			nil,
+		)
+	}
+
+	return nil
+}
+
+// verifyLibscrapliFile verifies the libscrapli library at path, see verifyLibscrapliChecksum.
+func verifyLibscrapliFile(version, filename, path string) error {
+	f, err := os.Open(path) //nolint: gosec
+	if err != nil {
+		return err
+	}
+
+	defer f.Close() //nolint: errcheck
+
+	h := sha256.New()
+
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return err
+	}
+
+	return verifyLibscrapliChecksum(version, filename, hex.EncodeToString(h.Sum(nil)))
+}
+
+func getLibscrapliReleaseURL(version, filename string) string {
+	base := scrapligoutil.GetEnvStrOrDefault(
+		scrapligoconstants.LibScrapliMirrorEnv,
+		libscrapliRepo+libscrapliReleasesPath,
+	)
+
+	return fmt.Sprintf("%s/v%s/%s", strings.TrimRight(base, "/"), version, filename)
+}
+
+// downloadLibscrapli downloads the libscrapli release filename for version to w (from GitHub or
+// the configured mirror) and verifies its checksum.
+func downloadLibscrapli(ctx context.Context, version, filename string, w io.Writer) error {
+	h := sha256.New()
+
+	err := writeHTTPContentsFromPath(
+		ctx,
+		getLibscrapliReleaseURL(version, filename),
+		io.MultiWriter(w, h),
+	)
+	if err != nil {
+		return err
+	}
+
+	return verifyLibscrapliChecksum(version, filename, hex.EncodeToString(h.Sum(nil)))
+}
+
+// getLibscrapliFromBundle returns the path to libscrapli in the bundle directory (if one is set
+// and it holds the library for this version and platform), the library is verified before it is
+// handed back.
+func getLibscrapliFromBundle(version, filename string) (string, bool, error) {
+	bundlePath := os.Getenv(scrapligoconstants.LibScrapliBundlePathEnv)
+	if bundlePath == "" {
+		return "", false, nil
+	}
+
+	bundledLibFilename := filepath.Join(bundlePath, filename)
+
+	_, err := os.Stat(bundledLibFilename)
+	if errors.Is(err, os.ErrNotExist) {
+		scrapligologging.Logger(
+			scrapligologging.Info,
+			"libscrapli does not exist in bundle at %q...",
+			bundledLibFilename,
+		)
+
+		return "", false, nil
+	}
+
+	if err != nil {
+		return "", false, err
+	}
+
+	err = verifyLibscrapliFile(version, filename, bundledLibFilename)
+	if err != nil {
+		return "", false, err
+	}
+
+	return bundledLibFilename, true, nil
+}
+
+// FetchLibscrapli downloads (and verifies) libscrapli for the given version and target triple (see
+// LibscrapliTargets) in to dir, returning the path of the library. The library is written with its
+// release filename, so dir can be used as a bundle directory (see LibScrapliBundlePathEnv).
+func FetchLibscrapli(
+	ctx context.Context,
+	version,
+	target,
+	dir string,
+) (string, error) {
+	filename, err := LibscrapliFilename(target, version)
+	if err != nil {
+		return "", err
+	}
+
+	err = os.MkdirAll(dir, scrapligoconstants.PermissionsOwnerReadWriteExecute)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.CreateTemp(dir, filename+".*")
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		// best effort close and remove, on success the temp file has been renamed already
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}()
+
+	err = downloadLibscrapli(ctx, version, filename, f)
+	if err != nil {
+		return "", err
+	}
+
+	err = f.Chmod(scrapligoconstants.PermissionsOwnerReadWriteEveryoneRead)
+	if err != nil {
+		return "", err
+	}
+
+	libPath := filepath.Join(dir, filename)
+
+	err = os.Rename(f.Name(), libPath)
+	if err != nil {
+		return "", err
+	}
+
+	return libPath, nil
+}
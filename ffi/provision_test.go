@@ -0,0 +1,251 @@
+package ffi_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	scrapligoassets "github.com/scrapli/scrapligo/v2/assets"
+	scrapligoconstants "github.com/scrapli/scrapligo/v2/constants"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
+)
+
+const (
+	testLibscrapliVersion = "0.0.0-test"
+	testLibscrapliTarget  = "x86_64-linux-gnu"
+)
+
+func TestLibscrapliFilename(t *testing.T) {
+	cases := map[string]struct {
+		target   string
+		expected string
+		err      bool
+	}{
+		"linux": {
+			target:   "aarch64-linux-musl",
+			expected: "libscrapli-aarch64-linux-musl.so.1.2.3",
+		},
+		"macos": {
+			target:   "x86_64-macos",
+			expected: "libscrapli-x86_64-macos.1.2.3.dylib",
+		},
+		"unsupported": {
+			target: "x86_64-windows",
+			err:    true,
+		},
+	}
+
+	for caseName, caseData := range cases {
+		t.Run(caseName, func(t *testing.T) {
+			actual, err := scrapligoffi.LibscrapliFilename(caseData.target, "1.2.3")
+			if caseData.err {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if actual != caseData.expected {
+				t.Fatalf("expected %q, got %q", caseData.expected, actual)
+			}
+		})
+	}
+}
+
+func newMirror(t *testing.T, version string, content []byte) *httptest.Server {
+	t.Helper()
+
+	filename, err := scrapligoffi.LibscrapliFilename(testLibscrapliTarget, version)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != fmt.Sprintf("/v%s/%s", version, filename) {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_, _ = w.Write(content)
+	}))
+
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func TestFetchLibscrapli(t *testing.T) {
+	content := []byte("not really a shared library")
+
+	mirror := newMirror(t, testLibscrapliVersion, content)
+
+	t.Setenv(scrapligoconstants.LibScrapliMirrorEnv, mirror.URL)
+
+	dir := t.TempDir()
+
+	p, err := scrapligoffi.FetchLibscrapli(
+		context.Background(),
+		testLibscrapliVersion,
+		testLibscrapliTarget,
+		dir,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := os.ReadFile(p) //nolint: gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(actual, content) {
+		t.Fatalf("expected fetched content %q, got %q", content, actual)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 || entries[0].Name() != filepath.Base(p) {
+		t.Fatalf("expected only the fetched library in the output dir, got %v", entries)
+	}
+}
+
+func TestFetchLibscrapliRequireChecksum(t *testing.T) {
+	mirror := newMirror(t, testLibscrapliVersion, []byte("not really a shared library"))
+
+	t.Setenv(scrapligoconstants.LibScrapliMirrorEnv, mirror.URL)
+	t.Setenv(scrapligoconstants.LibScrapliRequireChecksumEnv, "1")
+
+	dir := t.TempDir()
+
+	// the test version is never pinned, so this must be refused and leave nothing behind
+	_, err := scrapligoffi.FetchLibscrapli(
+		context.Background(),
+		testLibscrapliVersion,
+		testLibscrapliTarget,
+		dir,
+	)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 0 {
+		t.Fatalf("expected empty output dir, got %v", entries)
+	}
+}
+
+func TestFetchLibscrapliDefaultVersionUnverified(t *testing.T) {
+	mirror := newMirror(
+		t,
+		scrapligoconstants.LibScrapliVersion,
+		[]byte("not really a shared library"),
+	)
+
+	t.Setenv(scrapligoconstants.LibScrapliMirrorEnv, mirror.URL)
+
+	// the default version is always pinned and this content never matches its pinned checksum, so
+	// this must be refused as a mismatch even w/out requiring checksums
+	_, err := scrapligoffi.FetchLibscrapli(
+		context.Background(),
+		scrapligoconstants.LibScrapliVersion,
+		testLibscrapliTarget,
+		t.TempDir(),
+	)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	if !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("expected a checksum mismatch error, got %q", err)
+	}
+}
+
+func TestLibscrapliChecksumsPinned(t *testing.T) {
+	var checksums map[string]map[string]string
+
+	err := json.Unmarshal(scrapligoassets.LibScrapliChecksums, &checksums)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// every target of the default version must be pinned or nobody w/out a path override can load
+	// libscrapli, run build/update_libscrapli_checksums.sh when bumping the version
+	for _, target := range scrapligoffi.LibscrapliTargets() {
+		filename, err := scrapligoffi.LibscrapliFilename(
+			target,
+			scrapligoconstants.LibScrapliVersion,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		digest, ok := checksums[scrapligoconstants.LibScrapliVersion][filename]
+		if !ok {
+			t.Errorf(
+				"no pinned checksum for %q of default version %q",
+				filename,
+				scrapligoconstants.LibScrapliVersion,
+			)
+
+			continue
+		}
+
+		if len(digest) != sha256.Size*2 {
+			t.Errorf("pinned checksum for %q is not a sha256 digest: %q", filename, digest)
+		}
+	}
+}
+
+func TestEnsureLibscrapliVerifiesCache(t *testing.T) {
+	cacheDir := t.TempDir()
+
+	t.Setenv(scrapligoconstants.LibScrapliPathOverrideEnv, "")
+	t.Setenv(scrapligoconstants.LibScrapliBundlePathEnv, "")
+	t.Setenv(scrapligoconstants.LibScrapliCacheOverrideEnv, cacheDir)
+	t.Setenv(scrapligoconstants.LibScrapliVersionOverrideEnv, testLibscrapliVersion)
+	t.Setenv(scrapligoconstants.LibScrapliRequireChecksumEnv, "1")
+
+	// seed the cache for every target so whatever this platform resolves to is present
+	for _, target := range scrapligoffi.LibscrapliTargets() {
+		filename, err := scrapligoffi.LibscrapliFilename(target, testLibscrapliVersion)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = os.WriteFile(
+			filepath.Join(cacheDir, filename),
+			[]byte("not really a shared library"),
+			scrapligoconstants.PermissionsOwnerReadWriteEveryoneRead,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the test version is never pinned, so the cached library must be refused rather than used
+	_, err := scrapligoffi.EnsureLibscrapli(context.Background())
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
@@ -4,18 +4,21 @@ import (
 	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
 )
 
+// ReturnCode* are the return codes of all libscrapli functions that return a uint8 -- a non
+// success return code from a submit function (i.e. ls_cli_send_input) means the operation was never
+// submitted.
 const (
-	libscrapliReturnCodeSuccess uint8 = iota
-	libscrapliReturnCodeUnknown
-	libscrapliReturnCodeOutOfMemory
-	libscrapliReturnCodeEOF
-	libscrapliReturnCodeCancelled
-	libscrapliReturnCodeTimeout
-	libscrapliReturnCodeDriver
-	libscrapliReturnCodeSession
-	libscrapliReturnCodeTransport
-	libscrapliReturnCodeOperation
-	libscrapliReturnCodeInvalidArgument
+	ReturnCodeSuccess uint8 = iota
+	ReturnCodeUnknown
+	ReturnCodeOutOfMemory
+	ReturnCodeEOF
+	ReturnCodeCancelled
+	ReturnCodeTimeout
+	ReturnCodeDriver
+	ReturnCodeSession
+	ReturnCodeTransport
+	ReturnCodeOperation
+	ReturnCodeInvalidArgument
 )
 
 // libscrapliResult holds a libscrapli return code and the caller of that functions provided
@@ -40,34 +43,34 @@ func newLibScrapliResult(
 
 // check returns an error if the LibscrapliResult return code is a non-success value.
 func (r libscrapliResult) check() error {
-	if r.rc == libscrapliReturnCodeSuccess {
+	if r.rc == ReturnCodeSuccess {
 		return nil
 	}
 
 	var inner error
 
 	switch r.rc {
-	case libscrapliReturnCodeOutOfMemory:
+	case ReturnCodeOutOfMemory:
 		inner = scrapligoerrors.ErrOutOfMemory
-	case libscrapliReturnCodeEOF:
+	case ReturnCodeEOF:
 		inner = scrapligoerrors.ErrEOF
-	case libscrapliReturnCodeCancelled:
+	case ReturnCodeCancelled:
 		inner = scrapligoerrors.ErrCancelled
-	case libscrapliReturnCodeTimeout:
+	case ReturnCodeTimeout:
 		inner = scrapligoerrors.ErrTimeout
-	case libscrapliReturnCodeDriver:
+	case ReturnCodeDriver:
 		inner = scrapligoerrors.ErrDriver
-	case libscrapliReturnCodeSession:
+	case ReturnCodeSession:
 		inner = scrapligoerrors.ErrSession
-	case libscrapliReturnCodeTransport:
+	case ReturnCodeTransport:
 		inner = scrapligoerrors.ErrTransport
-	case libscrapliReturnCodeOperation:
+	case ReturnCodeOperation:
 		inner = scrapligoerrors.ErrOperation
-	case libscrapliReturnCodeInvalidArgument:
+	case ReturnCodeInvalidArgument:
 		inner = scrapligoerrors.ErrInvalidArgument
-	case libscrapliReturnCodeUnknown:
+	case ReturnCodeUnknown:
 		inner = scrapligoerrors.ErrUnknown
 	}
 
-	return scrapligoerrors.NewFfiError(r.message, inner)
+	return scrapligoerrors.NewOperationError(r.message, inner)
 }
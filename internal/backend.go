@@ -0,0 +1,7 @@
+//go:build !scrapligo_gobackend
+
+package internal
+
+// defaultBackendKind is the backend used unless otherwise specified via options, building with the
+// scrapligo_gobackend tag makes the pure go backend the default.
+const defaultBackendKind = BackendKindLibscrapli
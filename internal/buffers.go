@@ -0,0 +1,54 @@
+package internal
+
+import (
+	"sync"
+	"unsafe"
+)
+
+const (
+	// maxPooledBufferSize is the largest buffer we return to the pool, keeps the odd huge result
+	// (a giant show run or get-config) from being pinned in memory forever.
+	maxPooledBufferSize = 64 * 1_024
+)
+
+var bufferPool = sync.Pool{ //nolint: gochecknoglobals
+	New: func() any {
+		b := make([]byte, 0)
+
+		return &b
+	},
+}
+
+// GetBuffer returns a (pooled) buffer of length n. The buffer is meant for scratch space when
+// fetching operation results (i.e. error strings) that are not retained past the fetch, and must be
+// returned with PutBuffer once done with.
+func GetBuffer(n uintptr) *[]byte {
+	b, _ := bufferPool.Get().(*[]byte)
+
+	if uintptr(cap(*b)) < n { //nolint: gosec
+		*b = make([]byte, n)
+	}
+
+	*b = (*b)[:n]
+
+	return b
+}
+
+// PutBuffer returns a buffer acquired with GetBuffer to the pool.
+func PutBuffer(b *[]byte) {
+	if cap(*b) > maxPooledBufferSize {
+		return
+	}
+
+	bufferPool.Put(b)
+}
+
+// BytesToString returns a string sharing the memory of b, avoiding a copy for result buffers that
+// are allocated for (and then owned by) a result object -- b must never be modified afterward.
+func BytesToString(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+
+	return unsafe.String(&b[0], len(b))
+}
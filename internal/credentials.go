@@ -0,0 +1,137 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+
+	scrapligocredentials "github.com/scrapli/scrapligo/v2/credentials"
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+)
+
+// resolveCredentials returns the credential sets of all configured providers for host, in
+// provider order. A failing provider does not keep the sets of later providers from being tried,
+// the failures are returned (joined) alongside whatever sets were resolved.
+func (o *Options) resolveCredentials(
+	ctx context.Context,
+	host string,
+) ([]*scrapligocredentials.Credentials, error) {
+	var sets []*scrapligocredentials.Credentials
+
+	var errs []error
+
+	for idx, provider := range o.Auth.CredentialProviders {
+		providerSets, err := provider.Credentials(ctx, host)
+		if err != nil {
+			o.GetLogger().Warn(
+				fmt.Sprintf("credential provider %d failed for host %q: %s", idx, host, err),
+			)
+
+			errs = append(errs, err)
+
+			continue
+		}
+
+		for _, set := range providerSets {
+			if set != nil {
+				sets = append(sets, set)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return sets, scrapligoerrors.NewOptionsError(
+			"failed resolving credentials",
+			errors.Join(errs...),
+		)
+	}
+
+	return sets, nil
+}
+
+// overlay sets the fields of the credential set on the auth options, fields the set leaves empty
+// are left as they are.
+func (o *AuthOptions) overlay(set *scrapligocredentials.Credentials) {
+	if set.Username != "" {
+		o.Username = set.Username
+	}
+
+	if set.Password != "" {
+		o.Password = set.Password
+	}
+
+	if set.PrivateKey != "" {
+		// the key of the set is the key to use, not whatever may be on disk
+		o.PrivateKeyPath = ""
+		o.PrivateKeyContent = set.PrivateKey
+	}
+
+	if set.PrivateKeyPassphrase != "" {
+		o.PrivateKeyPassphrase = set.PrivateKeyPassphrase
+	}
+
+	if len(set.Lookups) > 0 {
+		if o.LookupMap == nil {
+			o.LookupMap = make(map[string]string, len(set.Lookups))
+		}
+
+		maps.Copy(o.LookupMap, set.Lookups)
+	}
+}
+
+// OpenWithCredentials calls open once with the configured auth options if no credential providers
+// are configured (or they supply nothing), otherwise once per resolved credential set (overlaid on
+// the configured auth options) until one does not fail to authenticate. Failures other than
+// authentication failures are returned as is without trying further sets. The auth options of the
+// successful set are retained, if all sets fail the configured auth options are restored. Sets of
+// the providers that did not fail are tried even if others failed, if no set is resolved at all a
+// provider failure fails the open, otherwise it is only returned if every set fails too.
+func OpenWithCredentials[T any](
+	ctx context.Context,
+	o *Options,
+	host string,
+	open func(ctx context.Context) (T, error),
+) (T, error) {
+	var zero T
+
+	sets, resolveErr := o.resolveCredentials(ctx, host)
+	if len(sets) == 0 {
+		if resolveErr != nil {
+			return zero, resolveErr
+		}
+
+		return open(ctx)
+	}
+
+	var err error
+
+	base := o.Auth
+	base.LookupMap = maps.Clone(o.Auth.LookupMap)
+
+	for _, set := range sets {
+		o.Auth = base
+		o.Auth.LookupMap = maps.Clone(base.LookupMap)
+		o.Auth.overlay(set)
+		o.refreshRedactor()
+
+		var result T
+
+		result, err = open(ctx)
+		if err == nil {
+			return result, nil
+		}
+
+		if !errors.Is(err, scrapligoerrors.ErrAuthenticationFailed) {
+			break
+		}
+	}
+
+	o.Auth = base
+
+	if resolveErr != nil {
+		return zero, errors.Join(err, resolveErr)
+	}
+
+	return zero, err
+}
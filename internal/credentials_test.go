@@ -0,0 +1,208 @@
+package internal_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	scrapligocredentials "github.com/scrapli/scrapligo/v2/credentials"
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
+)
+
+func TestOpenWithCredentials(t *testing.T) {
+	authFailed := scrapligoerrors.NewOperationError("error.AuthenticationFailed", nil)
+
+	cases := map[string]struct {
+		sets             []*scrapligocredentials.Credentials
+		failPasswords    map[string]error
+		expectedErr      error
+		expectedAttempts []string
+		expectedPassword string
+		expectedEnable   string
+	}{
+		"no-sets": {
+			expectedAttempts: []string{"base"},
+			expectedPassword: "base",
+			expectedEnable:   "base-enable",
+		},
+		"first-set": {
+			sets: []*scrapligocredentials.Credentials{
+				{Password: "one", Lookups: map[string]string{"enable": "one-enable"}},
+				{Password: "two"},
+			},
+			expectedAttempts: []string{"one"},
+			expectedPassword: "one",
+			expectedEnable:   "one-enable",
+		},
+		"second-set-after-auth-failure": {
+			sets: []*scrapligocredentials.Credentials{
+				{Password: "one", Lookups: map[string]string{"enable": "one-enable"}},
+				{Password: "two"},
+			},
+			failPasswords:    map[string]error{"one": authFailed},
+			expectedAttempts: []string{"one", "two"},
+			expectedPassword: "two",
+			expectedEnable:   "base-enable",
+		},
+		"all-sets-fail": {
+			sets: []*scrapligocredentials.Credentials{
+				{Password: "one"},
+				{Password: "two"},
+			},
+			failPasswords:    map[string]error{"one": authFailed, "two": authFailed},
+			expectedErr:      scrapligoerrors.ErrAuthenticationFailed,
+			expectedAttempts: []string{"one", "two"},
+			expectedPassword: "base",
+			expectedEnable:   "base-enable",
+		},
+		"other-failure-stops": {
+			sets: []*scrapligocredentials.Credentials{
+				{Password: "one"},
+				{Password: "two"},
+			},
+			failPasswords: map[string]error{
+				"one": scrapligoerrors.NewOperationError("error.ConnectionRefused", nil),
+			},
+			expectedErr:      scrapligoerrors.ErrConnectionRefused,
+			expectedAttempts: []string{"one"},
+			expectedPassword: "base",
+			expectedEnable:   "base-enable",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := scrapligointernal.NewOptions()
+
+			o.Auth.Password = "base"
+			o.Auth.LookupMap["enable"] = "base-enable"
+			o.Auth.CredentialProviders = []scrapligocredentials.Provider{
+				scrapligocredentials.NewStaticProvider(c.sets...),
+			}
+
+			var attempts []string
+
+			_, err := scrapligointernal.OpenWithCredentials(
+				context.Background(),
+				o,
+				"1.2.3.4",
+				func(_ context.Context) (string, error) {
+					attempts = append(attempts, o.Auth.Password)
+
+					return o.Auth.Password, c.failPasswords[o.Auth.Password]
+				},
+			)
+			if c.expectedErr == nil && err != nil {
+				t.Fatal(err)
+			}
+
+			if c.expectedErr != nil && !errors.Is(err, c.expectedErr) {
+				t.Fatalf("expected error %v, got %v", c.expectedErr, err)
+			}
+
+			if len(attempts) != len(c.expectedAttempts) {
+				t.Fatalf("expected attempts %v, got %v", c.expectedAttempts, attempts)
+			}
+
+			for idx := range attempts {
+				if attempts[idx] != c.expectedAttempts[idx] {
+					t.Fatalf("expected attempts %v, got %v", c.expectedAttempts, attempts)
+				}
+			}
+
+			if o.Auth.Password != c.expectedPassword {
+				t.Fatalf("expected password %q, got %q", c.expectedPassword, o.Auth.Password)
+			}
+
+			if o.Auth.LookupMap["enable"] != c.expectedEnable {
+				t.Fatalf("expected enable %q, got %q", c.expectedEnable, o.Auth.LookupMap["enable"])
+			}
+		})
+	}
+}
+
+func TestOpenWithCredentialsProviderError(t *testing.T) {
+	o := scrapligointernal.NewOptions()
+
+	o.Auth.CredentialProviders = []scrapligocredentials.Provider{
+		scrapligocredentials.ProviderFunc(
+			func(_ context.Context, _ string) ([]*scrapligocredentials.Credentials, error) {
+				return nil, errors.New("vault sealed")
+			},
+		),
+	}
+
+	_, err := scrapligointernal.OpenWithCredentials(
+		context.Background(),
+		o,
+		"1.2.3.4",
+		func(_ context.Context) (string, error) {
+			t.Fatal("open called despite provider error")
+
+			return "", nil
+		},
+	)
+	if err == nil {
+		t.Fatal("expected provider error")
+	}
+}
+
+func TestOpenWithCredentialsProviderErrorLaterProvider(t *testing.T) {
+	providerErr := errors.New("vault sealed")
+
+	cases := map[string]struct {
+		failPasswords map[string]error
+		expectedErr   error
+	}{
+		"later-set-authenticates": {},
+		"later-set-fails": {
+			failPasswords: map[string]error{
+				"two": scrapligoerrors.NewOperationError("error.AuthenticationFailed", nil),
+			},
+			expectedErr: providerErr,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := scrapligointernal.NewOptions()
+
+			o.Auth.CredentialProviders = []scrapligocredentials.Provider{
+				scrapligocredentials.ProviderFunc(
+					func(_ context.Context, _ string) ([]*scrapligocredentials.Credentials, error) {
+						return nil, providerErr
+					},
+				),
+				scrapligocredentials.NewStaticProvider(
+					&scrapligocredentials.Credentials{Password: "two"},
+				),
+			}
+
+			var attempts []string
+
+			_, err := scrapligointernal.OpenWithCredentials(
+				context.Background(),
+				o,
+				"1.2.3.4",
+				func(_ context.Context) (string, error) {
+					attempts = append(attempts, o.Auth.Password)
+
+					return o.Auth.Password, c.failPasswords[o.Auth.Password]
+				},
+			)
+			if c.expectedErr == nil && err != nil {
+				t.Fatal(err)
+			}
+
+			if c.expectedErr != nil && (!errors.Is(err, c.expectedErr) ||
+				!errors.Is(err, scrapligoerrors.ErrAuthenticationFailed)) {
+				t.Fatalf("expected provider and authentication errors, got %v", err)
+			}
+
+			if len(attempts) != 1 || attempts[0] != "two" {
+				t.Fatalf("expected the later provider's set to be tried, got %v", attempts)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package internal
+
+import (
+	"slices"
+	"strings"
+
+	scrapligodryrun "github.com/scrapli/scrapligo/v2/dryrun"
+)
+
+// DryRunOptions holds the dry-run options for drivers, drivers are in dry-run mode when Plan is
+// set.
+type DryRunOptions struct {
+	Plan        *scrapligodryrun.Plan
+	ConfigModes []string
+}
+
+// IsConfigMode returns true if inputs sent in mode change configuration -- mode is one of the
+// configured modes or, if none are configured, any mode with "config" in its name.
+func (o *DryRunOptions) IsConfigMode(mode string) bool {
+	if len(o.ConfigModes) > 0 {
+		return slices.Contains(o.ConfigModes, mode)
+	}
+
+	return strings.Contains(mode, "config")
+}
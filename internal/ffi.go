@@ -1,5 +1,11 @@
 package internal
 
+import (
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
 type driverOptions struct {
 	userData uintptr
 
@@ -108,3 +114,49 @@ type driverOptions struct {
 		}
 	}
 }
+
+var (
+	goDriverOptionsLock sync.Mutex             //nolint: gochecknoglobals
+	goDriverOptions     = map[uintptr][]byte{} //nolint: gochecknoglobals
+)
+
+// AllocGoDriverOptions allocates a driver options struct and returns a pointer to it, this is the
+// non libscrapli counterpart of ls_alloc_driver_options for the go backend. Just like libscrapli's
+// the struct lives outside of the go heap (so Options.Apply can treat both the same way) and is
+// kept until it is freed with FreeGoDriverOptions. Returns 0 if the allocation fails.
+func AllocGoDriverOptions() uintptr {
+	goDriverOptionsLock.Lock()
+	defer goDriverOptionsLock.Unlock()
+
+	b, err := syscall.Mmap(
+		-1,
+		0,
+		int(unsafe.Sizeof(driverOptions{})),
+		syscall.PROT_READ|syscall.PROT_WRITE,
+		syscall.MAP_ANON|syscall.MAP_PRIVATE,
+	)
+	if err != nil {
+		return 0
+	}
+
+	optionsPtr := uintptr(unsafe.Pointer(&b[0]))
+
+	goDriverOptions[optionsPtr] = b
+
+	return optionsPtr
+}
+
+// FreeGoDriverOptions frees a driver options struct allocated with AllocGoDriverOptions.
+func FreeGoDriverOptions(optionsPtr uintptr) {
+	goDriverOptionsLock.Lock()
+	defer goDriverOptionsLock.Unlock()
+
+	b, ok := goDriverOptions[optionsPtr]
+	if !ok {
+		return
+	}
+
+	delete(goDriverOptions, optionsPtr)
+
+	_ = syscall.Munmap(b)
+}
@@ -0,0 +1,404 @@
+// Package gobackend is a pure go implementation of the libscrapli driver surface, built on
+// golang.org/x/crypto/ssh. It exposes itself as an ffi.Mapping (bound to go funcs rather than
+// libscrapli symbols) so that Cli and Netconf objects behave identically -- and return the exact
+// same Result types -- regardless of the backend in use.
+package gobackend
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
+	scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
+	scrapligologging "github.com/scrapli/scrapligo/v2/logging"
+)
+
+// GetMapping returns the Mapping for the backend selected in the given options -- the libscrapli
+// singleton mapping, or a mapping to a go backend bound to the given options.
+func GetMapping(o *scrapligointernal.Options) (*scrapligoffi.Mapping, error) {
+	if o.Backend != scrapligointernal.BackendKindGo {
+		return scrapligoffi.GetMapping()
+	}
+
+	b := &backend{
+		options: o,
+		drivers: map[uintptr]any{},
+	}
+
+	return scrapligoffi.NewMappingFromSymbols(b.symbols())
+}
+
+// backend is the go backend for a single Cli or Netconf object, the options are the options of
+// that object.
+type backend struct {
+	options *scrapligointernal.Options
+
+	// render is set for backends that render netconf rpcs rather than submit them, see
+	// RenderNetconfRPC
+	render func(r *RenderedRPC)
+
+	lock    sync.Mutex
+	drivers map[uintptr]any
+	nextPtr uintptr
+}
+
+func (b *backend) symbols() map[string]any {
+	return map[string]any{
+		// there is no allocator to check for leaks, so... no leaks!
+		"ls_assert_no_leaks": func() bool { return true },
+
+		"ls_shared_get_poll_fd":   b.getPollFd,
+		"ls_shared_free":          b.free,
+		"ls_alloc_driver_options": scrapligointernal.AllocGoDriverOptions,
+		"ls_free_driver_options":  scrapligointernal.FreeGoDriverOptions,
+		"ls_fetch_options_size":   b.fetchOptionsSize,
+		"ls_fetch_options":        b.fetchOptions,
+
+		"ls_session_read":             b.sessionRead,
+		"ls_session_write":            b.sessionWrite,
+		"ls_session_write_and_return": b.sessionWriteAndReturn,
+		"ls_session_write_return":     b.sessionWriteReturn,
+
+		"ls_cli_alloc":                        b.cliAlloc,
+		"ls_cli_open":                         b.cliOpen,
+		"ls_cli_close":                        b.cliClose,
+		"ls_cli_fetch_operation_sizes":        b.cliFetchOperationSizes,
+		"ls_cli_fetch_operation":              b.cliFetchOperation,
+		"ls_cli_enter_mode":                   b.cliEnterMode,
+		"ls_cli_get_prompt":                   b.cliGetPrompt,
+		"ls_cli_send_input":                   b.cliSendInput,
+		"ls_cli_send_inputs":                  b.cliSendInputs,
+		"ls_cli_send_prompted_input":          b.cliSendPromptedInput,
+		"ls_cli_read_any":                     b.cliReadAny,
+		"ls_cli_read_callback_should_execute": readCallbackShouldExecute,
+		"ls_cli_replace_definition":           b.cliReplaceDefinition,
+
+		"ls_netconf_alloc":                          b.netconfAlloc,
+		"ls_netconf_open":                           b.netconfOpen,
+		"ls_netconf_close":                          b.netconfClose,
+		"ls_netconf_fetch_operation_sizes":          b.netconfFetchOperationSizes,
+		"ls_netconf_fetch_operation":                b.netconfFetchOperation,
+		"ls_netconf_get_session_id":                 b.netconfGetSessionID,
+		"ls_netconf_get_subscription_id":            getSubscriptionID,
+		"ls_netconf_next_notification_message_size": b.netconfNextNotificationSize,
+		"ls_netconf_next_notification_message":      b.netconfNextNotification,
+		"ls_netconf_next_subscription_message_size": b.netconfNextSubscriptionSize,
+		"ls_netconf_next_subscription_message":      b.netconfNextSubscription,
+		"ls_netconf_raw_rpc":                        b.netconfRawRPC,
+		"ls_netconf_get_config":                     b.netconfGetConfig,
+		"ls_netconf_edit_config":                    b.netconfEditConfig,
+		"ls_netconf_copy_config":                    b.netconfCopyConfig,
+		"ls_netconf_delete_config":                  b.netconfDeleteConfig,
+		"ls_netconf_lock":                           b.netconfLock,
+		"ls_netconf_unlock":                         b.netconfUnlock,
+		"ls_netconf_get":                            b.netconfGet,
+		"ls_netconf_close_session":                  b.netconfCloseSession,
+		"ls_netconf_kill_session":                   b.netconfKillSession,
+		"ls_netconf_commit":                         b.netconfCommit,
+		"ls_netconf_discard":                        b.netconfDiscard,
+		"ls_netconf_cancel_commit":                  b.netconfCancelCommit,
+		"ls_netconf_validate":                       b.netconfValidate,
+		"ls_netconf_get_schema":                     b.netconfGetSchema,
+		"ls_netconf_get_data":                       b.netconfGetData,
+		"ls_netconf_edit_data":                      b.netconfEditData,
+		"ls_netconf_action":                         b.netconfAction,
+	}
+}
+
+// register registers the driver and returns its "pointer" -- just an id really, but it is never
+// zero, which is all the Cli/Netconf objects care about.
+func (b *backend) register(d any) uintptr {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.nextPtr++
+
+	b.drivers[b.nextPtr] = d
+
+	return b.nextPtr
+}
+
+func (b *backend) getDriver(driverPtr uintptr) *driver {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	switch d := b.drivers[driverPtr].(type) {
+	case *cliDriver:
+		return d.driver
+	case *netconfDriver:
+		return d.driver
+	default:
+		return nil
+	}
+}
+
+func (b *backend) getPollFd(driverPtr uintptr) uint32 {
+	d := b.getDriver(driverPtr)
+	if d == nil {
+		return 0
+	}
+
+	return uint32(d.pollR.Fd()) //nolint: gosec
+}
+
+func (b *backend) free(driverPtr uintptr) {
+	b.lock.Lock()
+
+	d, ok := b.drivers[driverPtr]
+
+	delete(b.drivers, driverPtr)
+
+	b.lock.Unlock()
+
+	if !ok {
+		return
+	}
+
+	switch td := d.(type) {
+	case *cliDriver:
+		td.free()
+	case *netconfDriver:
+		td.free()
+	}
+}
+
+// goBackendOptions is the (json) representation of the options as returned by FetchOptions.
+type goBackendOptions struct {
+	Backend            string  `json:"backend"`
+	Port               uint16  `json:"port"`
+	TransportKind      uint8   `json:"transport_kind"`
+	DefinitionPlatform string  `json:"definition_platform"`
+	Username           string  `json:"username"`
+	ReturnChar         string  `json:"return_char"`
+	OperationTimeoutNs *uint64 `json:"operation_timeout_ns"`
+}
+
+func (b *backend) renderOptions() []byte {
+	out, _ := json.Marshal(goBackendOptions{ //nolint: errchkjson
+		Backend:            "go",
+		Port:               b.options.Port,
+		TransportKind:      uint8(b.options.TransportKind),
+		DefinitionPlatform: b.options.Cli.DefinitionPlatform,
+		Username:           b.options.Auth.Username,
+		ReturnChar:         b.options.Session.ReturnChar,
+		OperationTimeoutNs: b.options.Session.OperationTimeoutNs,
+	})
+
+	return out
+}
+
+func (b *backend) fetchOptionsSize(_ uintptr, optionsSize *uintptr) uint8 {
+	*optionsSize = uintptr(len(b.renderOptions()))
+
+	return scrapligoffi.ReturnCodeSuccess
+}
+
+func (b *backend) fetchOptions(_ uintptr, options *[]byte) uint8 {
+	copy(*options, b.renderOptions())
+
+	return scrapligoffi.ReturnCodeSuccess
+}
+
+func (b *backend) sessionRead(driverPtr uintptr, buf *[]byte, readSize *uintptr) uint8 {
+	d := b.getDriver(driverPtr)
+	if d == nil || d.sess == nil {
+		return scrapligoffi.ReturnCodeInvalidArgument
+	}
+
+	out := d.sess.readAvailable(len(*buf))
+
+	*readSize = uintptr(copy(*buf, out))
+
+	return scrapligoffi.ReturnCodeSuccess
+}
+
+func (b *backend) sessionWriteOp(driverPtr uintptr, f func(s *session) error) uint8 {
+	d := b.getDriver(driverPtr)
+	if d == nil || d.sess == nil {
+		return scrapligoffi.ReturnCodeInvalidArgument
+	}
+
+	err := f(d.sess)
+	if err != nil {
+		d.l.Critical(err.Error())
+
+		return scrapligoffi.ReturnCodeSession
+	}
+
+	return scrapligoffi.ReturnCodeSuccess
+}
+
+func (b *backend) sessionWrite(driverPtr uintptr, buf string, _ bool) uint8 {
+	return b.sessionWriteOp(driverPtr, func(s *session) error { return s.write(buf) })
+}
+
+func (b *backend) sessionWriteAndReturn(driverPtr uintptr, buf string, _ bool) uint8 {
+	return b.sessionWriteOp(driverPtr, func(s *session) error { return s.writeAndReturn(buf) })
+}
+
+func (b *backend) sessionWriteReturn(driverPtr uintptr) uint8 {
+	return b.sessionWriteOp(driverPtr, func(s *session) error { return s.writeReturn() })
+}
+
+// operationResult holds the result of a completed operation until it is fetched. Cli operations
+// use the splits and failed indicator, netconf operations the rpc warnings/errors.
+type operationResult struct {
+	startTime       uint64
+	endTime         uint64
+	operationCount  uint32
+	splits          []uint64
+	input           []byte
+	resultRaw       []byte
+	result          []byte
+	failedIndicator []byte
+	rpcWarnings     []byte
+	rpcErrors       []byte
+	err             []byte
+}
+
+// driver holds the things common to the cli and netconf drivers -- the ready signal pipe (the
+// equivalent of libscrapli's poll fd), the session, and the results of completed operations.
+type driver struct {
+	host    string
+	options *scrapligointernal.Options
+	l       *scrapligologging.AnyLogger
+
+	pollR *os.File
+	pollW *os.File
+
+	sess *session
+
+	operationsLock  sync.Mutex
+	nextOperationID uint32
+	operations      map[uint32]*operationResult
+	recorder        *os.File
+}
+
+func newDriver(host string, o *scrapligointernal.Options) (*driver, error) {
+	pollR, pollW, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+
+	return &driver{
+		host:       host,
+		options:    o,
+		l:          o.GetLogger(),
+		pollR:      pollR,
+		pollW:      pollW,
+		operations: map[uint32]*operationResult{},
+	}, nil
+}
+
+// submit allocates an operation id and runs f in a new goroutine, once done the result is stored
+// and the ready signal written. Operations run concurrently (netconf rpcs are pipelined) so results
+// are published in whatever order the operations complete.
+func (d *driver) submit(operationID *uint32, f func() *operationResult) {
+	d.operationsLock.Lock()
+
+	d.nextOperationID++
+
+	*operationID = d.nextOperationID
+
+	d.operations[*operationID] = nil
+
+	d.operationsLock.Unlock()
+
+	id := *operationID
+
+	go func() {
+		r := f()
+
+		d.operationsLock.Lock()
+		d.operations[id] = r
+		d.operationsLock.Unlock()
+
+		_, err := d.pollW.Write([]byte{1})
+		if err != nil {
+			d.l.Critical("failed writing operation ready signal: " + err.Error())
+		}
+	}()
+}
+
+// getOperation returns the result of the operation if it is complete, if take is true the result
+// is removed -- results can only be fetched once.
+func (d *driver) getOperation(operationID uint32, take bool) *operationResult {
+	d.operationsLock.Lock()
+	defer d.operationsLock.Unlock()
+
+	r := d.operations[operationID]
+	if r != nil && take {
+		delete(d.operations, operationID)
+	}
+
+	return r
+}
+
+// openSession opens the transport and sets up the session (and recorder if configured).
+func (d *driver) openSession(t transport) error {
+	var record func(b []byte)
+
+	switch {
+	case d.options.Session.RecorderPath != "":
+		f, err := os.Create(d.options.Session.RecorderPath)
+		if err != nil {
+			return err
+		}
+
+		d.recorder = f
+
+		record = func(b []byte) {
+			_, _ = f.Write(b)
+		}
+	case d.options.Session.RecorderCallback != nil:
+		recorderCallback := d.options.GetRecorderCallback()
+
+		record = func(b []byte) {
+			recorderCallback(string(b))
+		}
+	}
+
+	readSize := defaultReadSize
+	if d.options.Session.ReadSize != nil {
+		readSize = int(*d.options.Session.ReadSize) //nolint: gosec
+	}
+
+	d.sess = newSession(t, readSize, d.options.Session.ReturnChar, record)
+
+	return nil
+}
+
+func (d *driver) searchDepth() int {
+	if d.options.Session.OperationMaxSearchDepth != nil {
+		return int(*d.options.Session.OperationMaxSearchDepth) //nolint: gosec
+	}
+
+	return defaultMaxSearchDepth
+}
+
+func (d *driver) newControl(cancel *bool) *control {
+	return newControl(cancel, d.options.Session.OperationTimeoutNs)
+}
+
+func (d *driver) free() {
+	if d.sess != nil {
+		_ = d.sess.close()
+	}
+
+	if d.recorder != nil {
+		_ = d.recorder.Close()
+	}
+
+	_ = d.pollW.Close()
+	_ = d.pollR.Close()
+}
+
+// errResult returns an operationResult for a failed operation.
+func errResult(startTime uint64, err error) *operationResult {
+	return &operationResult{
+		startTime: startTime,
+		endTime:   nowNs(),
+		err:       []byte(err.Error()),
+	}
+}
@@ -0,0 +1,77 @@
+package gobackend_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	scrapligocli "github.com/scrapli/scrapligo/v2/cli"
+	scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
+	scrapligogobackend "github.com/scrapli/scrapligo/v2/internal/gobackend"
+	scrapligooptions "github.com/scrapli/scrapligo/v2/options"
+)
+
+func TestGetMapping(t *testing.T) {
+	o := scrapligointernal.NewOptions()
+
+	err := scrapligooptions.WithBackendGo()(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// binding fails if any symbol is missing or has the wrong signature
+	m, err := scrapligogobackend.GetMapping(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !m.AssertNoLeaks() {
+		t.Fatal("expected go backend to report no leaks")
+	}
+}
+
+// TestCliGetPrompt runs a cli against a libscrapli test fixture, the go backend has to arrive at
+// the same result libscrapli did.
+func TestCliGetPrompt(t *testing.T) {
+	testFixturePath, err := filepath.Abs("../../cli/fixtures/get-prompt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	c, err := scrapligocli.NewCli(
+		"localhost",
+		scrapligooptions.WithBackendGo(),
+		scrapligooptions.WithUsername("admin"),
+		scrapligooptions.WithPassword("admin"),
+		scrapligooptions.WithLookupKeyValue("enable", "libscrapli"),
+		scrapligooptions.WithDefinitionFileOrName(scrapligocli.AristaEos),
+		scrapligooptions.WithTransportTest(),
+		scrapligooptions.WithTestTransportF(testFixturePath),
+		scrapligooptions.WithReadSize(1),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = c.Open(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_, _ = c.Close(ctx)
+	}()
+
+	r, err := c.GetPrompt(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Result() != "eos1#" {
+		t.Fatalf("expected prompt %q, got %q", "eos1#", r.Result())
+	}
+}
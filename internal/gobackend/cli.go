@@ -0,0 +1,940 @@
+package gobackend
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	scrapligoconstants "github.com/scrapli/scrapligo/v2/constants"
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
+	scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
+)
+
+// input handling values, see cli.InputHandling.
+const (
+	inputHandlingExact uint8 = iota
+	inputHandlingFuzzy
+	inputHandlingIgnore
+)
+
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;?]*[a-zA-Z]`) //nolint: gochecknoglobals
+
+func nowNs() uint64 {
+	return uint64(time.Now().UnixNano()) //nolint: gosec
+}
+
+// cliDriver is the go backend flavor of the libscrapli cli driver.
+type cliDriver struct {
+	*driver
+
+	// opLock serializes operations, the cli object queues operations anyway, but open/close and
+	// the like are operations too
+	opLock sync.Mutex
+
+	def *definition
+
+	// prompt is the most recently seen prompt -- libscrapli results of inputs (when input is
+	// retained) begin with the prompt the input was sent at
+	prompt string
+}
+
+// cliOutput collects the per input outputs of an operation.
+type cliOutput struct {
+	inputs          []string
+	resultsRaw      [][]byte
+	results         []string
+	splits          []uint64
+	failedIndicator string
+}
+
+func (o *cliOutput) add(input string, raw []byte, result, failedIndicator string) {
+	o.inputs = append(o.inputs, input)
+	o.resultsRaw = append(o.resultsRaw, raw)
+	o.results = append(o.results, result)
+	o.splits = append(o.splits, nowNs())
+
+	if o.failedIndicator == "" {
+		o.failedIndicator = failedIndicator
+	}
+}
+
+func (o *cliOutput) toResult(startTime uint64) *operationResult {
+	return &operationResult{
+		startTime:      startTime,
+		endTime:        nowNs(),
+		operationCount: uint32(len(o.splits)), //nolint: gosec
+		splits:         o.splits,
+		input:          []byte(strings.Join(o.inputs, scrapligoconstants.LibScrapliDelimiter)),
+		resultRaw: bytes.Join(
+			o.resultsRaw,
+			[]byte(scrapligoconstants.LibScrapliDelimiter),
+		),
+		result:          []byte(strings.Join(o.results, scrapligoconstants.LibScrapliDelimiter)),
+		failedIndicator: []byte(o.failedIndicator),
+	}
+}
+
+func (b *backend) getCli(driverPtr uintptr) *cliDriver {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	d, _ := b.drivers[driverPtr].(*cliDriver)
+
+	return d
+}
+
+func (b *backend) cliAlloc(host string, _ uintptr) uintptr {
+	def, err := parseDefinition(b.options.Cli.DefinitionString)
+	if err != nil {
+		b.options.GetLogger().Critical(err.Error())
+
+		return 0
+	}
+
+	d, err := newDriver(host, b.options)
+	if err != nil {
+		b.options.GetLogger().Critical(err.Error())
+
+		return 0
+	}
+
+	return b.register(&cliDriver{driver: d, def: def})
+}
+
+// cliOp submits f as an operation of the cli driver at driverPtr.
+func (b *backend) cliOp(
+	driverPtr uintptr,
+	operationID *uint32,
+	cancel *bool,
+	f func(d *cliDriver, c *control) (*cliOutput, error),
+) uint8 {
+	d := b.getCli(driverPtr)
+	if d == nil {
+		return scrapligoffi.ReturnCodeInvalidArgument
+	}
+
+	d.submit(operationID, func() *operationResult {
+		d.opLock.Lock()
+		defer d.opLock.Unlock()
+
+		startTime := nowNs()
+
+		out, err := f(d, d.newControl(cancel))
+		if err != nil {
+			return errResult(startTime, err)
+		}
+
+		return out.toResult(startTime)
+	})
+
+	return scrapligoffi.ReturnCodeSuccess
+}
+
+func (b *backend) cliOpen(driverPtr uintptr, operationID *uint32, cancel *bool) uint8 {
+	return b.cliOp(driverPtr, operationID, cancel, (*cliDriver).open)
+}
+
+func (b *backend) cliClose(driverPtr uintptr, operationID *uint32, cancel *bool) uint8 {
+	return b.cliOp(driverPtr, operationID, cancel, (*cliDriver).close)
+}
+
+func (b *backend) cliFetchOperationSizes(
+	driverPtr uintptr,
+	operationID uint32,
+	operationCount *uint32,
+	inputsSize,
+	resultsRawSize,
+	resultsSize,
+	resultsFailedIndicatorSize,
+	errSize,
+	lastErrStrSize *uintptr,
+) uint8 {
+	d := b.getCli(driverPtr)
+	if d == nil {
+		return scrapligoffi.ReturnCodeInvalidArgument
+	}
+
+	r := d.getOperation(operationID, false)
+	if r == nil {
+		return scrapligoffi.ReturnCodeOperation
+	}
+
+	*operationCount = r.operationCount
+	*inputsSize = uintptr(len(r.input))
+	*resultsRawSize = uintptr(len(r.resultRaw))
+	*resultsSize = uintptr(len(r.result))
+	*resultsFailedIndicatorSize = uintptr(len(r.failedIndicator))
+	*errSize = uintptr(len(r.err))
+	*lastErrStrSize = 0
+
+	return scrapligoffi.ReturnCodeSuccess
+}
+
+func (b *backend) cliFetchOperation(
+	driverPtr uintptr,
+	operationID uint32,
+	resultStartTime *uint64,
+	splits *[]uint64,
+	inputs,
+	resultsRaw,
+	results,
+	resultsFailedIndicator,
+	err,
+	_ *[]byte,
+) uint8 {
+	d := b.getCli(driverPtr)
+	if d == nil {
+		return scrapligoffi.ReturnCodeInvalidArgument
+	}
+
+	r := d.getOperation(operationID, true)
+	if r == nil {
+		return scrapligoffi.ReturnCodeOperation
+	}
+
+	*resultStartTime = r.startTime
+
+	copy(*splits, r.splits)
+	copy(*inputs, r.input)
+	copy(*resultsRaw, r.resultRaw)
+	copy(*results, r.result)
+	copy(*resultsFailedIndicator, r.failedIndicator)
+	copy(*err, r.err)
+
+	return scrapligoffi.ReturnCodeSuccess
+}
+
+func (b *backend) cliEnterMode(
+	driverPtr uintptr,
+	operationID *uint32,
+	cancel *bool,
+	requestedMode string,
+) uint8 {
+	return b.cliOp(
+		driverPtr,
+		operationID,
+		cancel,
+		func(d *cliDriver, c *control) (*cliOutput, error) {
+			raw, result, err := d.enterMode(c, requestedMode)
+			if err != nil {
+				return nil, err
+			}
+
+			out := &cliOutput{}
+			out.add(requestedMode, raw, result, "")
+
+			return out, nil
+		},
+	)
+}
+
+func (b *backend) cliGetPrompt(driverPtr uintptr, operationID *uint32, cancel *bool) uint8 {
+	return b.cliOp(
+		driverPtr,
+		operationID,
+		cancel,
+		func(d *cliDriver, c *control) (*cliOutput, error) {
+			raw, err := d.getPrompt(c)
+			if err != nil {
+				return nil, err
+			}
+
+			out := &cliOutput{}
+			out.add("", raw, d.prompt, "")
+
+			return out, nil
+		},
+	)
+}
+
+func (b *backend) cliSendInput(
+	driverPtr uintptr,
+	operationID *uint32,
+	cancel *bool,
+	input string,
+	requestedMode string,
+	inputHandling *uint8,
+	retainInput bool,
+	retainTrailingPrompt bool,
+) uint8 {
+	return b.cliSendInputs(
+		driverPtr,
+		operationID,
+		cancel,
+		input,
+		requestedMode,
+		inputHandling,
+		retainInput,
+		retainTrailingPrompt,
+	)
+}
+
+func (b *backend) cliSendInputs(
+	driverPtr uintptr,
+	operationID *uint32,
+	cancel *bool,
+	inputs string,
+	requestedMode string,
+	inputHandling *uint8,
+	retainInput bool,
+	retainTrailingPrompt bool,
+) uint8 {
+	handling := inputHandlingFuzzy
+	if inputHandling != nil {
+		handling = *inputHandling
+	}
+
+	return b.cliOp(
+		driverPtr,
+		operationID,
+		cancel,
+		func(d *cliDriver, c *control) (*cliOutput, error) {
+			err := d.acquireMode(c, requestedMode)
+			if err != nil {
+				return nil, err
+			}
+
+			out := &cliOutput{}
+
+			for input := range strings.SplitSeq(inputs, scrapligoconstants.LibScrapliDelimiter) {
+				raw, result, err := d.sendInput(
+					c,
+					input,
+					handling,
+					retainInput,
+					retainTrailingPrompt,
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				out.add(input, raw, result, d.def.failureIndicator(result))
+			}
+
+			return out, nil
+		},
+	)
+}
+
+func (b *backend) cliSendPromptedInput(
+	driverPtr uintptr,
+	operationID *uint32,
+	cancel *bool,
+	input string,
+	prompt string,
+	promptPattern string,
+	response string,
+	abortInput string,
+	requestedMode string,
+	inputHandling *uint8,
+	hiddenInput bool,
+	retainTrailingPrompt bool,
+) uint8 {
+	handling := inputHandlingFuzzy
+	if inputHandling != nil {
+		handling = *inputHandling
+	}
+
+	return b.cliOp(
+		driverPtr,
+		operationID,
+		cancel,
+		func(d *cliDriver, c *control) (*cliOutput, error) {
+			err := d.acquireMode(c, requestedMode)
+			if err != nil {
+				return nil, err
+			}
+
+			raw, result, err := d.sendPromptedInput(
+				c,
+				input,
+				prompt,
+				promptPattern,
+				response,
+				handling,
+				hiddenInput,
+				retainTrailingPrompt,
+			)
+			if err != nil {
+				if abortInput != "" {
+					// best effort get the device back to a sane state
+					_ = d.sess.writeAndReturn(abortInput)
+				}
+
+				return nil, err
+			}
+
+			out := &cliOutput{}
+			out.add(input, raw, result, d.def.failureIndicator(result))
+
+			return out, nil
+		},
+	)
+}
+
+func (b *backend) cliReadAny(driverPtr uintptr, operationID *uint32, cancel *bool) uint8 {
+	return b.cliOp(
+		driverPtr,
+		operationID,
+		cancel,
+		func(d *cliDriver, c *control) (*cliOutput, error) {
+			raw, err := d.sess.readAny(c)
+			if err != nil {
+				return nil, err
+			}
+
+			// read any is "raw-ish" -- only ansi sequences are stripped, line feeds and whitespace
+			// are left alone as the caller is likely matching on them
+			out := &cliOutput{}
+			out.add("", raw, ansiPattern.ReplaceAllString(string(raw), ""), "")
+
+			return out, nil
+		},
+	)
+}
+
+func readCallbackShouldExecute(
+	buf string,
+	_ string,
+	contains string,
+	containsPattern string,
+	notContains string,
+	execute *bool,
+) uint8 {
+	*execute = false
+
+	if contains != "" && !strings.Contains(buf, contains) {
+		return scrapligoffi.ReturnCodeSuccess
+	}
+
+	if containsPattern != "" {
+		pattern, err := compilePattern(containsPattern)
+		if err != nil {
+			return scrapligoffi.ReturnCodeInvalidArgument
+		}
+
+		if !pattern.MatchString(buf) {
+			return scrapligoffi.ReturnCodeSuccess
+		}
+	}
+
+	if notContains != "" && strings.Contains(buf, notContains) {
+		return scrapligoffi.ReturnCodeSuccess
+	}
+
+	*execute = true
+
+	return scrapligoffi.ReturnCodeSuccess
+}
+
+func (b *backend) cliReplaceDefinition(driverPtr uintptr, definitionString string) uint8 {
+	d := b.getCli(driverPtr)
+	if d == nil {
+		return scrapligoffi.ReturnCodeInvalidArgument
+	}
+
+	def, err := parseDefinition(definitionString)
+	if err != nil {
+		d.l.Critical(err.Error())
+
+		return scrapligoffi.ReturnCodeInvalidArgument
+	}
+
+	d.opLock.Lock()
+	defer d.opLock.Unlock()
+
+	d.def = def
+
+	return scrapligoffi.ReturnCodeSuccess
+}
+
+func (d *cliDriver) free() {
+	d.driver.free()
+}
+
+// requiresInSessionAuth returns true if auth happens "in" the session -- always for the test
+// transport (recordings include the auth prompts), otherwise only if forced.
+func (d *cliDriver) requiresInSessionAuth() bool {
+	if d.options.Auth.BypassInSessionAuth {
+		return false
+	}
+
+	return d.options.TransportKind == scrapligointernal.TransportKindTest ||
+		d.options.Auth.ForceInSessionAuth ||
+		d.def.forceInSessionAuth
+}
+
+func (d *cliDriver) open(c *control) (*cliOutput, error) {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	t, err := openTransport(ctx, d.host, d.options, "")
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.openSession(t)
+	if err != nil {
+		_ = t.Close()
+
+		return nil, err
+	}
+
+	var raw []byte
+
+	if d.requiresInSessionAuth() {
+		username, password, passphrase, err := inSessionAuthPatterns(
+			d.options.Auth.UsernamePattern,
+			d.options.Auth.PasswordPattern,
+			d.options.Auth.PassphrasePattern,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		raw, err = d.sess.inSessionAuth(
+			c,
+			authCredentials{
+				username:   d.options.Auth.Username,
+				password:   d.options.Auth.Password,
+				passphrase: d.options.Auth.PrivateKeyPassphrase,
+			},
+			[3]*regexp.Regexp{username, password, passphrase},
+			promptMatcher(d.def.promptPattern, d.searchDepth()),
+		)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		raw, err = d.sess.readUntil(c, promptMatcher(d.def.promptPattern, d.searchDepth()))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	d.updatePrompt(raw)
+
+	// make sure we are sitting at a fresh prompt before doing anything else
+	promptRaw, err := d.getPrompt(c)
+	if err != nil {
+		return nil, err
+	}
+
+	raw = append(raw, promptRaw...)
+
+	err = d.runInstructions(c, d.def.onOpenInstructions)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &cliOutput{}
+	out.add("", raw, d.normalize(raw), "")
+
+	return out, nil
+}
+
+func (d *cliDriver) close(c *control) (*cliOutput, error) {
+	if d.sess == nil {
+		return &cliOutput{}, nil
+	}
+
+	instructionErr := d.runInstructions(c, d.def.onCloseInstructions)
+
+	err := d.sess.close()
+	if instructionErr != nil {
+		return nil, instructionErr
+	}
+
+	if err != nil {
+		return nil, scrapligoerrors.NewFfiError("failed closing transport", err)
+	}
+
+	out := &cliOutput{}
+	out.add("", nil, "", "")
+
+	return out, nil
+}
+
+func (d *cliDriver) runInstructions(c *control, instructions []*instruction) error {
+	for _, i := range instructions {
+		var err error
+
+		switch {
+		case i.EnterMode != nil:
+			_, _, err = d.enterMode(c, i.EnterMode.RequestedMode)
+		case i.SendInput != nil:
+			_, _, err = d.sendInput(c, i.SendInput.Input, inputHandlingFuzzy, false, false)
+		case i.SendPromptedInput != nil:
+			var response string
+
+			response, err = d.resolveLookup(i.SendPromptedInput.Response)
+			if err != nil {
+				break
+			}
+
+			_, _, err = d.sendPromptedInput(
+				c,
+				i.SendPromptedInput.Input,
+				i.SendPromptedInput.PromptExact,
+				i.SendPromptedInput.PromptPattern,
+				response,
+				inputHandlingFuzzy,
+				true,
+				false,
+			)
+		case i.Write != nil:
+			err = d.sess.writeAndReturn(i.Write.Input)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *cliDriver) resolveLookup(value string) (string, error) {
+	var lookup func(key string) (string, error)
+
+	if f := d.options.GetLookupCallback(); f != nil {
+		lookup = func(key string) (string, error) {
+			return f(d.host, key)
+		}
+	}
+
+	return resolveLookup(value, d.options.Auth.LookupMap, lookup, d.options.Auth.Password)
+}
+
+// normalize strips ansi escapes and (per the options) normalizes line feeds and trailing
+// whitespace of the given output.
+func (d *cliDriver) normalize(b []byte) string {
+	out := ansiPattern.ReplaceAllString(string(b), "")
+
+	if d.options.Cli.NormalizeLineFeeds {
+		out = strings.ReplaceAll(out, "\r\n", "\n")
+		out = strings.ReplaceAll(out, "\r", "")
+	}
+
+	if d.options.Cli.NormalizeTrailingWhitespace {
+		lines := strings.Split(out, "\n")
+
+		for idx, line := range lines {
+			lines[idx] = strings.TrimRight(line, " \t")
+		}
+
+		out = strings.Join(lines, "\n")
+	}
+
+	return out
+}
+
+// updatePrompt sets the prompt to the prompt at the end of raw, if there is one.
+func (d *cliDriver) updatePrompt(raw []byte) {
+	idx := promptIndex(d.def.promptPattern, raw, d.searchDepth())
+	if idx < 0 {
+		return
+	}
+
+	d.prompt = strings.Trim(string(raw[idx:]), "\r\n")
+}
+
+func (d *cliDriver) getPrompt(c *control) ([]byte, error) {
+	err := d.sess.writeReturn()
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := d.sess.readUntil(c, promptMatcher(d.def.promptPattern, d.searchDepth()))
+	if err != nil {
+		return nil, err
+	}
+
+	d.updatePrompt(raw)
+
+	return raw, nil
+}
+
+// acquireMode enters the requested mode if one is requested.
+func (d *cliDriver) acquireMode(c *control, requestedMode string) error {
+	if requestedMode == "" {
+		return nil
+	}
+
+	_, _, err := d.enterMode(c, requestedMode)
+
+	return err
+}
+
+func (d *cliDriver) enterMode(c *control, requestedMode string) ([]byte, string, error) {
+	if d.def.getMode(requestedMode) == nil {
+		return nil, "", scrapligoerrors.NewFfiError(
+			fmt.Sprintf("requested mode %q is not a mode in the definition", requestedMode),
+			nil,
+		)
+	}
+
+	// like libscrapli, no need to touch the device if the last prompt we saw is already in the
+	// requested mode
+	if current := d.def.modeForPrompt(d.prompt); current != nil && current.name == requestedMode {
+		return nil, "", nil
+	}
+
+	raw, err := d.getPrompt(c)
+	if err != nil {
+		return nil, "", err
+	}
+
+	current := d.def.modeForPrompt(d.prompt)
+	if current == nil {
+		return nil, "", scrapligoerrors.NewFfiError(
+			fmt.Sprintf("prompt %q does not match any mode", d.prompt),
+			nil,
+		)
+	}
+
+	if current.name == requestedMode {
+		return nil, "", nil
+	}
+
+	path, err := d.def.modePath(current.name, requestedMode)
+	if err != nil {
+		return nil, "", err
+	}
+
+	results := []string{d.prompt}
+
+	for _, am := range path {
+		for _, i := range am.Instructions {
+			var (
+				stepRaw    []byte
+				stepResult string
+			)
+
+			switch {
+			case i.SendInput != nil:
+				stepRaw, stepResult, err = d.sendInput(
+					c,
+					i.SendInput.Input,
+					inputHandlingFuzzy,
+					true,
+					true,
+				)
+			case i.SendPromptedInput != nil:
+				var response string
+
+				response, err = d.resolveLookup(i.SendPromptedInput.Response)
+				if err != nil {
+					break
+				}
+
+				stepRaw, stepResult, err = d.sendPromptedInput(
+					c,
+					i.SendPromptedInput.Input,
+					i.SendPromptedInput.PromptExact,
+					i.SendPromptedInput.PromptPattern,
+					response,
+					inputHandlingFuzzy,
+					true,
+					true,
+				)
+			case i.Write != nil:
+				err = d.sess.writeAndReturn(i.Write.Input)
+			}
+
+			if err != nil {
+				return nil, "", err
+			}
+
+			raw = append(raw, stepRaw...)
+
+			if stepResult != "" {
+				results = append(results, stepResult)
+			}
+		}
+	}
+
+	return raw, strings.Join(results, "\n"), nil
+}
+
+func (d *cliDriver) inputMatcher(input string, handling uint8) func(b []byte) int {
+	if handling == inputHandlingExact {
+		return exactMatcher([]byte(input))
+	}
+
+	return fuzzyMatcher([]byte(input))
+}
+
+// sendInput sends a single input and reads until the prompt.
+func (d *cliDriver) sendInput(
+	c *control,
+	input string,
+	handling uint8,
+	retainInput bool,
+	retainTrailingPrompt bool,
+) ([]byte, string, error) {
+	prevPrompt := d.prompt
+
+	err := d.sess.write(input)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var raw []byte
+
+	if handling != inputHandlingIgnore {
+		raw, err = d.sess.readUntil(c, d.inputMatcher(input, handling))
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	err = d.sess.writeReturn()
+	if err != nil {
+		return nil, "", err
+	}
+
+	rest, err := d.sess.readUntil(c, promptMatcher(d.def.promptPattern, d.searchDepth()))
+	if err != nil {
+		return nil, "", err
+	}
+
+	raw = append(raw, rest...)
+
+	d.updatePrompt(raw)
+
+	return raw, d.processResult(
+		raw,
+		prevPrompt,
+		handling != inputHandlingIgnore,
+		retainInput,
+		retainTrailingPrompt,
+	), nil
+}
+
+// sendPromptedInput sends an input, waits for the given prompt, sends the response and then reads
+// until the (normal) prompt. The input is always retained in the result.
+func (d *cliDriver) sendPromptedInput(
+	c *control,
+	input string,
+	prompt string,
+	promptPattern string,
+	response string,
+	handling uint8,
+	hiddenInput bool,
+	retainTrailingPrompt bool,
+) ([]byte, string, error) {
+	prevPrompt := d.prompt
+
+	promptMatch := exactMatcher([]byte(prompt))
+
+	if promptPattern != "" {
+		pattern, err := compilePattern(promptPattern)
+		if err != nil {
+			return nil, "", err
+		}
+
+		promptMatch = patternMatcher(pattern)
+	}
+
+	err := d.sess.write(input)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var raw []byte
+
+	if handling != inputHandlingIgnore {
+		raw, err = d.sess.readUntil(c, d.inputMatcher(input, handling))
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	err = d.sess.writeReturn()
+	if err != nil {
+		return nil, "", err
+	}
+
+	promptRaw, err := d.sess.readUntil(c, promptMatch)
+	if err != nil {
+		return nil, "", err
+	}
+
+	raw = append(raw, promptRaw...)
+
+	err = d.sess.write(response)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !hiddenInput && handling != inputHandlingIgnore {
+		responseRaw, err := d.sess.readUntil(c, d.inputMatcher(response, handling))
+		if err != nil {
+			return nil, "", err
+		}
+
+		raw = append(raw, responseRaw...)
+	}
+
+	err = d.sess.writeReturn()
+	if err != nil {
+		return nil, "", err
+	}
+
+	rest, err := d.sess.readUntil(c, promptMatcher(d.def.promptPattern, d.searchDepth()))
+	if err != nil {
+		return nil, "", err
+	}
+
+	raw = append(raw, rest...)
+
+	d.updatePrompt(raw)
+
+	return raw, d.processResult(
+		raw,
+		prevPrompt,
+		handling != inputHandlingIgnore,
+		true,
+		retainTrailingPrompt,
+	), nil
+}
+
+// processResult processes the raw output of an input into its result -- the input line is either
+// stripped or retained (along with the prompt the input was sent at), as is the trailing prompt.
+// When input handling is "ignore" we never found the input so it is left as is.
+func (d *cliDriver) processResult(
+	raw []byte,
+	prevPrompt string,
+	foundInput bool,
+	retainInput bool,
+	retainTrailingPrompt bool,
+) string {
+	out := d.normalize(raw)
+
+	if foundInput {
+		if retainInput {
+			out = prevPrompt + out
+		} else {
+			_, after, ok := strings.Cut(out, "\n")
+			if !ok {
+				after = ""
+			}
+
+			out = after
+		}
+	}
+
+	if !retainTrailingPrompt {
+		idx := promptIndex(d.def.promptPattern, []byte(out), d.searchDepth())
+		if idx >= 0 {
+			out = out[:idx]
+		}
+	}
+
+	return strings.Trim(out, "\n")
+}
@@ -0,0 +1,327 @@
+package gobackend
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	lookupPrefix = "__lookup::"
+)
+
+// definition is the go flavor of a libscrapli cli definition -- the same yaml definitions (see the
+// assets package) libscrapli loads, with patterns compiled for go's regexp package.
+type definition struct {
+	promptPattern       *regexp.Regexp
+	defaultMode         string
+	modes               []*mode
+	failureIndicators   []string
+	onOpenInstructions  []*instruction
+	onCloseInstructions []*instruction
+	forceInSessionAuth  bool
+}
+
+type mode struct {
+	name            string
+	promptPattern   *regexp.Regexp
+	promptExcludes  []string
+	accessibleModes []*accessibleMode
+}
+
+type accessibleMode struct {
+	Name         string         `yaml:"name"`
+	Instructions []*instruction `yaml:"instructions"`
+}
+
+// instruction is a single on open/close or mode change instruction, exactly one of the fields is
+// set.
+type instruction struct {
+	EnterMode *struct {
+		RequestedMode string `yaml:"requested_mode"`
+	} `yaml:"enter_mode"`
+	SendInput *struct {
+		Input string `yaml:"input"`
+	} `yaml:"send_input"`
+	SendPromptedInput *struct {
+		Input         string `yaml:"input"`
+		PromptExact   string `yaml:"prompt_exact"`
+		PromptPattern string `yaml:"prompt_pattern"`
+		Response      string `yaml:"response"`
+	} `yaml:"send_prompted_input"`
+	Write *struct {
+		Input string `yaml:"input"`
+	} `yaml:"write"`
+}
+
+type rawDefinition struct {
+	PromptPattern string `yaml:"prompt_pattern"`
+	DefaultMode   string `yaml:"default_mode"`
+	Modes         []struct {
+		Name            string            `yaml:"name"`
+		PromptPattern   string            `yaml:"prompt_pattern"`
+		PromptExcludes  []string          `yaml:"prompt_excludes"`
+		AccessibleModes []*accessibleMode `yaml:"accessible_modes"`
+	} `yaml:"modes"`
+	FailureIndicators   []string       `yaml:"failure_indicators"`
+	OnOpenInstructions  []*instruction `yaml:"on_open_instructions"`
+	OnCloseInstructions []*instruction `yaml:"on_close_instructions"`
+	ForceInSessionAuth  bool           `yaml:"force_in_session_auth"`
+}
+
+func parseDefinition(definitionString string) (*definition, error) {
+	raw := &rawDefinition{}
+
+	err := yaml.Unmarshal([]byte(definitionString), raw)
+	if err != nil {
+		return nil, scrapligoerrors.NewUtilError("failed parsing definition", err)
+	}
+
+	d := &definition{
+		defaultMode:         raw.DefaultMode,
+		modes:               make([]*mode, len(raw.Modes)),
+		failureIndicators:   raw.FailureIndicators,
+		onOpenInstructions:  raw.OnOpenInstructions,
+		onCloseInstructions: raw.OnCloseInstructions,
+		forceInSessionAuth:  raw.ForceInSessionAuth,
+	}
+
+	d.promptPattern, err = compilePattern(raw.PromptPattern)
+	if err != nil {
+		return nil, err
+	}
+
+	for idx, rawMode := range raw.Modes {
+		m := &mode{
+			name:            rawMode.Name,
+			promptExcludes:  rawMode.PromptExcludes,
+			accessibleModes: rawMode.AccessibleModes,
+		}
+
+		m.promptPattern, err = compilePattern(rawMode.PromptPattern)
+		if err != nil {
+			return nil, err
+		}
+
+		d.modes[idx] = m
+	}
+
+	if d.defaultMode != "" && d.getMode(d.defaultMode) == nil {
+		return nil, scrapligoerrors.NewUtilError(
+			fmt.Sprintf("default mode %q is not a mode in the definition", d.defaultMode),
+			nil,
+		)
+	}
+
+	return d, nil
+}
+
+func (d *definition) getMode(name string) *mode {
+	for _, m := range d.modes {
+		if m.name == name {
+			return m
+		}
+	}
+
+	return nil
+}
+
+// modeForPrompt returns the mode the given prompt belongs to, or nil if the prompt matches no
+// mode.
+func (d *definition) modeForPrompt(prompt string) *mode {
+	for _, m := range d.modes {
+		if !m.promptPattern.MatchString(prompt) {
+			continue
+		}
+
+		excluded := false
+
+		for _, exclude := range m.promptExcludes {
+			if strings.Contains(prompt, exclude) {
+				excluded = true
+
+				break
+			}
+		}
+
+		if !excluded {
+			return m
+		}
+	}
+
+	return nil
+}
+
+// modePath returns the shortest path of mode changes (as the accessible mode entries to follow)
+// to get from the current to the requested mode.
+func (d *definition) modePath(current, requested string) ([]*accessibleMode, error) {
+	type step struct {
+		name string
+		path []*accessibleMode
+	}
+
+	visited := map[string]struct{}{current: {}}
+	queue := []step{{name: current}}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		if cur.name == requested {
+			return cur.path, nil
+		}
+
+		m := d.getMode(cur.name)
+		if m == nil {
+			continue
+		}
+
+		for _, am := range m.accessibleModes {
+			if _, ok := visited[am.Name]; ok {
+				continue
+			}
+
+			visited[am.Name] = struct{}{}
+
+			path := make([]*accessibleMode, len(cur.path), len(cur.path)+1)
+			copy(path, cur.path)
+
+			queue = append(queue, step{name: am.Name, path: append(path, am)})
+		}
+	}
+
+	return nil, scrapligoerrors.NewUtilError(
+		fmt.Sprintf("no path from mode %q to mode %q", current, requested),
+		nil,
+	)
+}
+
+// failureIndicator returns the first failure indicator found in the given output, if any.
+func (d *definition) failureIndicator(output string) string {
+	for _, indicator := range d.failureIndicators {
+		if strings.Contains(output, indicator) {
+			return indicator
+		}
+	}
+
+	return ""
+}
+
+// compilePattern compiles a (pcre2 flavored, as libscrapli uses) pattern in multiline mode. The
+// definitions use a few pcre-isms that go's regexp does not support, these are rewritten to their
+// closest (for our purposes, identical) equivalent, see toRE2.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile("(?m)" + toRE2(pattern))
+	if err != nil {
+		return nil, scrapligoerrors.NewUtilError(
+			fmt.Sprintf("failed compiling pattern %q", pattern),
+			err,
+		)
+	}
+
+	return re, nil
+}
+
+// toRE2 rewrites possessive quantifiers ("?+", "*+", "++", "{n,m}+") to their greedy flavor and
+// "{,n}" to "{0,n}". Greedy vs possessive only differs in backtracking behavior, which re2 does not
+// do in the first place.
+func toRE2(pattern string) string {
+	var b strings.Builder
+
+	b.Grow(len(pattern))
+
+	inClass := false
+	afterQuantifier := false
+
+	for idx := 0; idx < len(pattern); idx++ {
+		c := pattern[idx]
+
+		switch {
+		case c == '\\' && idx+1 < len(pattern):
+			b.WriteByte(c)
+			b.WriteByte(pattern[idx+1])
+
+			idx++
+
+			afterQuantifier = false
+
+			continue
+		case inClass:
+			if c == ']' {
+				inClass = false
+			}
+		case c == '[':
+			inClass = true
+
+			b.WriteByte(c)
+
+			// a leading "]" (optionally after a "^") is a literal, not the end of the class
+			if idx+1 < len(pattern) && pattern[idx+1] == '^' {
+				b.WriteByte('^')
+
+				idx++
+			}
+
+			if idx+1 < len(pattern) && pattern[idx+1] == ']' {
+				b.WriteByte(']')
+
+				idx++
+			}
+
+			afterQuantifier = false
+
+			continue
+		case c == '+' && afterQuantifier:
+			afterQuantifier = false
+
+			continue
+		case c == '{' && idx+1 < len(pattern) && pattern[idx+1] == ',':
+			b.WriteString("{0")
+
+			afterQuantifier = false
+
+			continue
+		}
+
+		b.WriteByte(c)
+
+		afterQuantifier = !inClass && (c == '?' || c == '*' || c == '+' || c == '}')
+	}
+
+	return b.String()
+}
+
+// resolveLookup resolves "__lookup::key" style values from the lookup map, then the lookup
+// callback (if any), if neither has a value for the key the password is used, as libscrapli does.
+func resolveLookup(
+	value string,
+	lookups map[string]string,
+	lookup func(key string) (string, error),
+	password string,
+) (string, error) {
+	key, ok := strings.CutPrefix(value, lookupPrefix)
+	if !ok {
+		return value, nil
+	}
+
+	resolved, ok := lookups[key]
+	if ok {
+		return resolved, nil
+	}
+
+	if lookup != nil {
+		resolved, err := lookup(key)
+		if err != nil {
+			return "", err
+		}
+
+		if resolved != "" {
+			return resolved, nil
+		}
+	}
+
+	return password, nil
+}
@@ -0,0 +1,813 @@
+package gobackend
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
+	scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
+)
+
+const (
+	netconfBase10Delimiter = "]]>]]>"
+	netconfBase11Cap       = "urn:ietf:params:netconf:base:1.1"
+	netconfBase10Cap       = "urn:ietf:params:netconf:base:1.0"
+	netconfVersion10       = "1.0"
+	netconfVersion11       = "1.1"
+	netconfFirstMessageID  = 101
+	xmlHeader              = `<?xml version="1.0" encoding="UTF-8"?>`
+)
+
+// nolint: gochecknoglobals
+var (
+	errIncompleteMessage = scrapligoerrors.NewFfiError("incomplete netconf message", nil)
+	errInvalidChunk      = scrapligoerrors.NewFfiError("invalid netconf chunk", nil)
+
+	netconfMessageIDPattern = regexp.MustCompile(`<(?:\w+:)?rpc-reply[^>]*\smessage-id="([^"]+)"`)
+	netconfSessionIDPattern = regexp.MustCompile(
+		`<(?:\w+:)?session-id>\s*(\d+)\s*</(?:\w+:)?session-id>`,
+	)
+	netconfSubscriptionIDPattern = regexp.MustCompile(
+		`<(?:\w+:)?(?:subscription-id|id)(?:\s[^>]*)?>\s*(\d+)\s*</`,
+	)
+	netconfNotificationSubscriptionPattern = regexp.MustCompile(
+		`(?s)<(?:\w+:)?(?:push-update|push-change-update|subscription-(?:started|modified|` +
+			`terminated|suspended|resumed|completed))\b[^>]*>.*?` +
+			`<(?:\w+:)?(?:subscription-)?id(?:\s[^>]*)?>\s*(\d+)\s*</`,
+	)
+	netconfRPCErrorPattern = regexp.MustCompile(
+		`(?s)<(?:\w+:)?rpc-error>.*?</(?:\w+:)?rpc-error>`,
+	)
+	netconfWarningSeverityPattern = regexp.MustCompile(
+		`<(?:\w+:)?error-severity>\s*warning\s*</`,
+	)
+)
+
+// netconfReply is an rpc reply as read off the wire (raw) and with any framing removed (message).
+type netconfReply struct {
+	raw     []byte
+	message []byte
+}
+
+// netconfDriver is the go backend flavor of the libscrapli netconf driver. Rpcs are pipelined --
+// each is written as soon as it is submitted and a single reader routes replies back to the
+// waiting operation by message-id.
+type netconfDriver struct {
+	*driver
+
+	base11    bool
+	sessionID uint64
+
+	writeLock     sync.Mutex
+	nextMessageID uint64
+
+	messagesLock  sync.Mutex
+	pending       map[string]chan *netconfReply
+	unclaimed     map[string]*netconfReply
+	notifications [][]byte
+	subscriptions map[uint64][][]byte
+	readerErr     error
+	reading       atomic.Bool
+	readerDone    chan struct{}
+}
+
+func (b *backend) getNetconf(driverPtr uintptr) *netconfDriver {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	d, _ := b.drivers[driverPtr].(*netconfDriver)
+
+	return d
+}
+
+func (b *backend) netconfAlloc(host string, _ uintptr) uintptr {
+	d, err := newDriver(host, b.options)
+	if err != nil {
+		b.options.GetLogger().Critical(err.Error())
+
+		return 0
+	}
+
+	return b.register(&netconfDriver{
+		driver:        d,
+		nextMessageID: netconfFirstMessageID,
+		pending:       map[string]chan *netconfReply{},
+		unclaimed:     map[string]*netconfReply{},
+		subscriptions: map[uint64][][]byte{},
+		readerDone:    make(chan struct{}),
+	})
+}
+
+func (d *netconfDriver) free() {
+	d.driver.free()
+
+	if d.reading.Load() {
+		<-d.readerDone
+	}
+}
+
+func (b *backend) netconfOpen(driverPtr uintptr, operationID *uint32, cancel *bool) uint8 {
+	d := b.getNetconf(driverPtr)
+	if d == nil {
+		return scrapligoffi.ReturnCodeInvalidArgument
+	}
+
+	d.submit(operationID, func() *operationResult {
+		startTime := nowNs()
+
+		r, err := d.open(d.newControl(cancel))
+		if err != nil {
+			return errResult(startTime, err)
+		}
+
+		r.startTime = startTime
+
+		return r
+	})
+
+	return scrapligoffi.ReturnCodeSuccess
+}
+
+func (b *backend) netconfClose(
+	driverPtr uintptr,
+	operationID *uint32,
+	cancel *bool,
+	force bool,
+) uint8 {
+	d := b.getNetconf(driverPtr)
+	if d == nil {
+		return scrapligoffi.ReturnCodeInvalidArgument
+	}
+
+	if force || d.sess == nil {
+		d.submit(operationID, func() *operationResult {
+			startTime := nowNs()
+
+			if d.sess != nil {
+				_ = d.sess.close()
+			}
+
+			return &operationResult{startTime: startTime, endTime: nowNs()}
+		})
+
+		return scrapligoffi.ReturnCodeSuccess
+	}
+
+	return b.netconfRPC(
+		driverPtr,
+		operationID,
+		cancel,
+		"",
+		"",
+		"<close-session/>",
+		func(d *netconfDriver) {
+			_ = d.sess.close()
+		},
+	)
+}
+
+// done matches the start of the server hello, leaving it unconsumed.
+func helloStartMatcher(b []byte) int {
+	idx := bytes.Index(b, []byte("<hello"))
+
+	xmlIdx := bytes.Index(b, []byte("<?xml"))
+	if xmlIdx >= 0 && (idx < 0 || xmlIdx < idx) {
+		idx = xmlIdx
+	}
+
+	return idx
+}
+
+func (d *netconfDriver) open(c *control) (*operationResult, error) {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	t, err := openTransport(ctx, d.host, d.options, netconfSubsystem)
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.openSession(t)
+	if err != nil {
+		_ = t.Close()
+
+		return nil, err
+	}
+
+	if d.options.TransportKind == scrapligointernal.TransportKindTest &&
+		!d.options.Auth.BypassInSessionAuth {
+		username, password, passphrase, err := inSessionAuthPatterns(
+			d.options.Auth.UsernamePattern,
+			d.options.Auth.PasswordPattern,
+			d.options.Auth.PassphrasePattern,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = d.sess.inSessionAuth(
+			c,
+			authCredentials{
+				username:   d.options.Auth.Username,
+				password:   d.options.Auth.Password,
+				passphrase: d.options.Auth.PrivateKeyPassphrase,
+			},
+			[3]*regexp.Regexp{username, password, passphrase},
+			helloStartMatcher,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	serverHelloRaw, err := d.sess.readUntil(c, exactMatcher([]byte(netconfBase10Delimiter)))
+	if err != nil {
+		return nil, err
+	}
+
+	serverHello := bytes.TrimSpace(bytes.TrimSuffix(serverHelloRaw, []byte(netconfBase10Delimiter)))
+
+	serverCapabilities := string(serverHello)
+
+	switch d.options.Netconf.PreferredVersion {
+	case netconfVersion10:
+		d.base11 = false
+	case netconfVersion11:
+		d.base11 = true
+	default:
+		d.base11 = strings.Contains(serverCapabilities, netconfBase11Cap)
+	}
+
+	if m := netconfSessionIDPattern.FindSubmatch(serverHello); m != nil {
+		d.sessionID, _ = strconv.ParseUint(string(m[1]), 10, 64)
+	}
+
+	clientHello := d.clientHello(string(serverHello))
+
+	err = d.sess.write(clientHello + netconfBase10Delimiter)
+	if err != nil {
+		return nil, err
+	}
+
+	d.reading.Store(true)
+
+	go d.readMessages()
+
+	return &operationResult{
+		endTime:   nowNs(),
+		input:     []byte(clientHello),
+		resultRaw: serverHelloRaw,
+		result:    serverHello,
+	}, nil
+}
+
+func (d *netconfDriver) clientHello(serverHello string) string {
+	var capabilities string
+
+	switch {
+	case d.options.Netconf.CapabilitiesCallback != nil:
+		capabilities = d.options.Netconf.CapabilitiesCallback(serverHello)
+	case d.base11:
+		capabilities = fmt.Sprintf("<capability>%s</capability>", netconfBase11Cap)
+	default:
+		capabilities = fmt.Sprintf("<capability>%s</capability>", netconfBase10Cap)
+	}
+
+	return fmt.Sprintf(
+		`%s<hello xmlns="%s"><capabilities>%s</capabilities></hello>`,
+		xmlHeader,
+		netconfNamespace,
+		capabilities,
+	)
+}
+
+// frame frames the message per the negotiated version.
+func (d *netconfDriver) frame(message string) string {
+	if d.base11 {
+		return fmt.Sprintf("\n#%d\n%s\n##\n", len(message), message)
+	}
+
+	return message + netconfBase10Delimiter
+}
+
+// newChunkedMatcher returns a matcher that matches a complete base 1.1 (chunked framing) message,
+// chunks are only scanned once so the matcher is only good for one read. Invalid framing "matches"
+// everything so that decodeChunked can report the error.
+func newChunkedMatcher() func(b []byte) int {
+	pos := 0
+
+	return func(b []byte) int {
+		for {
+			_, next, last, err := scanChunk(b, pos)
+
+			switch {
+			case errors.Is(err, errIncompleteMessage):
+				return -1
+			case err != nil:
+				return len(b)
+			case last:
+				return next
+			}
+
+			pos = next
+		}
+	}
+}
+
+// scanChunk scans the chunk (or end of chunks marker) starting at pos, returning the start of the
+// chunk data and the position following the chunk, last is true for the end of chunks marker.
+func scanChunk(b []byte, pos int) (int, int, bool, error) {
+	// chunks start with "\n#" -- skip any whitespace leading up to that
+	for pos+1 < len(b) && (b[pos] == '\r' || b[pos] == ' ' || b[pos] == '\t' ||
+		(b[pos] == '\n' && b[pos+1] != '#')) {
+		pos++
+	}
+
+	if len(b)-pos < 4 { //nolint: mnd
+		return 0, 0, false, errIncompleteMessage
+	}
+
+	if b[pos] != '\n' || b[pos+1] != '#' {
+		return 0, 0, false, errInvalidChunk
+	}
+
+	pos += 2
+
+	if b[pos] == '#' {
+		if b[pos+1] != '\n' {
+			return 0, 0, false, errInvalidChunk
+		}
+
+		return pos + 2, pos + 2, true, nil
+	}
+
+	sizeEnd := bytes.IndexByte(b[pos:], '\n')
+	if sizeEnd < 0 {
+		return 0, 0, false, errIncompleteMessage
+	}
+
+	size, err := strconv.Atoi(string(b[pos : pos+sizeEnd]))
+	if err != nil {
+		return 0, 0, false, errInvalidChunk
+	}
+
+	dataStart := pos + sizeEnd + 1
+
+	if len(b)-dataStart < size {
+		return 0, 0, false, errIncompleteMessage
+	}
+
+	return dataStart, dataStart + size, false, nil
+}
+
+// decodeChunked decodes a (complete) chunked framing message.
+func decodeChunked(b []byte) ([]byte, error) {
+	var out []byte
+
+	pos := 0
+
+	for {
+		dataStart, next, last, err := scanChunk(b, pos)
+		if err != nil {
+			return nil, err
+		}
+
+		if last {
+			return out, nil
+		}
+
+		out = append(out, b[dataStart:next]...)
+
+		pos = next
+	}
+}
+
+// readMessages reads messages until the session fails (is closed), routing rpc replies to their
+// operation and queueing notifications and subscription messages.
+func (d *netconfDriver) readMessages() {
+	defer close(d.readerDone)
+
+	// the reader lives for the life of the session, it is never cancelled and has no deadline
+	c := &control{}
+
+	for {
+		var (
+			raw     []byte
+			message []byte
+			err     error
+		)
+
+		if d.base11 {
+			raw, err = d.sess.readUntil(c, newChunkedMatcher())
+			if err == nil {
+				message, err = decodeChunked(raw)
+			}
+		} else {
+			raw, err = d.sess.readUntil(c, exactMatcher([]byte(netconfBase10Delimiter)))
+			message = bytes.TrimSuffix(raw, []byte(netconfBase10Delimiter))
+		}
+
+		if err != nil {
+			d.failPending(err)
+
+			return
+		}
+
+		d.routeMessage(raw, bytes.TrimLeft(message, " \r\n"))
+	}
+}
+
+func (d *netconfDriver) routeMessage(raw, message []byte) {
+	d.messagesLock.Lock()
+	defer d.messagesLock.Unlock()
+
+	if m := netconfMessageIDPattern.FindSubmatch(message); m != nil {
+		ch, ok := d.pending[string(m[1])]
+		if ok {
+			delete(d.pending, string(m[1]))
+
+			ch <- &netconfReply{raw: raw, message: message}
+
+			return
+		}
+
+		// a reply for an rpc we have not (yet) registered, this only really happens with the test
+		// transport where all "replies" are available up front, hang on to it until the rpc is sent
+		d.unclaimed[string(m[1])] = &netconfReply{raw: raw, message: message}
+
+		return
+	}
+
+	if m := netconfNotificationSubscriptionPattern.FindSubmatch(message); m != nil {
+		subscriptionID, err := strconv.ParseUint(string(m[1]), 10, 64)
+		if err == nil {
+			d.subscriptions[subscriptionID] = append(d.subscriptions[subscriptionID], message)
+
+			return
+		}
+	}
+
+	d.notifications = append(d.notifications, message)
+}
+
+func (d *netconfDriver) failPending(err error) {
+	d.messagesLock.Lock()
+	defer d.messagesLock.Unlock()
+
+	d.readerErr = err
+
+	for id, ch := range d.pending {
+		close(ch)
+
+		delete(d.pending, id)
+	}
+}
+
+// netconfRPC submits the rpc with the given body, after, if provided, is called once the reply has
+// been received.
+func (b *backend) netconfRPC(
+	driverPtr uintptr,
+	operationID *uint32,
+	cancel *bool,
+	baseNamespacePrefix,
+	extraNamespaces,
+	body string,
+	after func(d *netconfDriver),
+) uint8 {
+	if b.render != nil {
+		b.render(&RenderedRPC{
+			BaseNamespacePrefix: baseNamespacePrefix,
+			ExtraNamespaces:     extraNamespaces,
+			Body:                body,
+		})
+
+		return scrapligoffi.ReturnCodeSuccess
+	}
+
+	d := b.getNetconf(driverPtr)
+	if d == nil || d.sess == nil {
+		return scrapligoffi.ReturnCodeInvalidArgument
+	}
+
+	startTime := nowNs()
+
+	// the rpc is written before returning so rpcs hit the wire in submission order
+	d.writeLock.Lock()
+
+	messageID := d.nextMessageID
+	d.nextMessageID++
+
+	rpc := wrapRPC(messageID, baseNamespacePrefix, extraNamespaces, body)
+
+	replyCh := make(chan *netconfReply, 1)
+
+	d.messagesLock.Lock()
+
+	var readerErr error
+
+	if reply, ok := d.unclaimed[strconv.FormatUint(messageID, 10)]; ok {
+		delete(d.unclaimed, strconv.FormatUint(messageID, 10))
+
+		replyCh <- reply
+	} else {
+		readerErr = d.readerErr
+		if readerErr == nil {
+			d.pending[strconv.FormatUint(messageID, 10)] = replyCh
+		}
+	}
+
+	d.messagesLock.Unlock()
+
+	var writeErr error
+
+	if readerErr == nil {
+		writeErr = d.sess.write(d.frame(rpc))
+	}
+
+	d.writeLock.Unlock()
+
+	d.submit(operationID, func() *operationResult {
+		switch {
+		case readerErr != nil:
+			return errResult(startTime, readerErr)
+		case writeErr != nil:
+			return errResult(startTime, writeErr)
+		}
+
+		reply, err := d.waitReply(d.newControl(cancel), messageID, replyCh)
+		if err != nil {
+			return errResult(startTime, err)
+		}
+
+		if after != nil {
+			after(d)
+		}
+
+		return d.processReply(startTime, rpc, reply)
+	})
+
+	return scrapligoffi.ReturnCodeSuccess
+}
+
+func (d *netconfDriver) waitReply(
+	c *control,
+	messageID uint64,
+	replyCh chan *netconfReply,
+) (*netconfReply, error) {
+	ticker := time.NewTicker(controlCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case reply, ok := <-replyCh:
+			if !ok {
+				d.messagesLock.Lock()
+				err := d.readerErr
+				d.messagesLock.Unlock()
+
+				return nil, scrapligoerrors.NewFfiError("session closed awaiting reply", err)
+			}
+
+			return reply, nil
+		case <-ticker.C:
+			err := c.check()
+			if err != nil {
+				d.messagesLock.Lock()
+				delete(d.pending, strconv.FormatUint(messageID, 10))
+				d.messagesLock.Unlock()
+
+				return nil, err
+			}
+		}
+	}
+}
+
+// processReply builds the operation result for an rpc reply.
+func (d *netconfDriver) processReply(
+	startTime uint64,
+	rpc string,
+	reply *netconfReply,
+) *operationResult {
+	message := reply.message
+
+	var warnings, errs [][]byte
+
+	for _, rpcErr := range netconfRPCErrorPattern.FindAll(message, -1) {
+		if netconfWarningSeverityPattern.Match(rpcErr) {
+			warnings = append(warnings, rpcErr)
+
+			continue
+		}
+
+		errs = append(errs, rpcErr)
+	}
+
+	if len(errs) == 0 && d.options.Netconf.ErrorTag != "" &&
+		bytes.Contains(message, []byte(d.options.Netconf.ErrorTag)) {
+		errs = append(errs, message)
+	}
+
+	return &operationResult{
+		startTime:   startTime,
+		endTime:     nowNs(),
+		input:       []byte(rpc),
+		resultRaw:   reply.raw,
+		result:      message,
+		rpcWarnings: bytes.Join(warnings, []byte("\n")),
+		rpcErrors:   bytes.Join(errs, []byte("\n")),
+	}
+}
+
+func (b *backend) netconfFetchOperationSizes(
+	driverPtr uintptr,
+	operationID uint32,
+	inputSize,
+	resultRawSize,
+	resultSize,
+	rpcWarningsSize,
+	rpcErrorsSize,
+	errSize,
+	lastErrStrSize *uintptr,
+) uint8 {
+	d := b.getNetconf(driverPtr)
+	if d == nil {
+		return scrapligoffi.ReturnCodeInvalidArgument
+	}
+
+	r := d.getOperation(operationID, false)
+	if r == nil {
+		return scrapligoffi.ReturnCodeOperation
+	}
+
+	*inputSize = uintptr(len(r.input))
+	*resultRawSize = uintptr(len(r.resultRaw))
+	*resultSize = uintptr(len(r.result))
+	*rpcWarningsSize = uintptr(len(r.rpcWarnings))
+	*rpcErrorsSize = uintptr(len(r.rpcErrors))
+	*errSize = uintptr(len(r.err))
+	*lastErrStrSize = 0
+
+	return scrapligoffi.ReturnCodeSuccess
+}
+
+func (b *backend) netconfFetchOperation(
+	driverPtr uintptr,
+	operationID uint32,
+	resultStartTime *uint64,
+	resultEndTime *uint64,
+	input,
+	resultRaw,
+	result,
+	rpcWarnings,
+	rpcErrors,
+	err,
+	_ *[]byte,
+) uint8 {
+	d := b.getNetconf(driverPtr)
+	if d == nil {
+		return scrapligoffi.ReturnCodeInvalidArgument
+	}
+
+	r := d.getOperation(operationID, true)
+	if r == nil {
+		return scrapligoffi.ReturnCodeOperation
+	}
+
+	*resultStartTime = r.startTime
+	*resultEndTime = r.endTime
+
+	copy(*input, r.input)
+	copy(*resultRaw, r.resultRaw)
+	copy(*result, r.result)
+	copy(*rpcWarnings, r.rpcWarnings)
+	copy(*rpcErrors, r.rpcErrors)
+	copy(*err, r.err)
+
+	return scrapligoffi.ReturnCodeSuccess
+}
+
+func (b *backend) netconfGetSessionID(driverPtr uintptr, sessionID *uint64) uint8 {
+	d := b.getNetconf(driverPtr)
+	if d == nil {
+		return scrapligoffi.ReturnCodeInvalidArgument
+	}
+
+	if d.sessionID == 0 {
+		return scrapligoffi.ReturnCodeOperation
+	}
+
+	*sessionID = d.sessionID
+
+	return scrapligoffi.ReturnCodeSuccess
+}
+
+func getSubscriptionID(message string, subscriptionID *uint64) uint8 {
+	*subscriptionID = 0
+
+	m := netconfSubscriptionIDPattern.FindStringSubmatch(message)
+	if m == nil {
+		return scrapligoffi.ReturnCodeSuccess
+	}
+
+	id, err := strconv.ParseUint(m[1], 10, 64)
+	if err != nil {
+		return scrapligoffi.ReturnCodeSuccess
+	}
+
+	*subscriptionID = id
+
+	return scrapligoffi.ReturnCodeSuccess
+}
+
+func (b *backend) netconfNextNotificationSize(driverPtr uintptr, size *uint64) uint8 {
+	d := b.getNetconf(driverPtr)
+	if d == nil {
+		return scrapligoffi.ReturnCodeInvalidArgument
+	}
+
+	d.messagesLock.Lock()
+	defer d.messagesLock.Unlock()
+
+	*size = 0
+
+	if len(d.notifications) > 0 {
+		*size = uint64(len(d.notifications[0]))
+	}
+
+	return scrapligoffi.ReturnCodeSuccess
+}
+
+func (b *backend) netconfNextNotification(driverPtr uintptr, notification *[]byte) uint8 {
+	d := b.getNetconf(driverPtr)
+	if d == nil {
+		return scrapligoffi.ReturnCodeInvalidArgument
+	}
+
+	d.messagesLock.Lock()
+	defer d.messagesLock.Unlock()
+
+	if len(d.notifications) == 0 {
+		return scrapligoffi.ReturnCodeOperation
+	}
+
+	copy(*notification, d.notifications[0])
+
+	d.notifications = d.notifications[1:]
+
+	return scrapligoffi.ReturnCodeSuccess
+}
+
+func (b *backend) netconfNextSubscriptionSize(
+	driverPtr uintptr,
+	subscriptionID uint64,
+	size *uint64,
+) uint8 {
+	d := b.getNetconf(driverPtr)
+	if d == nil {
+		return scrapligoffi.ReturnCodeInvalidArgument
+	}
+
+	d.messagesLock.Lock()
+	defer d.messagesLock.Unlock()
+
+	*size = 0
+
+	if messages := d.subscriptions[subscriptionID]; len(messages) > 0 {
+		*size = uint64(len(messages[0]))
+	}
+
+	return scrapligoffi.ReturnCodeSuccess
+}
+
+func (b *backend) netconfNextSubscription(
+	driverPtr uintptr,
+	subscriptionID uint64,
+	subscription *[]byte,
+) uint8 {
+	d := b.getNetconf(driverPtr)
+	if d == nil {
+		return scrapligoffi.ReturnCodeInvalidArgument
+	}
+
+	d.messagesLock.Lock()
+	defer d.messagesLock.Unlock()
+
+	messages := d.subscriptions[subscriptionID]
+	if len(messages) == 0 {
+		return scrapligoffi.ReturnCodeOperation
+	}
+
+	copy(*subscription, messages[0])
+
+	d.subscriptions[subscriptionID] = messages[1:]
+
+	return scrapligoffi.ReturnCodeSuccess
+}
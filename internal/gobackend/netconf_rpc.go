@@ -0,0 +1,630 @@
+package gobackend
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/scrapli/scrapligo/v2/constants"
+)
+
+const (
+	netconfNamespace             = "urn:ietf:params:xml:ns:netconf:base:1.0"
+	netconfNmdaNamespace         = "urn:ietf:params:xml:ns:yang:ietf-netconf-nmda"
+	netconfDatastoresNamespace   = "urn:ietf:params:xml:ns:yang:ietf-datastores"
+	netconfWithDefaultsNamespace = "urn:ietf:params:xml:ns:yang:ietf-netconf-with-defaults"
+	netconfMonitoringNamespace   = "urn:ietf:params:xml:ns:yang:ietf-netconf-monitoring"
+	netconfOriginNamespace       = "urn:ietf:params:xml:ns:yang:ietf-origin"
+	netconfActionNamespace       = "urn:ietf:params:xml:ns:yang:1"
+	namespacePairDelimiter       = "::"
+)
+
+// the enum value names, in the order of the enums in the netconf package.
+//
+// nolint: gochecknoglobals
+var (
+	datastoreNames = []string{
+		"conventional",
+		"running",
+		"candidate",
+		"startup",
+		"intended",
+		"dynamic",
+		"operational",
+	}
+	filterTypeNames       = []string{"subtree", "xpath"}
+	defaultsTypeNames     = []string{"report-all", "report-all-tagged", "trim", "explicit"}
+	schemaFormatNames     = []string{"xsd", "yang", "yin", "rng", "rnc"}
+	defaultOperationNames = []string{"merge", "replace", "none"}
+	testOptionNames       = []string{"test-then-set", "set"}
+	errorOptionNames      = []string{"stop-on-error", "continue-on-error", "rollback-on-error"}
+)
+
+// enumName returns the name of the (optional) enum value v, or fallback if v is nil or unknown.
+func enumName(names []string, v *uint8, fallback string) string {
+	if v == nil || int(*v) >= len(names) {
+		return fallback
+	}
+
+	return names[*v]
+}
+
+// wrapRPC wraps the body in an rpc element with the given message-id.
+func wrapRPC(messageID uint64, baseNamespacePrefix, extraNamespaces, body string) string {
+	rpcTag := "rpc"
+	namespaces := fmt.Sprintf(` xmlns="%s"`, netconfNamespace)
+
+	if baseNamespacePrefix != "" {
+		rpcTag = baseNamespacePrefix + ":rpc"
+		namespaces = fmt.Sprintf(` xmlns:%s="%s"`, baseNamespacePrefix, netconfNamespace)
+	}
+
+	if extraNamespaces != "" {
+		for _, pair := range strings.Split(extraNamespaces, constants.LibScrapliDelimiter) {
+			prefix, uri, ok := strings.Cut(pair, namespacePairDelimiter)
+			if !ok {
+				continue
+			}
+
+			namespaces += fmt.Sprintf(` xmlns:%s="%s"`, prefix, uri)
+		}
+	}
+
+	return fmt.Sprintf(
+		`%s<%s%s message-id="%d">%s</%s>`,
+		xmlHeader,
+		rpcTag,
+		namespaces,
+		messageID,
+		body,
+		rpcTag,
+	)
+}
+
+func datastoreElement(tag string, datastore *uint8, fallback string) string {
+	return fmt.Sprintf("<%s><%s/></%s>", tag, enumName(datastoreNames, datastore, fallback), tag)
+}
+
+func filterNamespaceAttr(filterNamespacePrefix, filterNamespace string) string {
+	switch {
+	case filterNamespace == "":
+		return ""
+	case filterNamespacePrefix == "":
+		return fmt.Sprintf(` xmlns="%s"`, filterNamespace)
+	default:
+		return fmt.Sprintf(` xmlns:%s="%s"`, filterNamespacePrefix, filterNamespace)
+	}
+}
+
+func filterElement(
+	filter string,
+	filterType *uint8,
+	filterNamespacePrefix,
+	filterNamespace string,
+) string {
+	if filter == "" {
+		return ""
+	}
+
+	namespaceAttr := filterNamespaceAttr(filterNamespacePrefix, filterNamespace)
+
+	if enumName(filterTypeNames, filterType, "subtree") == "xpath" {
+		return fmt.Sprintf(
+			`<filter type="xpath"%s select="%s"/>`,
+			namespaceAttr,
+			html.EscapeString(filter),
+		)
+	}
+
+	return fmt.Sprintf(`<filter type="subtree"%s>%s</filter>`, namespaceAttr, filter)
+}
+
+func defaultsElement(defaultsType *uint8) string {
+	if defaultsType == nil {
+		return ""
+	}
+
+	return fmt.Sprintf(
+		`<with-defaults xmlns="%s">%s</with-defaults>`,
+		netconfWithDefaultsNamespace,
+		enumName(defaultsTypeNames, defaultsType, "report-all"),
+	)
+}
+
+func getConfigBody(
+	source *uint8,
+	filter string,
+	filterType *uint8,
+	filterNamespacePrefix,
+	filterNamespace string,
+	defaultsType *uint8,
+) string {
+	return fmt.Sprintf(
+		"<get-config>%s%s%s</get-config>",
+		datastoreElement("source", source, "running"),
+		filterElement(filter, filterType, filterNamespacePrefix, filterNamespace),
+		defaultsElement(defaultsType),
+	)
+}
+
+func getBody(
+	filter string,
+	filterType *uint8,
+	filterNamespacePrefix,
+	filterNamespace string,
+	defaultsType *uint8,
+) string {
+	return fmt.Sprintf(
+		"<get>%s%s</get>",
+		filterElement(filter, filterType, filterNamespacePrefix, filterNamespace),
+		defaultsElement(defaultsType),
+	)
+}
+
+func editConfigBody(
+	config string,
+	target,
+	defaultOperation,
+	testOption,
+	errorOption *uint8,
+) string {
+	var options string
+
+	if defaultOperation != nil {
+		options += fmt.Sprintf(
+			"<default-operation>%s</default-operation>",
+			enumName(defaultOperationNames, defaultOperation, "merge"),
+		)
+	}
+
+	if testOption != nil {
+		options += fmt.Sprintf(
+			"<test-option>%s</test-option>",
+			enumName(testOptionNames, testOption, "test-then-set"),
+		)
+	}
+
+	if errorOption != nil {
+		options += fmt.Sprintf(
+			"<error-option>%s</error-option>",
+			enumName(errorOptionNames, errorOption, "stop-on-error"),
+		)
+	}
+
+	trimmedConfig := strings.TrimSpace(config)
+	if !strings.HasPrefix(trimmedConfig, "<config") {
+		trimmedConfig = fmt.Sprintf("<config>%s</config>", trimmedConfig)
+	}
+
+	return fmt.Sprintf(
+		"<edit-config>%s%s%s</edit-config>",
+		datastoreElement("target", target, "running"),
+		options,
+		trimmedConfig,
+	)
+}
+
+func getSchemaBody(identifier, version string, format *uint8) string {
+	var versionElement string
+
+	if version != "" {
+		versionElement = fmt.Sprintf("<version>%s</version>", version)
+	}
+
+	return fmt.Sprintf(
+		`<get-schema xmlns="%s"><identifier>%s</identifier>%s<format>%s</format></get-schema>`,
+		netconfMonitoringNamespace,
+		identifier,
+		versionElement,
+		enumName(schemaFormatNames, format, "yang"),
+	)
+}
+
+func nmdaDatastoreElement(datastore *uint8, fallback string) string {
+	return fmt.Sprintf(
+		`<datastore xmlns:ds="%s">ds:%s</datastore>`,
+		netconfDatastoresNamespace,
+		enumName(datastoreNames, datastore, fallback),
+	)
+}
+
+func getDataBody(
+	datastore *uint8,
+	filter string,
+	filterType *uint8,
+	filterNamespacePrefix,
+	filterNamespace string,
+	configFilter *bool,
+	originFilters string,
+	maxDepth uint32,
+	withOrigin bool,
+	defaultsType *uint8,
+) string {
+	var b strings.Builder
+
+	b.WriteString(nmdaDatastoreElement(datastore, "running"))
+
+	if filter != "" {
+		namespaceAttr := filterNamespaceAttr(filterNamespacePrefix, filterNamespace)
+
+		if enumName(filterTypeNames, filterType, "subtree") == "xpath" {
+			b.WriteString(fmt.Sprintf(
+				"<xpath-filter%s>%s</xpath-filter>",
+				namespaceAttr,
+				html.EscapeString(filter),
+			))
+		} else {
+			b.WriteString(
+				fmt.Sprintf("<subtree-filter%s>%s</subtree-filter>", namespaceAttr, filter),
+			)
+		}
+	}
+
+	if configFilter != nil {
+		b.WriteString(fmt.Sprintf("<config-filter>%t</config-filter>", *configFilter))
+	}
+
+	if originFilters != "" {
+		for _, origin := range strings.Split(originFilters, constants.LibScrapliDelimiter) {
+			b.WriteString(fmt.Sprintf(
+				`<origin-filter xmlns:or="%s">%s</origin-filter>`,
+				netconfOriginNamespace,
+				origin,
+			))
+		}
+	}
+
+	if maxDepth != 0 {
+		b.WriteString(fmt.Sprintf("<max-depth>%d</max-depth>", maxDepth))
+	}
+
+	if withOrigin {
+		b.WriteString("<with-origin/>")
+	}
+
+	b.WriteString(defaultsElement(defaultsType))
+
+	return fmt.Sprintf(`<get-data xmlns="%s">%s</get-data>`, netconfNmdaNamespace, b.String())
+}
+
+func editDataBody(datastore *uint8, content string, defaultOperation *uint8) string {
+	var defaultOperationElement string
+
+	if defaultOperation != nil {
+		defaultOperationElement = fmt.Sprintf(
+			"<default-operation>%s</default-operation>",
+			enumName(defaultOperationNames, defaultOperation, "merge"),
+		)
+	}
+
+	return fmt.Sprintf(
+		`<edit-data xmlns="%s">%s%s<config>%s</config></edit-data>`,
+		netconfNmdaNamespace,
+		nmdaDatastoreElement(datastore, "running"),
+		defaultOperationElement,
+		content,
+	)
+}
+
+func (b *backend) netconfRawRPC(
+	driverPtr uintptr,
+	operationID *uint32,
+	cancel *bool,
+	payload,
+	baseNamespacePrefix,
+	extraNamespaces string,
+) uint8 {
+	return b.netconfRPC(
+		driverPtr,
+		operationID,
+		cancel,
+		baseNamespacePrefix,
+		extraNamespaces,
+		payload,
+		nil,
+	)
+}
+
+func (b *backend) netconfGetConfig(
+	driverPtr uintptr,
+	operationID *uint32,
+	cancel *bool,
+	source *uint8,
+	filter string,
+	filterType *uint8,
+	filterNamespacePrefix,
+	filterNamespace string,
+	defaultsType *uint8,
+) uint8 {
+	return b.netconfRPC(
+		driverPtr,
+		operationID,
+		cancel,
+		"",
+		"",
+		getConfigBody(
+			source,
+			filter,
+			filterType,
+			filterNamespacePrefix,
+			filterNamespace,
+			defaultsType,
+		),
+		nil,
+	)
+}
+
+func (b *backend) netconfEditConfig(
+	driverPtr uintptr,
+	operationID *uint32,
+	cancel *bool,
+	config string,
+	target,
+	defaultOperation,
+	testOption,
+	errorOption *uint8,
+) uint8 {
+	return b.netconfRPC(
+		driverPtr,
+		operationID,
+		cancel,
+		"",
+		"",
+		editConfigBody(config, target, defaultOperation, testOption, errorOption),
+		nil,
+	)
+}
+
+func (b *backend) netconfCopyConfig(
+	driverPtr uintptr,
+	operationID *uint32,
+	cancel *bool,
+	target,
+	source *uint8,
+) uint8 {
+	return b.netconfRPC(
+		driverPtr,
+		operationID,
+		cancel,
+		"",
+		"",
+		fmt.Sprintf(
+			"<copy-config>%s%s</copy-config>",
+			datastoreElement("target", target, "startup"),
+			datastoreElement("source", source, "running"),
+		),
+		nil,
+	)
+}
+
+func (b *backend) netconfDeleteConfig(
+	driverPtr uintptr,
+	operationID *uint32,
+	cancel *bool,
+	target *uint8,
+) uint8 {
+	return b.netconfRPC(
+		driverPtr,
+		operationID,
+		cancel,
+		"",
+		"",
+		fmt.Sprintf(
+			"<delete-config>%s</delete-config>",
+			datastoreElement("target", target, "startup"),
+		),
+		nil,
+	)
+}
+
+func (b *backend) netconfLock(
+	driverPtr uintptr,
+	operationID *uint32,
+	cancel *bool,
+	target *uint8,
+) uint8 {
+	return b.netconfRPC(
+		driverPtr,
+		operationID,
+		cancel,
+		"",
+		"",
+		fmt.Sprintf("<lock>%s</lock>", datastoreElement("target", target, "running")),
+		nil,
+	)
+}
+
+func (b *backend) netconfUnlock(
+	driverPtr uintptr,
+	operationID *uint32,
+	cancel *bool,
+	target *uint8,
+) uint8 {
+	return b.netconfRPC(
+		driverPtr,
+		operationID,
+		cancel,
+		"",
+		"",
+		fmt.Sprintf("<unlock>%s</unlock>", datastoreElement("target", target, "running")),
+		nil,
+	)
+}
+
+func (b *backend) netconfGet(
+	driverPtr uintptr,
+	operationID *uint32,
+	cancel *bool,
+	filter string,
+	filterType *uint8,
+	filterNamespacePrefix,
+	filterNamespace string,
+	defaultsType *uint8,
+) uint8 {
+	return b.netconfRPC(
+		driverPtr,
+		operationID,
+		cancel,
+		"",
+		"",
+		getBody(filter, filterType, filterNamespacePrefix, filterNamespace, defaultsType),
+		nil,
+	)
+}
+
+func (b *backend) netconfCloseSession(
+	driverPtr uintptr,
+	operationID *uint32,
+	cancel *bool,
+) uint8 {
+	return b.netconfRPC(driverPtr, operationID, cancel, "", "", "<close-session/>", nil)
+}
+
+func (b *backend) netconfKillSession(
+	driverPtr uintptr,
+	operationID *uint32,
+	cancel *bool,
+	sessionID uint64,
+) uint8 {
+	return b.netconfRPC(
+		driverPtr,
+		operationID,
+		cancel,
+		"",
+		"",
+		fmt.Sprintf("<kill-session><session-id>%d</session-id></kill-session>", sessionID),
+		nil,
+	)
+}
+
+func (b *backend) netconfCommit(driverPtr uintptr, operationID *uint32, cancel *bool) uint8 {
+	return b.netconfRPC(driverPtr, operationID, cancel, "", "", "<commit/>", nil)
+}
+
+func (b *backend) netconfDiscard(driverPtr uintptr, operationID *uint32, cancel *bool) uint8 {
+	return b.netconfRPC(driverPtr, operationID, cancel, "", "", "<discard-changes/>", nil)
+}
+
+func (b *backend) netconfCancelCommit(
+	driverPtr uintptr,
+	operationID *uint32,
+	cancel *bool,
+	persistID string,
+) uint8 {
+	body := "<cancel-commit/>"
+
+	if persistID != "" {
+		body = fmt.Sprintf("<cancel-commit><persist-id>%s</persist-id></cancel-commit>", persistID)
+	}
+
+	return b.netconfRPC(driverPtr, operationID, cancel, "", "", body, nil)
+}
+
+func (b *backend) netconfValidate(
+	driverPtr uintptr,
+	operationID *uint32,
+	cancel *bool,
+	source *uint8,
+) uint8 {
+	return b.netconfRPC(
+		driverPtr,
+		operationID,
+		cancel,
+		"",
+		"",
+		fmt.Sprintf("<validate>%s</validate>", datastoreElement("source", source, "running")),
+		nil,
+	)
+}
+
+func (b *backend) netconfGetSchema(
+	driverPtr uintptr,
+	operationID *uint32,
+	cancel *bool,
+	identifier,
+	version string,
+	format *uint8,
+) uint8 {
+	return b.netconfRPC(
+		driverPtr,
+		operationID,
+		cancel,
+		"",
+		"",
+		getSchemaBody(identifier, version, format),
+		nil,
+	)
+}
+
+func (b *backend) netconfGetData(
+	driverPtr uintptr,
+	operationID *uint32,
+	cancel *bool,
+	datastore *uint8,
+	filter string,
+	filterType *uint8,
+	filterNamespacePrefix,
+	filterNamespace string,
+	configFilter *bool,
+	originFilters string,
+	maxDepth uint32,
+	withOrigin bool,
+	defaultsType *uint8,
+) uint8 {
+	return b.netconfRPC(
+		driverPtr,
+		operationID,
+		cancel,
+		"",
+		"",
+		getDataBody(
+			datastore,
+			filter,
+			filterType,
+			filterNamespacePrefix,
+			filterNamespace,
+			configFilter,
+			originFilters,
+			maxDepth,
+			withOrigin,
+			defaultsType,
+		),
+		nil,
+	)
+}
+
+func (b *backend) netconfEditData(
+	driverPtr uintptr,
+	operationID *uint32,
+	cancel *bool,
+	datastore *uint8,
+	content string,
+	defaultOperation *uint8,
+) uint8 {
+	return b.netconfRPC(
+		driverPtr,
+		operationID,
+		cancel,
+		"",
+		"",
+		editDataBody(datastore, content, defaultOperation),
+		nil,
+	)
+}
+
+func (b *backend) netconfAction(
+	driverPtr uintptr,
+	operationID *uint32,
+	cancel *bool,
+	action string,
+) uint8 {
+	return b.netconfRPC(
+		driverPtr,
+		operationID,
+		cancel,
+		"",
+		"",
+		fmt.Sprintf(`<action xmlns="%s">%s</action>`, netconfActionNamespace, action),
+		nil,
+	)
+}
@@ -0,0 +1,50 @@
+package gobackend
+
+import (
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
+	scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
+)
+
+// RenderedRPC is a netconf rpc as rendered by RenderNetconfRPC.
+type RenderedRPC struct {
+	BaseNamespacePrefix string
+	ExtraNamespaces     string
+	// Body is the content of the rpc element.
+	Body string
+}
+
+// RenderNetconfRPC renders the rpc that submit submits via the given netconf mapping, without
+// submitting anything anywhere -- the rpc is rendered exactly as the go backend would render it,
+// libscrapli renders the same rpcs but may differ in details like namespace prefixes.
+func RenderNetconfRPC(
+	submit func(m *scrapligoffi.NetconfMapping, id *uint32, cancel *scrapligoffi.CancelFlag) error,
+) (*RenderedRPC, error) {
+	var rendered *RenderedRPC
+
+	b := &backend{
+		options: scrapligointernal.NewOptions(),
+		drivers: map[uintptr]any{},
+		render: func(r *RenderedRPC) {
+			rendered = r
+		},
+	}
+
+	m, err := scrapligoffi.NewMappingFromSymbols(b.symbols())
+	if err != nil {
+		return nil, err
+	}
+
+	var operationID uint32
+
+	err = submit(&m.Netconf, &operationID, scrapligoffi.NewCancelFlag())
+	if err != nil {
+		return nil, err
+	}
+
+	if rendered == nil {
+		return nil, scrapligoerrors.NewFfiError("rpc submission did not render an rpc", nil)
+	}
+
+	return rendered, nil
+}
@@ -0,0 +1,516 @@
+package gobackend
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+)
+
+const (
+	defaultReadSize       = 8_192
+	defaultMaxSearchDepth = 512
+	defaultReturnChar     = "\n"
+
+	// controlCheckInterval is how often a waiting operation checks if it has been cancelled or
+	// timed out when no new output arrives.
+	controlCheckInterval = 10 * time.Millisecond
+
+	// maxAuthAttempts is the number of times we will respond to the same kind of auth prompt before
+	// deciding that auth has failed.
+	maxAuthAttempts = 2
+)
+
+var (
+	errCancelled = errors.New("operation cancelled")
+	errTimeout   = errors.New("operation timed out")
+
+	defaultUsernamePattern   = regexp.MustCompile(`(?im)^(.*username:)|(.*login:)\s?$`)
+	defaultPasswordPattern   = regexp.MustCompile(`(?im)^(.*@.*)?\s?password:\s?$`)
+	defaultPassphrasePattern = regexp.MustCompile(`(?i)enter passphrase for key`)
+)
+
+// control governs a single operation -- its cancel flag and (optional) deadline.
+type control struct {
+	cancel   *bool
+	deadline time.Time
+}
+
+func newControl(cancel *bool, timeoutNs *uint64) *control {
+	c := &control{cancel: cancel}
+
+	if timeoutNs != nil && *timeoutNs != 0 {
+		c.deadline = time.Now().Add(time.Duration(*timeoutNs)) //nolint: gosec
+	}
+
+	return c
+}
+
+// check returns an error if the operation has been cancelled or has timed out.
+func (c *control) check() error {
+	if c.cancel != nil && cancelRequested(c.cancel) {
+		return errCancelled
+	}
+
+	if !c.deadline.IsZero() && time.Now().After(c.deadline) {
+		return errTimeout
+	}
+
+	return nil
+}
+
+// context returns a context that is cancelled when the operation is cancelled or times out, for
+// use with things that want a context (dialing).
+func (c *control) context() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	if !c.deadline.IsZero() {
+		var deadlineCancel context.CancelFunc
+
+		ctx, deadlineCancel = context.WithDeadline(ctx, c.deadline)
+
+		parentCancel := cancel
+
+		cancel = func() {
+			deadlineCancel()
+			parentCancel()
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(controlCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if c.check() != nil {
+					cancel()
+
+					return
+				}
+			}
+		}
+	}()
+
+	return ctx, cancel
+}
+
+// cancelRequested reads the cancel flag handed to us by the ffi mapping. The flag is always the
+// byte of an ffi.CancelFlag (a uint32 that is only ever written atomically), so we atomically load
+// the (aligned) word it lives in rather than reading the byte directly.
+func cancelRequested(cancel *bool) bool {
+	p := unsafe.Pointer(cancel)
+
+	word := (*uint32)(unsafe.Add(p, -int(uintptr(p)%unsafe.Alignof(uint32(0)))))
+
+	return atomic.LoadUint32(word) != 0
+}
+
+// session is the go flavor of a libscrapli session -- it continuously reads from the transport
+// into a queue of chunks that operations then consume by searching for whatever they are waiting
+// for (a prompt, the echo of an input, a netconf message delimiter...). Like libscrapli, chunks are
+// searched one at a time in the order they arrived rather than all at once, so a match is always
+// the *first* possible match (which matters a lot for the test transport that has *all* output up
+// front).
+type session struct {
+	t          transport
+	returnChar string
+	record     func(b []byte)
+
+	lock    sync.Mutex
+	buf     []byte
+	chunks  [][]byte
+	readErr error
+	notify  chan struct{}
+}
+
+func newSession(t transport, readSize int, returnChar string, record func(b []byte)) *session {
+	if readSize <= 0 {
+		readSize = defaultReadSize
+	}
+
+	if returnChar == "" {
+		returnChar = defaultReturnChar
+	}
+
+	s := &session{
+		t:          t,
+		returnChar: returnChar,
+		record:     record,
+		notify:     make(chan struct{}, 1),
+	}
+
+	go s.read(readSize)
+
+	return s
+}
+
+func (s *session) read(readSize int) {
+	p := make([]byte, readSize)
+
+	for {
+		n, err := s.t.Read(p)
+		if n > 0 {
+			if s.record != nil {
+				s.record(p[:n])
+			}
+
+			s.lock.Lock()
+			s.chunks = append(s.chunks, bytes.Clone(p[:n]))
+			s.lock.Unlock()
+
+			s.signal()
+		}
+
+		if err != nil {
+			s.lock.Lock()
+			s.readErr = err
+			s.lock.Unlock()
+
+			s.signal()
+
+			return
+		}
+	}
+}
+
+func (s *session) signal() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// nextChunk moves the next chunk (if any) into the search buffer, must be called with the lock
+// held.
+func (s *session) nextChunk() bool {
+	if len(s.chunks) == 0 {
+		return false
+	}
+
+	s.buf = append(s.buf, s.chunks[0]...)
+	s.chunks = s.chunks[1:]
+
+	return true
+}
+
+func (s *session) write(input string) error {
+	_, err := s.t.Write([]byte(input))
+	if err != nil {
+		return scrapligoerrors.NewFfiError("failed writing to transport", err)
+	}
+
+	return nil
+}
+
+func (s *session) writeAndReturn(input string) error {
+	return s.write(input + s.returnChar)
+}
+
+func (s *session) writeReturn() error {
+	return s.write(s.returnChar)
+}
+
+// readAvailable consumes and returns up to size bytes of whatever output is available, without
+// waiting for anything.
+func (s *session) readAvailable(size int) []byte {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for len(s.buf) < size {
+		if !s.nextChunk() {
+			break
+		}
+	}
+
+	n := min(size, len(s.buf))
+
+	out := bytes.Clone(s.buf[:n])
+
+	s.buf = s.buf[n:]
+
+	return out
+}
+
+// readUntil waits until match finds a match in the unconsumed output, then consumes and returns
+// the output up to the end of that match. match returns the end index of the match or -1.
+func (s *session) readUntil(c *control, match func(b []byte) int) ([]byte, error) {
+	timer := time.NewTimer(controlCheckInterval)
+	defer timer.Stop()
+
+	for {
+		s.lock.Lock()
+
+		end := match(s.buf)
+
+		for end < 0 && s.nextChunk() {
+			end = match(s.buf)
+		}
+
+		if end >= 0 {
+			out := bytes.Clone(s.buf[:end])
+
+			s.buf = s.buf[end:]
+
+			s.lock.Unlock()
+
+			return out, nil
+		}
+
+		readErr := s.readErr
+
+		s.lock.Unlock()
+
+		if readErr != nil {
+			return nil, scrapligoerrors.NewFfiError("failed reading from transport", readErr)
+		}
+
+		err := c.check()
+		if err != nil {
+			return nil, err
+		}
+
+		select {
+		case <-s.notify:
+		case <-timer.C:
+			timer.Reset(controlCheckInterval)
+		}
+	}
+}
+
+// readAny waits for any output at all and consumes it.
+func (s *session) readAny(c *control) ([]byte, error) {
+	return s.readUntil(c, func(b []byte) int {
+		if len(b) == 0 {
+			return -1
+		}
+
+		return len(b)
+	})
+}
+
+func (s *session) close() error {
+	return s.t.Close()
+}
+
+// exactMatcher matches the first occurrence of the given input. Output that was already searched
+// is not searched again, so (like all matchers) the returned matcher is only good for one read.
+func exactMatcher(input []byte) func(b []byte) int {
+	searched := 0
+
+	return func(b []byte) int {
+		start := max(0, searched-len(input)+1)
+
+		idx := bytes.Index(b[start:], input)
+		if idx < 0 {
+			searched = len(b)
+
+			return -1
+		}
+
+		return start + idx + len(input)
+	}
+}
+
+// fuzzyMatcher matches once all chars of the given input have been seen in order, regardless of
+// what else is between them -- devices like to insert backspaces, line wraps and the like in
+// echoed inputs.
+func fuzzyMatcher(input []byte) func(b []byte) int {
+	return func(b []byte) int {
+		if len(input) == 0 {
+			return 0
+		}
+
+		inputIdx := 0
+
+		for idx, c := range b {
+			if c != input[inputIdx] {
+				continue
+			}
+
+			inputIdx++
+
+			if inputIdx == len(input) {
+				return idx + 1
+			}
+		}
+
+		return -1
+	}
+}
+
+// promptMatcher matches when the given pattern matches at the very end of the output (ignoring
+// trailing spaces), only the last searchDepth bytes are searched.
+func promptMatcher(pattern *regexp.Regexp, searchDepth int) func(b []byte) int {
+	return func(b []byte) int {
+		if promptIndex(pattern, b, searchDepth) < 0 {
+			return -1
+		}
+
+		return len(b)
+	}
+}
+
+// promptIndex returns the index the prompt (matching pattern) at the end of b starts at, or -1.
+func promptIndex(pattern *regexp.Regexp, b []byte, searchDepth int) int {
+	start := max(0, len(b)-searchDepth)
+
+	matches := pattern.FindAllIndex(b[start:], -1)
+	if len(matches) == 0 {
+		return -1
+	}
+
+	last := matches[len(matches)-1]
+
+	if len(bytes.Trim(b[start+last[1]:], " \t")) != 0 {
+		return -1
+	}
+
+	return start + last[0]
+}
+
+// anyMatcher matches the first of the given patterns found (searching in order), the index of the
+// matched pattern is written to which.
+func anyMatcher(which *int, patterns ...func(b []byte) int) func(b []byte) int {
+	return func(b []byte) int {
+		for idx, p := range patterns {
+			end := p(b)
+			if end >= 0 {
+				*which = idx
+
+				return end
+			}
+		}
+
+		return -1
+	}
+}
+
+// patternMatcher matches the first occurrence of the pattern anywhere in the output.
+func patternMatcher(pattern *regexp.Regexp) func(b []byte) int {
+	return func(b []byte) int {
+		loc := pattern.FindIndex(b)
+		if loc == nil {
+			return -1
+		}
+
+		return loc[1]
+	}
+}
+
+// inSessionAuthPatterns returns the username, password and passphrase patterns for in session auth.
+func inSessionAuthPatterns(
+	usernamePattern, passwordPattern, passphrasePattern string,
+) (*regexp.Regexp, *regexp.Regexp, *regexp.Regexp, error) {
+	username, password, passphrase := defaultUsernamePattern,
+		defaultPasswordPattern,
+		defaultPassphrasePattern
+
+	var err error
+
+	if usernamePattern != "" {
+		username, err = compilePattern(usernamePattern)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	if passwordPattern != "" {
+		password, err = compilePattern(passwordPattern)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	if passphrasePattern != "" {
+		passphrase, err = compilePattern(passphrasePattern)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	return username, password, passphrase, nil
+}
+
+type authCredentials struct {
+	username   string
+	password   string
+	passphrase string
+}
+
+// inSessionAuth handles "in session" auth -- responding to username/password/passphrase prompts
+// until done matches. The output read during auth is returned.
+func (s *session) inSessionAuth(
+	c *control,
+	creds authCredentials,
+	patterns [3]*regexp.Regexp,
+	done func(b []byte) int,
+) ([]byte, error) {
+	var (
+		out      []byte
+		attempts [3]int
+	)
+
+	for {
+		which := -1
+
+		usernameMatcher := patternMatcher(patterns[0])
+		if attempts[1] > 0 {
+			// once the password is sent we are past the username prompt, and given we match on
+			// streaming output things like "Last login:" in a banner would otherwise look like one
+			usernameMatcher = func(_ []byte) int { return -1 }
+		}
+
+		b, err := s.readUntil(
+			c,
+			anyMatcher(
+				&which,
+				done,
+				usernameMatcher,
+				patternMatcher(patterns[1]),
+				patternMatcher(patterns[2]),
+			),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, b...)
+
+		if which == 0 {
+			return out, nil
+		}
+
+		promptKind := which - 1
+
+		attempts[promptKind]++
+		if attempts[promptKind] > maxAuthAttempts {
+			return nil, scrapligoerrors.NewFfiError("in session authentication failed", nil)
+		}
+
+		var response string
+
+		switch promptKind {
+		case 0:
+			response = creds.username
+		case 1:
+			response = creds.password
+		default:
+			response = creds.passphrase
+		}
+
+		err = s.writeAndReturn(response)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
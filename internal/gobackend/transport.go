@@ -0,0 +1,421 @@
+package gobackend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const (
+	defaultTermHeight = 255
+	defaultTermWidth  = 256
+	netconfSubsystem  = "netconf"
+)
+
+// transport is the byte stream a session reads from and writes to.
+type transport interface {
+	io.ReadWriteCloser
+}
+
+// openTransport opens the transport for the given options, if subsystem is non empty the ssh
+// subsystem is requested rather than a pty/shell (i.e. for netconf).
+func openTransport(
+	ctx context.Context,
+	host string,
+	o *scrapligointernal.Options,
+	subsystem string,
+) (transport, error) {
+	switch o.TransportKind {
+	case scrapligointernal.TransportKindTest:
+		return openTestTransport(o.Transport.Test.F)
+	case scrapligointernal.TransportKindBin, scrapligointernal.TransportKindSSH2:
+		return openSSHTransport(ctx, host, o, subsystem)
+	case scrapligointernal.TransportKindTelnet:
+	}
+
+	return nil, scrapligoerrors.NewOptionsError(
+		"telnet transport is not supported by the go backend",
+		nil,
+	)
+}
+
+// testTransport replays the contents of a file (a session recording) as if it were the output of
+// a device, anything written to it is discarded -- mirrors libscrapli's "test" transport.
+type testTransport struct {
+	f *os.File
+}
+
+func openTestTransport(path string) (transport, error) {
+	f, err := os.Open(path) //nolint: gosec
+	if err != nil {
+		return nil, scrapligoerrors.NewOptionsError(
+			fmt.Sprintf("failed opening test transport file %q", path),
+			err,
+		)
+	}
+
+	return &testTransport{f: f}, nil
+}
+
+func (t *testTransport) Read(p []byte) (int, error) {
+	return t.f.Read(p)
+}
+
+func (t *testTransport) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (t *testTransport) Close() error {
+	return t.f.Close()
+}
+
+// sshTransport is a golang.org/x/crypto/ssh client session, either a pty/shell or a subsystem.
+type sshTransport struct {
+	clients []*ssh.Client
+	session *ssh.Session
+	stdin   io.WriteCloser
+	stdout  io.Reader
+}
+
+func openSSHTransport(
+	ctx context.Context,
+	host string,
+	o *scrapligointernal.Options,
+	subsystem string,
+) (transport, error) {
+	t := &sshTransport{}
+
+	err := t.dial(ctx, host, o)
+	if err != nil {
+		_ = t.Close()
+
+		return nil, err
+	}
+
+	err = t.startSession(o, subsystem)
+	if err != nil {
+		_ = t.Close()
+
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (t *sshTransport) dial(ctx context.Context, host string, o *scrapligointernal.Options) error {
+	hostKeyCallback, err := getHostKeyCallback(o)
+	if err != nil {
+		return err
+	}
+
+	targetConfig, err := getClientConfig(
+		o.Auth.Username,
+		o.Auth.Password,
+		o.Auth.PrivateKeyPath,
+		o.Auth.PrivateKeyContent,
+		o.Auth.PrivateKeyPassphrase,
+		hostKeyCallback,
+	)
+	if err != nil {
+		return err
+	}
+
+	target := net.JoinHostPort(host, strconv.Itoa(int(o.Port)))
+
+	if o.Transport.SSH2.ProxyJumpHost == "" {
+		client, err := dialSSH(ctx, nil, target, targetConfig)
+		if err != nil {
+			return err
+		}
+
+		t.clients = append(t.clients, client)
+
+		return nil
+	}
+
+	jumpConfig, err := getClientConfig(
+		o.Transport.SSH2.ProxyJumpUsername,
+		o.Transport.SSH2.ProxyJumpPassword,
+		o.Transport.SSH2.ProxyJumpPrivateKeyPath,
+		"",
+		o.Transport.SSH2.ProxyJumpPrivateKeyPassphrase,
+		hostKeyCallback,
+	)
+	if err != nil {
+		return err
+	}
+
+	jumpPort := o.Transport.SSH2.ProxyJumpPort
+	if jumpPort == 0 {
+		jumpPort = 22
+	}
+
+	jumpClient, err := dialSSH(
+		ctx,
+		nil,
+		net.JoinHostPort(o.Transport.SSH2.ProxyJumpHost, strconv.Itoa(int(jumpPort))),
+		jumpConfig,
+	)
+	if err != nil {
+		return err
+	}
+
+	t.clients = append(t.clients, jumpClient)
+
+	client, err := dialSSH(ctx, jumpClient, target, targetConfig)
+	if err != nil {
+		return err
+	}
+
+	t.clients = append(t.clients, client)
+
+	return nil
+}
+
+// dialSSH dials the target, via the jump client if provided.
+func dialSSH(
+	ctx context.Context,
+	jumpClient *ssh.Client,
+	target string,
+	config *ssh.ClientConfig,
+) (*ssh.Client, error) {
+	var (
+		conn net.Conn
+		err  error
+	)
+
+	if jumpClient != nil {
+		conn, err = jumpClient.DialContext(ctx, "tcp", target)
+	} else {
+		dialer := &net.Dialer{}
+
+		conn, err = dialer.DialContext(ctx, "tcp", target)
+	}
+
+	if err != nil {
+		return nil, scrapligoerrors.NewFfiError(fmt.Sprintf("failed dialing %q", target), err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, target, config)
+	if err != nil {
+		_ = conn.Close()
+
+		return nil, scrapligoerrors.NewFfiError(
+			fmt.Sprintf("failed establishing ssh connection to %q", target),
+			err,
+		)
+	}
+
+	// the deadline only covers connection establishment, the session governs timeouts afterward
+	_ = conn.SetDeadline(time.Time{})
+
+	return ssh.NewClient(sshConn, chans, reqs), nil
+}
+
+func getHostKeyCallback(o *scrapligointernal.Options) (ssh.HostKeyCallback, error) {
+	if !o.Transport.Bin.EnableStrictKey {
+		return ssh.InsecureIgnoreHostKey(), nil //nolint: gosec
+	}
+
+	knownHostsPath := o.Transport.Bin.KnownHostsPath
+	if knownHostsPath == "" {
+		knownHostsPath = o.Transport.SSH2.KnownHostsPath
+	}
+
+	if knownHostsPath == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, scrapligoerrors.NewOptionsError("failed finding known hosts file", err)
+		}
+
+		knownHostsPath = fmt.Sprintf("%s/.ssh/known_hosts", homeDir)
+	}
+
+	cb, err := knownhosts.New(knownHostsPath)
+	if err != nil {
+		return nil, scrapligoerrors.NewOptionsError(
+			fmt.Sprintf("failed loading known hosts file %q", knownHostsPath),
+			err,
+		)
+	}
+
+	return cb, nil
+}
+
+func getClientConfig(
+	username,
+	password,
+	privateKeyPath,
+	privateKeyContent,
+	privateKeyPassphrase string,
+	hostKeyCallback ssh.HostKeyCallback,
+) (*ssh.ClientConfig, error) {
+	var authMethods []ssh.AuthMethod
+
+	if privateKeyPath != "" || privateKeyContent != "" {
+		signer, err := getSigner(privateKeyPath, privateKeyContent, privateKeyPassphrase)
+		if err != nil {
+			return nil, err
+		}
+
+		authMethods = append(authMethods, ssh.PublicKeys(signer))
+	}
+
+	if password != "" {
+		authMethods = append(
+			authMethods,
+			ssh.Password(password),
+			ssh.KeyboardInteractive(
+				func(_, _ string, questions []string, _ []bool) ([]string, error) {
+					answers := make([]string, len(questions))
+
+					for idx := range questions {
+						answers[idx] = password
+					}
+
+					return answers, nil
+				},
+			),
+		)
+	}
+
+	return &ssh.ClientConfig{
+		User:            username,
+		Auth:            authMethods,
+		HostKeyCallback: hostKeyCallback,
+	}, nil
+}
+
+func getSigner(privateKeyPath, privateKeyContent, passphrase string) (ssh.Signer, error) {
+	key := []byte(privateKeyContent)
+
+	if privateKeyPath != "" {
+		var err error
+
+		key, err = os.ReadFile(privateKeyPath) //nolint: gosec
+		if err != nil {
+			return nil, scrapligoerrors.NewOptionsError(
+				fmt.Sprintf("failed reading private key %q", privateKeyPath),
+				err,
+			)
+		}
+	}
+
+	var (
+		signer ssh.Signer
+		err    error
+	)
+
+	if passphrase != "" {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(key, []byte(passphrase))
+	} else {
+		signer, err = ssh.ParsePrivateKey(key)
+	}
+
+	if err != nil {
+		return nil, scrapligoerrors.NewOptionsError("failed parsing private key", err)
+	}
+
+	return signer, nil
+}
+
+func (t *sshTransport) startSession(o *scrapligointernal.Options, subsystem string) error {
+	session, err := t.clients[len(t.clients)-1].NewSession()
+	if err != nil {
+		return scrapligoerrors.NewFfiError("failed opening ssh session", err)
+	}
+
+	t.session = session
+
+	t.stdin, err = session.StdinPipe()
+	if err != nil {
+		return scrapligoerrors.NewFfiError("failed opening ssh session stdin", err)
+	}
+
+	t.stdout, err = session.StdoutPipe()
+	if err != nil {
+		return scrapligoerrors.NewFfiError("failed opening ssh session stdout", err)
+	}
+
+	if subsystem != "" {
+		err = session.RequestSubsystem(subsystem)
+		if err != nil {
+			return scrapligoerrors.NewFfiError(
+				fmt.Sprintf("failed requesting %q subsystem", subsystem),
+				err,
+			)
+		}
+
+		return nil
+	}
+
+	termHeight, termWidth := defaultTermHeight, defaultTermWidth
+
+	if o.Transport.Bin.TermHeight != nil {
+		termHeight = int(*o.Transport.Bin.TermHeight)
+	}
+
+	if o.Transport.Bin.TermWidth != nil {
+		termWidth = int(*o.Transport.Bin.TermWidth)
+	}
+
+	err = session.RequestPty(
+		"xterm",
+		termHeight,
+		termWidth,
+		ssh.TerminalModes{ssh.ECHO: 1},
+	)
+	if err != nil {
+		return scrapligoerrors.NewFfiError("failed requesting pty", err)
+	}
+
+	err = session.Shell()
+	if err != nil {
+		return scrapligoerrors.NewFfiError("failed starting shell", err)
+	}
+
+	return nil
+}
+
+func (t *sshTransport) Read(p []byte) (int, error) {
+	return t.stdout.Read(p)
+}
+
+func (t *sshTransport) Write(p []byte) (int, error) {
+	return t.stdin.Write(p)
+}
+
+func (t *sshTransport) Close() error {
+	var errs []error
+
+	if t.session != nil {
+		err := t.session.Close()
+		if err != nil && !errors.Is(err, io.EOF) {
+			errs = append(errs, err)
+		}
+	}
+
+	// close in reverse order, the target client rides on the jump client (if any)
+	for idx := len(t.clients) - 1; idx >= 0; idx-- {
+		err := t.clients[idx].Close()
+		if err != nil && !errors.Is(err, net.ErrClosed) {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -27,7 +27,12 @@ type loggerCallbackF func(level uint8, message string)
 // libscrapli, and it properly dispatches logs based on the id which is the pointer to the Cli or
 // Netconf object.
 type LoggerDispatcher interface {
-	Register(userData uintptr, logger any, logLevel scrapligologging.LogLevel) error
+	Register(
+		userData uintptr,
+		logger any,
+		logLevel scrapligologging.LogLevel,
+		redact func(s string) string,
+	) error
 	Deregister(userData uintptr)
 
 	GetLoggerCallback() uintptr
@@ -57,10 +62,13 @@ type loggerDispatcher struct {
 	cb      uintptr
 }
 
+// Register registers the logger for userData, if redact is provided messages are passed through
+// it before they reach the logger.
 func (l *loggerDispatcher) Register( //nolint: gocyclo
 	userData uintptr,
 	logger any,
 	logLevel scrapligologging.LogLevel,
+	redact func(s string) string,
 ) error {
 	l.lock.Lock()
 	defer l.lock.Unlock()
@@ -129,6 +137,14 @@ func (l *loggerDispatcher) Register( //nolint: gocyclo
 		return scrapligoerrors.NewFfiError(fmt.Sprintf("invalid logger type %T", tl), nil)
 	}
 
+	if redact != nil {
+		lf := l.loggers[userData]
+
+		l.loggers[userData] = func(level uint8, message string) {
+			lf(level, redact(message))
+		}
+	}
+
 	return nil
 }
 
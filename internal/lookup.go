@@ -0,0 +1,26 @@
+package internal
+
+import (
+	"fmt"
+)
+
+// GetLookupCallback returns the lookup callback, logging the failures of the callback (which
+// otherwise only surface as the failure of whatever operation needed the value), or nil if no
+// callback is set. Only the go backend invokes the callback, libscrapli has no equivalent.
+func (o *Options) GetLookupCallback() func(host, key string) (string, error) {
+	f := o.Auth.LookupCallback
+	if f == nil {
+		return nil
+	}
+
+	l := o.GetLogger()
+
+	return func(host, key string) (string, error) {
+		v, err := f(host, key)
+		if err != nil {
+			l.Warn(fmt.Sprintf("lookup of key %q for host %q failed: %s", key, host, err))
+		}
+
+		return v, err
+	}
+}
@@ -1,9 +1,16 @@
 package internal
 
 import (
+	"sync"
+	"sync/atomic"
 	"unsafe"
 
+	scrapligoaudit "github.com/scrapli/scrapligo/v2/audit"
+	scrapligocredentials "github.com/scrapli/scrapligo/v2/credentials"
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
 	scrapligologging "github.com/scrapli/scrapligo/v2/logging"
+	scrapligometrics "github.com/scrapli/scrapligo/v2/metrics"
+	scrapligopolicy "github.com/scrapli/scrapligo/v2/policy"
 )
 
 // TransportKind is an enum(ish) representing the kind of transport a Cli should use.
@@ -17,6 +24,16 @@ const (
 	TransportKindTest
 )
 
+// BackendKind is an enum(ish) representing the backend that implements the driver -- either
+// libscrapli (via ffi) or the pure go backend.
+type BackendKind uint8
+
+// enumerations of BackendKind.
+const (
+	BackendKindLibscrapli BackendKind = iota
+	BackendKindGo
+)
+
 var zero uint64 //nolint: gochecknoglobals
 
 // Options holds options for all driver kinds (cli and netconf).
@@ -31,9 +48,21 @@ type Options struct {
 
 	TransportKind TransportKind
 
+	Backend BackendKind
+
 	Session   SessionOptions
 	Auth      AuthOptions
 	Transport TransportOptions
+
+	Tracing TracingOptions
+	Metrics scrapligometrics.Recorder
+	Policy  *scrapligopolicy.Policy
+	DryRun  DryRunOptions
+	Audit   *scrapligoaudit.Sink
+
+	Redaction    RedactionOptions
+	redactor     atomic.Pointer[Redactor]
+	redactorOnce sync.Once
 }
 
 // NewOptions returns a new options object.
@@ -42,6 +71,7 @@ func NewOptions() *Options {
 		Logger:        nil,
 		LoggerLevel:   scrapligologging.Warn,
 		TransportKind: TransportKindBin,
+		Backend:       defaultBackendKind,
 		Port:          0,
 		Cli: CliOptions{
 			DefinitionFileOrName:        "default",
@@ -57,16 +87,40 @@ func NewOptions() *Options {
 
 // GetLogger returns the AnyLogger wrapped around the configured logger options.
 func (o *Options) GetLogger() *scrapligologging.AnyLogger {
-	return scrapligologging.LoggerToAnyLogger(o.Logger, o.LoggerLevel)
+	l := scrapligologging.LoggerToAnyLogger(o.Logger, o.LoggerLevel)
+
+	if o.Redaction.Enabled {
+		l = l.Redacting(o.redact)
+	}
+
+	return l
 }
 
 // Apply applies the Options to the given driver options struct at optionsPtr.
 func (o *Options) Apply(userData, optionsPtr uintptr) error {
+	if o.Auth.LookupCallback != nil && o.Backend != BackendKindGo {
+		// libscrapli has no lookup callback in its driver options (yet), so there is nothing for a
+		// lookup dispatcher (like the logger/recorder ones) to be registered with -- until it has
+		// one only the go backend can invoke the callback
+		return scrapligoerrors.NewOptionsError(
+			"lookup callbacks are not supported by libscrapli yet, use the go backend",
+			nil,
+		)
+	}
+
 	opts := (*driverOptions)(unsafe.Pointer(optionsPtr)) //nolint: govet
 
+	// secrets may have changed since the options were built, so the redactor is always rebuilt
+	o.refreshRedactor()
+
+	var redact func(s string) string
+	if o.Redaction.Enabled {
+		redact = o.redact
+	}
+
 	ld := GetLoggerDispatcher()
 
-	err := ld.Register(userData, o.Logger, o.LoggerLevel)
+	err := ld.Register(userData, o.Logger, o.LoggerLevel, redact)
 	if err != nil {
 		return err
 	}
@@ -79,7 +133,7 @@ func (o *Options) Apply(userData, optionsPtr uintptr) error {
 
 	o.Cli.apply(opts)
 	o.Netconf.apply(opts.userData, opts)
-	o.Session.apply(opts.userData, opts)
+	o.Session.apply(opts.userData, opts, o.GetRecorderCallback())
 	o.Auth.apply(opts)
 
 	opts.transportKind = uint8(o.TransportKind)
@@ -108,6 +162,10 @@ type CliOptions struct {
 
 	NormalizeLineFeeds          bool
 	NormalizeTrailingWhitespace bool
+
+	// Interceptors holds the cli.Interceptor chain, as "any" since the type lives in the cli
+	// package.
+	Interceptors []any
 }
 
 func (o *CliOptions) apply(opts *driverOptions) {
@@ -133,6 +191,10 @@ type NetconfOptions struct {
 	PreferredVersion      string
 	MessagePollIntervalNS uint64
 	CapabilitiesCallback  func(serverCapabilities string) string
+
+	// Interceptors holds the netconf.Interceptor chain, as "any" since the type lives in the
+	// netconf package.
+	Interceptors []any
 }
 
 func (o *NetconfOptions) apply(userData uintptr, opts *driverOptions) {
@@ -178,7 +240,11 @@ type SessionOptions struct {
 	RecorderCallback func(buf string)
 }
 
-func (o *SessionOptions) apply(userData uintptr, opts *driverOptions) {
+func (o *SessionOptions) apply(
+	userData uintptr,
+	opts *driverOptions,
+	recorderCallback func(buf string),
+) {
 	if o.ReadSize != nil {
 		opts.session.readSize = o.ReadSize
 	}
@@ -219,10 +285,10 @@ func (o *SessionOptions) apply(userData uintptr, opts *driverOptions) {
 	if o.RecorderPath != "" {
 		opts.session.recordDestination = uintptr(unsafe.Pointer(unsafe.StringData(o.RecorderPath)))
 		opts.session.recordDestinationLen = uintptr(len(o.RecorderPath))
-	} else if o.RecorderCallback != nil {
+	} else if recorderCallback != nil {
 		rd := GetRecorderDispatcher()
 
-		rd.Register(userData, o.RecorderCallback)
+		rd.Register(userData, recorderCallback)
 
 		opts.session.recorderCallback = rd.GetRecorderCallback()
 	}
@@ -243,12 +309,16 @@ type AuthOptions struct {
 	lookupMapVals    []string
 	lookupMapValLens []uint16
 
+	LookupCallback func(host, key string) (string, error)
+
 	ForceInSessionAuth  bool
 	BypassInSessionAuth bool
 
 	UsernamePattern   string
 	PasswordPattern   string
 	PassphrasePattern string
+
+	CredentialProviders []scrapligocredentials.Provider
 }
 
 func (o *AuthOptions) apply(opts *driverOptions) {
@@ -0,0 +1,87 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+
+	"golang.org/x/sys/unix"
+)
+
+// ReadyFd wraps a driver's operation ready fd (libscrapli writes a byte to it for every completed
+// operation) and hands it to the go runtime netpoller. The netpoller is a single, shared,
+// epoll/kqueue based reactor -- so no matter how many drivers are waiting on operations, no os
+// threads are pinned and no poll loops are spinning, waiting goroutines are simply parked until
+// their fd becomes readable.
+type ReadyFd struct {
+	f *os.File
+}
+
+// NewReadyFd returns a ReadyFd for the given (libscrapli owned) fd, closing the ReadyFd never
+// closes the fd out from under libscrapli. The fd must be non-blocking for the netpoller, so where
+// possible (linux) the fd is reopened via /proc, giving us our own file description to set
+// non-blocking and leaving libscrapli's untouched. Elsewhere the fd is duplicated -- a duplicate
+// shares the file description, so this also switches libscrapli's fd to non-blocking. That is
+// tolerable as the fd is the read end of libscrapli's ready pipe, libscrapli only ever writes the
+// (separate) write end, and scrapligo is the only reader.
+func NewReadyFd(fd int) (*ReadyFd, error) {
+	ownFd, err := reopenFd(fd)
+	if err != nil {
+		ownFd, err = unix.Dup(fd)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the fd must be non-blocking for os.NewFile to register it with the netpoller
+	err = unix.SetNonblock(ownFd, true)
+	if err != nil {
+		_ = unix.Close(ownFd)
+
+		return nil, err
+	}
+
+	return &ReadyFd{
+		f: os.NewFile(uintptr(ownFd), fmt.Sprintf("scrapli-ready-fd-%d", fd)),
+	}, nil
+}
+
+// reopenFd opens a new file description for the pipe at fd via procfs.
+func reopenFd(fd int) (int, error) {
+	if runtime.GOOS != "linux" {
+		return -1, unix.ENOTSUP
+	}
+
+	return unix.Open(
+		fmt.Sprintf("/proc/self/fd/%d", fd),
+		unix.O_RDONLY|unix.O_NONBLOCK|unix.O_CLOEXEC,
+		0,
+	)
+}
+
+// Wait blocks until an operation ready signal is available and consumes it.
+func (r *ReadyFd) Wait() error {
+	var out [1]byte
+
+	for {
+		n, err := r.f.Read(out[:])
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return io.ErrUnexpectedEOF
+			}
+
+			return err
+		}
+
+		if n == 1 {
+			return nil
+		}
+	}
+}
+
+// Close closes the ReadyFd, any goroutine blocked in Wait returns with os.ErrClosed.
+func (r *ReadyFd) Close() error {
+	return r.f.Close()
+}
@@ -0,0 +1,231 @@
+package internal_test
+
+import (
+	"errors"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+	"testing"
+	"time"
+
+	scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	benchmarkSessions     = 1_024
+	legacyPollTimeoutMs   = 100
+	threadCreateProfileID = "threadcreate"
+	waiterSettleTime      = 10 * time.Millisecond
+)
+
+func newPipe(t testing.TB) (int, int) {
+	t.Helper()
+
+	var fds [2]int
+
+	err := unix.Pipe(fds[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = unix.Close(fds[0])
+		_ = unix.Close(fds[1])
+	})
+
+	return fds[0], fds[1]
+}
+
+func TestReadyFd(t *testing.T) {
+	readFd, writeFd := newPipe(t)
+
+	r, err := scrapligointernal.NewReadyFd(readFd)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = unix.Write(writeFd, []byte{1, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// two signals, two waits that should not block
+	for range 2 {
+		err = r.Wait()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	done := make(chan error)
+
+	go func() {
+		done <- r.Wait()
+	}()
+
+	err = r.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = <-done
+	if err == nil {
+		t.Fatal("expected error waiting on closed ready fd")
+	}
+
+	// the original (libscrapli owned) fd must be untouched by closing the ready fd
+	_, err = unix.Write(writeFd, []byte{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out [1]byte
+
+	_, err = unix.Read(readFd, out[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// legacyWait is the per operation poll loop that was used prior to the netpoller based ReadyFd,
+// kept here as a baseline for benchmarks.
+func legacyWait(fd int) error {
+	pollFds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}} //nolint: gosec
+
+	for {
+		pollFds[0].Revents = 0
+
+		n, err := unix.Poll(pollFds, legacyPollTimeoutMs)
+		if err != nil {
+			if errors.Is(err, unix.EINTR) {
+				continue
+			}
+
+			return err
+		}
+
+		if n > 0 {
+			break
+		}
+	}
+
+	var out [1]byte
+
+	for {
+		_, err := unix.Read(fd, out[:])
+		if err == nil {
+			return nil
+		}
+
+		if errors.Is(err, unix.EAGAIN) || errors.Is(err, unix.EINTR) {
+			continue
+		}
+
+		return err
+	}
+}
+
+// benchmarkWaiters has benchmarkSessions goroutines each waiting on their own "session" ready fd
+// per iteration, then signals every fd once they are blocked -- an iteration completes when every
+// waiter has woken up, so ns/op is the latency to wake all sessions. The number of os threads
+// created over the course of the benchmark is reported as well.
+func benchmarkWaiters(b *testing.B, newWaiter func(b *testing.B, fd int) func() error) {
+	b.Helper()
+
+	waiters := make([]func() error, benchmarkSessions)
+	writeFds := make([]int, benchmarkSessions)
+
+	for idx := range benchmarkSessions {
+		readFd, writeFd := newPipe(b)
+
+		waiters[idx] = newWaiter(b, readFd)
+		writeFds[idx] = writeFd
+	}
+
+	threadsBefore := pprof.Lookup(threadCreateProfileID).Count()
+
+	for b.Loop() {
+		wg := &sync.WaitGroup{}
+
+		for _, wait := range waiters {
+			wg.Go(func() {
+				err := wait()
+				if err != nil {
+					b.Error(err)
+				}
+			})
+		}
+
+		// give the waiters a chance to actually block, otherwise we are mostly measuring how fast
+		// we can spawn goroutines
+		b.StopTimer()
+		time.Sleep(waiterSettleTime)
+		b.StartTimer()
+
+		for _, writeFd := range writeFds {
+			_, err := unix.Write(writeFd, []byte{1})
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+
+		wg.Wait()
+	}
+
+	b.ReportMetric(
+		float64(pprof.Lookup(threadCreateProfileID).Count()-threadsBefore),
+		"threads-created",
+	)
+}
+
+func BenchmarkReadyFd(b *testing.B) {
+	benchmarkWaiters(b, func(b *testing.B, fd int) func() error {
+		b.Helper()
+
+		r, err := scrapligointernal.NewReadyFd(fd)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Cleanup(func() {
+			_ = r.Close()
+		})
+
+		return r.Wait
+	})
+}
+
+func BenchmarkLegacyPollLoop(b *testing.B) {
+	benchmarkWaiters(b, func(_ *testing.B, fd int) func() error {
+		return func() error {
+			return legacyWait(fd)
+		}
+	})
+}
+
+func TestReadyFdLeavesFdBlocking(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("ready fds only get their own file description on linux")
+	}
+
+	readFd, _ := newPipe(t)
+
+	r, err := scrapligointernal.NewReadyFd(readFd)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = r.Close()
+	}()
+
+	flags, err := unix.FcntlInt(uintptr(readFd), unix.F_GETFL, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if flags&unix.O_NONBLOCK != 0 {
+		t.Fatal("expected the original fd to be left blocking")
+	}
+}
@@ -0,0 +1,136 @@
+package internal
+
+import (
+	"cmp"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Redacted is what redacted secrets are replaced with.
+const Redacted = "<redacted>"
+
+// builtinRedactionPatterns match the secret of common secret/password/key config lines (and the
+// like) -- the keyword (plus any type/encryption marker) is the first group and is retained, the
+// secret itself is redacted. The markers cover cisco (type 0/5/7/8/9), juniper (quoted
+// encrypted-password/secret), nokia (hash/hash2), huawei (cipher/irreversible-cipher), fortinet
+// (ENC) and mikrotik (password=) flavors of these lines. Keywords that are just as common in
+// regular output ("key", "community") only match in explicit key=value/key: value form.
+var builtinRedactionPatterns = []*regexp.Regexp{ //nolint: gochecknoglobals
+	regexp.MustCompile(
+		`(?i)\b((?:secret|password|passwd|encrypted-password|pre-shared-key|key-string|` +
+			`authentication-key|auth-key)` +
+			`(?:[ \t]*=[ \t]*|(?:[ \t]+(?:[0-9]|sha256|sha512|md5|ascii-text|hash2?|` +
+			`encrypted|cipher|irreversible-cipher|simple|enc))*[ \t]+))("[^"\n]*"|[^\s;"]+)`,
+	),
+	regexp.MustCompile(`(?i)\b((?:key|community)[ \t]*[=:][ \t]*)("[^"\n]*"|[^\s;"]+)`),
+}
+
+// RedactionOptions holds the secret redaction options for drivers.
+type RedactionOptions struct {
+	Enabled           bool
+	NoBuiltinPatterns bool
+	Patterns          []*regexp.Regexp
+	Results           bool
+}
+
+// Redactor redacts secrets from strings (log messages, session recordings, results). A nil
+// Redactor redacts nothing.
+type Redactor struct {
+	secrets  *strings.Replacer
+	patterns []*regexp.Regexp
+}
+
+// Redact returns s with all secrets redacted. Patterns with a capture group retain the first
+// group, anything else they match is redacted.
+func (r *Redactor) Redact(s string) string {
+	if r == nil || s == "" {
+		return s
+	}
+
+	if r.secrets != nil {
+		s = r.secrets.Replace(s)
+	}
+
+	for _, pattern := range r.patterns {
+		s = pattern.ReplaceAllString(s, "${1}"+Redacted)
+	}
+
+	return s
+}
+
+// newRedactor returns a Redactor for the configured secrets (password, private key passphrase and
+// lookup map values) plus the builtin and user patterns, or nil if redaction is not enabled.
+func (o *Options) newRedactor() *Redactor {
+	if !o.Redaction.Enabled {
+		return nil
+	}
+
+	secrets := []string{o.Auth.Password, o.Auth.PrivateKeyPassphrase}
+
+	for _, v := range o.Auth.LookupMap {
+		secrets = append(secrets, v)
+	}
+
+	secrets = slices.DeleteFunc(secrets, func(s string) bool { return s == "" })
+
+	// longest first so a secret containing another secret is redacted whole
+	slices.SortFunc(secrets, func(a, b string) int { return cmp.Compare(len(b), len(a)) })
+
+	r := &Redactor{
+		patterns: slices.Clone(o.Redaction.Patterns),
+	}
+
+	if !o.Redaction.NoBuiltinPatterns {
+		r.patterns = append(slices.Clone(builtinRedactionPatterns), r.patterns...)
+	}
+
+	if len(secrets) > 0 {
+		oldnew := make([]string, 0, len(secrets)*2)
+
+		for _, secret := range secrets {
+			oldnew = append(oldnew, secret, Redacted)
+		}
+
+		r.secrets = strings.NewReplacer(oldnew...)
+	}
+
+	return r
+}
+
+// GetRedactor returns the Redactor for the options, or nil if redaction is not enabled. The
+// Redactor is refreshed (see refreshRedactor) when the options are applied and when credentials
+// are resolved, so it reflects the secrets at that time.
+func (o *Options) GetRedactor() *Redactor {
+	o.redactorOnce.Do(func() {
+		// a redactor that has been refreshed already is newer than anything built here
+		o.redactor.CompareAndSwap(nil, o.newRedactor())
+	})
+
+	return o.redactor.Load()
+}
+
+// refreshRedactor rebuilds the Redactor from the current secrets.
+func (o *Options) refreshRedactor() {
+	o.redactor.Store(o.newRedactor())
+}
+
+// redact redacts s with the current Redactor, unlike capturing the Redactor itself this picks up
+// secrets resolved after the fact (i.e. by credential providers when opening).
+func (o *Options) redact(s string) string {
+	return o.GetRedactor().Redact(s)
+}
+
+// GetRecorderCallback returns the session recorder callback, redacting what it is handed if
+// redaction is enabled.
+func (o *Options) GetRecorderCallback() func(s string) {
+	f := o.Session.RecorderCallback
+
+	if f == nil || !o.Redaction.Enabled {
+		return f
+	}
+
+	return func(s string) {
+		f(o.redact(s))
+	}
+}
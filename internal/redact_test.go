@@ -0,0 +1,181 @@
+package internal_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	scrapligocredentials "github.com/scrapli/scrapligo/v2/credentials"
+	scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
+	scrapligologging "github.com/scrapli/scrapligo/v2/logging"
+)
+
+func TestRedactorRedact(t *testing.T) {
+	o := scrapligointernal.NewOptions()
+
+	o.Redaction.Enabled = true
+	o.Redaction.Patterns = []*regexp.Regexp{regexp.MustCompile(`(token: )\w+`)}
+	o.Auth.Password = "hunter2"
+	o.Auth.LookupMap["enable"] = "hunter2-enable"
+
+	redactor := o.GetRedactor()
+
+	cases := map[string]struct {
+		in       string
+		expected string
+	}{
+		"password": {
+			in:       "sending hunter2",
+			expected: "sending <redacted>",
+		},
+		"lookup-containing-password": {
+			in:       "sending hunter2-enable",
+			expected: "sending <redacted>",
+		},
+		"cisco-secret": {
+			in:       "username admin privilege 15 secret 9 $9$abc$def\n",
+			expected: "username admin privilege 15 secret 9 <redacted>\n",
+		},
+		"cisco-enable-password": {
+			in:       "enable password 7 0822455D0A16",
+			expected: "enable password 7 <redacted>",
+		},
+		"cisco-key-string": {
+			in:       " key-string 7 045802150C2E",
+			expected: " key-string 7 <redacted>",
+		},
+		"community-untouched": {
+			in:       "snmp-server community snmp ro",
+			expected: "snmp-server community snmp ro",
+		},
+		"key-untouched": {
+			in:       "crypto key generate rsa modulus 2048",
+			expected: "crypto key generate rsa modulus 2048",
+		},
+		"community-key-value": {
+			in:       "/snmp community set public community=abc123",
+			expected: "/snmp community set public community=<redacted>",
+		},
+		"key-key-value": {
+			in:       "psk key: abc123",
+			expected: "psk key: <redacted>",
+		},
+		"junos-encrypted-password": {
+			in:       `encrypted-password "$6$abc$def"; ## SECRET-DATA`,
+			expected: `encrypted-password <redacted>; ## SECRET-DATA`,
+		},
+		"sros-hash2": {
+			in:       `password "abc123" hash2`,
+			expected: `password <redacted> hash2`,
+		},
+		"huawei-cipher": {
+			in:       "local-user admin password irreversible-cipher $1a$abc$",
+			expected: "local-user admin password irreversible-cipher <redacted>",
+		},
+		"fortinet-enc": {
+			in:       "set password ENC SH2abc",
+			expected: "set password ENC <redacted>",
+		},
+		"mikrotik-password": {
+			in:       "/user add name=admin password=abc123",
+			expected: "/user add name=admin password=<redacted>",
+		},
+		"user-pattern": {
+			in:       "token: abc123",
+			expected: "token: <redacted>",
+		},
+		"prompt-untouched": {
+			in:       "Password:",
+			expected: "Password:",
+		},
+	}
+
+	for caseName, caseData := range cases {
+		t.Run(caseName, func(t *testing.T) {
+			actual := redactor.Redact(caseData.in)
+			if actual != caseData.expected {
+				t.Fatalf("expected %q, got %q", caseData.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRedactorDisabled(t *testing.T) {
+	o := scrapligointernal.NewOptions()
+
+	o.Auth.Password = "hunter2"
+
+	if o.GetRedactor().Redact("hunter2") != "hunter2" {
+		t.Fatal("expected nothing to be redacted with redaction disabled")
+	}
+}
+
+func TestRedactorCallbacks(t *testing.T) {
+	o := scrapligointernal.NewOptions()
+
+	var recorded, logged string
+
+	o.Redaction.Enabled = true
+	o.Auth.PrivateKeyPassphrase = "hunter2"
+	o.Session.RecorderCallback = func(s string) { recorded = s }
+	o.Logger = func(_ scrapligologging.LogLevel, s string) { logged = s }
+
+	o.GetRecorderCallback()("passphrase hunter2")
+	o.GetLogger().Critical("passphrase hunter2")
+
+	if recorded != "passphrase <redacted>" {
+		t.Fatalf("expected redacted recording, got %q", recorded)
+	}
+
+	if logged != "passphrase <redacted>" {
+		t.Fatalf("expected redacted log message, got %q", logged)
+	}
+}
+
+func TestRedactorNoBuiltinPatterns(t *testing.T) {
+	o := scrapligointernal.NewOptions()
+
+	o.Redaction.Enabled = true
+	o.Redaction.NoBuiltinPatterns = true
+	o.Auth.Password = "hunter2"
+
+	actual := o.GetRedactor().Redact("enable password 7 0822455D0A16 hunter2")
+	if actual != "enable password 7 0822455D0A16 <redacted>" {
+		t.Fatalf("expected only the configured secret to be redacted, got %q", actual)
+	}
+}
+
+func TestRedactorResolvedCredentials(t *testing.T) {
+	o := scrapligointernal.NewOptions()
+
+	var logged string
+
+	o.Redaction.Enabled = true
+	o.Logger = func(_ scrapligologging.LogLevel, s string) { logged = s }
+	o.Auth.CredentialProviders = []scrapligocredentials.Provider{
+		scrapligocredentials.NewStaticProvider(&scrapligocredentials.Credentials{
+			Password: "hunter2",
+		}),
+	}
+
+	// the logger is created (as drivers do) before the credentials are resolved
+	l := o.GetLogger()
+
+	_, err := scrapligointernal.OpenWithCredentials(
+		context.Background(),
+		o,
+		"localhost",
+		func(_ context.Context) (struct{}, error) {
+			l.Critical("sending hunter2")
+
+			return struct{}{}, nil
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if logged != "sending <redacted>" {
+		t.Fatalf("expected resolved password to be redacted, got %q", logged)
+	}
+}
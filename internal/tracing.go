@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"context"
+	"time"
+
+	scrapligotracing "github.com/scrapli/scrapligo/v2/tracing"
+)
+
+const redactedInput = "<redacted>"
+
+// Span attribute keys used for all driver (cli and netconf) spans.
+const (
+	TraceAttributeHost             = scrapligotracing.Key("server.address")
+	TraceAttributePort             = scrapligotracing.Key("server.port")
+	TraceAttributeTransport        = scrapligotracing.Key("scrapligo.transport")
+	TraceAttributePlatform         = scrapligotracing.Key("scrapligo.platform")
+	TraceAttributeMode             = scrapligotracing.Key("scrapligo.mode")
+	TraceAttributeInput            = scrapligotracing.Key("scrapligo.input")
+	TraceAttributeInputBytes       = scrapligotracing.Key("scrapligo.input.bytes")
+	TraceAttributeResultBytes      = scrapligotracing.Key("scrapligo.result.bytes")
+	TraceAttributeFailed           = scrapligotracing.Key("scrapligo.failed")
+	TraceAttributeFailedIndicator  = scrapligotracing.Key("scrapligo.failed_indicator")
+	TraceAttributeOperationID      = scrapligotracing.Key("scrapligo.operation.id")
+	TraceAttributeNetconfRPC       = scrapligotracing.Key("scrapligo.netconf.rpc")
+	TraceAttributeNetconfErrorTags = scrapligotracing.Key("scrapligo.netconf.rpc_error.tags")
+)
+
+// TracingOptions holds the tracing options for drivers, drivers emit no spans unless a Tracer is
+// set.
+type TracingOptions struct {
+	Tracer       scrapligotracing.Tracer
+	RedactInputs bool
+}
+
+// String returns the name of the transport kind as used in span attributes.
+func (k TransportKind) String() string {
+	switch k {
+	case TransportKindBin:
+		return "bin"
+	case TransportKindTelnet:
+		return "telnet"
+	case TransportKindSSH2:
+		return "ssh2"
+	case TransportKindTest:
+		return "test"
+	default:
+		return "unknown"
+	}
+}
+
+// noopSpan is the span of drivers without a Tracer.
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(_ ...scrapligotracing.Attribute) {}
+
+func (noopSpan) End(_ error) {}
+
+// StartSpan starts a span (as a child of whatever span is in ctx) for the operation against host
+// with the attributes common to all driver spans plus the given attributes. If no Tracer is set
+// the span is a no-op and ctx is returned as is.
+func (o *Options) StartSpan(
+	ctx context.Context,
+	name,
+	host string,
+	start time.Time,
+	attrs ...scrapligotracing.Attribute,
+) (context.Context, scrapligotracing.Span) { //nolint: ireturn
+	if o.Tracing.Tracer == nil {
+		return ctx, noopSpan{}
+	}
+
+	return o.Tracing.Tracer.Start(
+		ctx,
+		name,
+		start,
+		append(
+			[]scrapligotracing.Attribute{
+				TraceAttributeHost.String(host),
+				TraceAttributePort.Int(int(o.Port)),
+				TraceAttributeTransport.String(o.TransportKind.String()),
+			},
+			attrs...,
+		)...,
+	)
+}
+
+// TraceInput returns the span attribute for the input(s) of an operation, honoring the input
+// redaction option. The input byte count is always recorded as is.
+func (o *Options) TraceInput(input string) []scrapligotracing.Attribute {
+	inputBytes := len(input)
+
+	if o.Tracing.RedactInputs {
+		input = redactedInput
+	}
+
+	return []scrapligotracing.Attribute{
+		TraceAttributeInput.String(input),
+		TraceAttributeInputBytes.Int(inputBytes),
+	}
+}
@@ -62,6 +62,22 @@ func (l *AnyLogger) Critical(s string) {
 	l.critical(s)
 }
 
+// Redacting returns a copy of the logger that passes messages through redact before logging them.
+func (l *AnyLogger) Redacting(redact func(s string) string) *AnyLogger {
+	wrap := func(f func(s string)) func(s string) {
+		return func(s string) { f(redact(s)) }
+	}
+
+	return &AnyLogger{
+		level:    l.level,
+		trace:    wrap(l.trace),
+		debug:    wrap(l.debug),
+		info:     wrap(l.info),
+		warn:     wrap(l.warn),
+		critical: wrap(l.critical),
+	}
+}
+
 func noopLoggerFunc(string) {}
 
 // LoggerToAnyLogger wraps any of the supported logger flavors in an AnyLogger so the Cli/Netconf
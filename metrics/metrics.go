@@ -0,0 +1,56 @@
+package metrics
+
+import "time"
+
+// DriverKind is an enum(ish) representing the kind of driver reporting metrics.
+type DriverKind string
+
+const (
+	// Cli represents metrics reported by a cli.Cli driver.
+	Cli DriverKind = "cli"
+	// Netconf represents metrics reported by a netconf.Netconf driver.
+	Netconf DriverKind = "netconf"
+)
+
+// Operation names used for opening and closing drivers, all other operations are named for the
+// cli operation (i.e. "send-input") or the netconf rpc (i.e. "get-config").
+const (
+	OperationOpen  = "open"
+	OperationClose = "close"
+)
+
+// Operation holds the statistics of a single (completed) driver operation.
+type Operation struct {
+	Driver DriverKind
+	Host   string
+	Port   uint16
+	// Platform is the definition platform of a cli.Cli driver, empty for netconf.
+	Platform string
+	// Name is the name of the operation, see OperationOpen/OperationClose.
+	Name string
+	// Duration is the duration of the operation as reported in the operation Result (start to end
+	// time) or, for operations that errored without a Result, as measured by the driver.
+	Duration    time.Duration
+	InputBytes  int
+	ResultBytes int
+	// Failed is true if the operation completed but a failed when indicator was found (cli) or
+	// the server replied with rpc-errors (netconf).
+	Failed bool
+	// RPCErrorTags holds the error-tag of each rpc-error the server replied with (netconf).
+	RPCErrorTags []string
+	// Err is the error the operation returned, if any.
+	Err error
+}
+
+// Recorder is the interface drivers report their metrics to, see the prometheus subpackage for a
+// prometheus client backed implementation. Recorder methods are called synchronously as
+// operations complete so implementations should not block.
+type Recorder interface {
+	// ObserveOperation is called once per completed (or errored) operation, including Open and
+	// Close.
+	ObserveOperation(op *Operation)
+	// SessionOpened is called when a driver is successfully opened.
+	SessionOpened(driver DriverKind, host string)
+	// SessionClosed is called when a previously opened driver is closed.
+	SessionClosed(driver DriverKind, host string)
+}
@@ -0,0 +1,174 @@
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	scrapligometrics "github.com/scrapli/scrapligo/v2/metrics"
+)
+
+const namespace = "scrapligo"
+
+const (
+	labelDriver    = "driver"
+	labelHost      = "host"
+	labelOperation = "operation"
+	labelResult    = "result"
+	labelTag       = "tag"
+
+	resultSuccess = "success"
+	resultFailed  = "failed"
+	resultError   = "error"
+)
+
+var _ scrapligometrics.Recorder = (*Recorder)(nil)
+
+// Recorder is a scrapligometrics.Recorder that records driver metrics as prometheus metrics.
+type Recorder struct {
+	openDuration      *prometheus.HistogramVec
+	opens             *prometheus.CounterVec
+	closes            *prometheus.CounterVec
+	operationDuration *prometheus.HistogramVec
+	operations        *prometheus.CounterVec
+	failures          *prometheus.CounterVec
+	rpcErrors         *prometheus.CounterVec
+	bytesSent         *prometheus.CounterVec
+	bytesReceived     *prometheus.CounterVec
+	activeSessions    *prometheus.GaugeVec
+}
+
+// NewRecorder returns a new Recorder with its metrics registered with registerer, if registerer
+// is nil the metrics are registered with the default prometheus registerer.
+func NewRecorder(registerer prometheus.Registerer) (*Recorder, error) {
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+
+	driverHostLabels := []string{labelDriver, labelHost}
+	operationLabels := []string{labelDriver, labelHost, labelOperation}
+
+	r := &Recorder{
+		openDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "open_duration_seconds",
+			Help:      "Duration of opening drivers (connecting, authenticating, on open).",
+			Buckets:   prometheus.ExponentialBuckets(0.1, 2, 10), //nolint: mnd
+		}, driverHostLabels),
+		opens: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "opens_total",
+			Help:      "Count of driver opens by result (success or error).",
+		}, []string{labelDriver, labelHost, labelResult}),
+		closes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "closes_total",
+			Help:      "Count of driver closes by result (success or error).",
+		}, []string{labelDriver, labelHost, labelResult}),
+		operationDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "operation_duration_seconds",
+			Help:      "Duration of driver operations (cli operations and netconf rpcs).",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 14), //nolint: mnd
+		}, operationLabels),
+		operations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "operations_total",
+			Help:      "Count of driver operations by result (success, failed or error).",
+		}, []string{labelDriver, labelHost, labelOperation, labelResult}),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "operation_failures_total",
+			Help:      "Count of operations with failed when indicator hits or rpc-errors.",
+		}, operationLabels),
+		rpcErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "netconf_rpc_errors_total",
+			Help:      "Count of netconf rpc-errors by error-tag.",
+		}, []string{labelHost, labelOperation, labelTag}),
+		bytesSent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "input_bytes_total",
+			Help:      "Count of bytes of operation inputs sent to devices.",
+		}, driverHostLabels),
+		bytesReceived: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "result_bytes_total",
+			Help:      "Count of bytes of (raw) operation results received from devices.",
+		}, driverHostLabels),
+		activeSessions: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "active_sessions",
+			Help:      "Count of currently open drivers.",
+		}, driverHostLabels),
+	}
+
+	for _, c := range []prometheus.Collector{
+		r.openDuration,
+		r.opens,
+		r.closes,
+		r.operationDuration,
+		r.operations,
+		r.failures,
+		r.rpcErrors,
+		r.bytesSent,
+		r.bytesReceived,
+		r.activeSessions,
+	} {
+		err := registerer.Register(c)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+func operationResult(op *scrapligometrics.Operation) string {
+	switch {
+	case op.Err != nil:
+		return resultError
+	case op.Failed:
+		return resultFailed
+	default:
+		return resultSuccess
+	}
+}
+
+// ObserveOperation records the statistics of a completed operation.
+func (r *Recorder) ObserveOperation(op *scrapligometrics.Operation) {
+	driver := string(op.Driver)
+	result := operationResult(op)
+
+	switch op.Name {
+	case scrapligometrics.OperationOpen:
+		r.opens.WithLabelValues(driver, op.Host, result).Inc()
+
+		if op.Err == nil {
+			r.openDuration.WithLabelValues(driver, op.Host).Observe(op.Duration.Seconds())
+		}
+	case scrapligometrics.OperationClose:
+		r.closes.WithLabelValues(driver, op.Host, result).Inc()
+	}
+
+	r.operations.WithLabelValues(driver, op.Host, op.Name, result).Inc()
+	r.operationDuration.WithLabelValues(driver, op.Host, op.Name).Observe(op.Duration.Seconds())
+
+	if op.Failed {
+		r.failures.WithLabelValues(driver, op.Host, op.Name).Inc()
+	}
+
+	for _, tag := range op.RPCErrorTags {
+		r.rpcErrors.WithLabelValues(op.Host, op.Name, tag).Inc()
+	}
+
+	r.bytesSent.WithLabelValues(driver, op.Host).Add(float64(op.InputBytes))
+	r.bytesReceived.WithLabelValues(driver, op.Host).Add(float64(op.ResultBytes))
+}
+
+// SessionOpened increments the active sessions of the driver kind and host.
+func (r *Recorder) SessionOpened(driver scrapligometrics.DriverKind, host string) {
+	r.activeSessions.WithLabelValues(string(driver), host).Inc()
+}
+
+// SessionClosed decrements the active sessions of the driver kind and host.
+func (r *Recorder) SessionClosed(driver scrapligometrics.DriverKind, host string) {
+	r.activeSessions.WithLabelValues(string(driver), host).Dec()
+}
@@ -0,0 +1,101 @@
+package prometheus_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	scrapligometrics "github.com/scrapli/scrapligo/v2/metrics"
+	scrapligometricsprometheus "github.com/scrapli/scrapligo/v2/metrics/prometheus"
+)
+
+const expectedMetrics = `
+# HELP scrapligo_active_sessions Count of currently open drivers.
+# TYPE scrapligo_active_sessions gauge
+scrapligo_active_sessions{driver="netconf",host="router1"} 1
+# HELP scrapligo_netconf_rpc_errors_total Count of netconf rpc-errors by error-tag.
+# TYPE scrapligo_netconf_rpc_errors_total counter
+scrapligo_netconf_rpc_errors_total{host="router1",operation="edit-config",tag="invalid-value"} 1
+scrapligo_netconf_rpc_errors_total{host="router1",operation="edit-config",tag="lock-denied"} 1
+# HELP scrapligo_opens_total Count of driver opens by result (success or error).
+# TYPE scrapligo_opens_total counter
+scrapligo_opens_total{driver="cli",host="switch1",result="error"} 1
+scrapligo_opens_total{driver="netconf",host="router1",result="success"} 1
+# HELP scrapligo_operation_failures_total Count of operations with failed when indicator hits or rpc-errors.
+# TYPE scrapligo_operation_failures_total counter
+scrapligo_operation_failures_total{driver="netconf",host="router1",operation="edit-config"} 1
+# HELP scrapligo_result_bytes_total Count of bytes of (raw) operation results received from devices.
+# TYPE scrapligo_result_bytes_total counter
+scrapligo_result_bytes_total{driver="cli",host="switch1"} 0
+scrapligo_result_bytes_total{driver="netconf",host="router1"} 20
+`
+
+func TestRecorder(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	r, err := scrapligometricsprometheus.NewRecorder(registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.ObserveOperation(&scrapligometrics.Operation{
+		Driver:   scrapligometrics.Netconf,
+		Host:     "router1",
+		Name:     scrapligometrics.OperationOpen,
+		Duration: time.Second,
+	})
+	r.SessionOpened(scrapligometrics.Netconf, "router1")
+
+	r.ObserveOperation(&scrapligometrics.Operation{
+		Driver:       scrapligometrics.Netconf,
+		Host:         "router1",
+		Name:         "edit-config",
+		Duration:     100 * time.Millisecond,
+		InputBytes:   10,
+		ResultBytes:  20,
+		Failed:       true,
+		RPCErrorTags: []string{"lock-denied", "invalid-value"},
+	})
+
+	r.ObserveOperation(&scrapligometrics.Operation{
+		Driver: scrapligometrics.Cli,
+		Host:   "switch1",
+		Name:   scrapligometrics.OperationOpen,
+		Err:    errors.New("connection refused"),
+	})
+
+	err = testutil.GatherAndCompare(
+		registry,
+		strings.NewReader(expectedMetrics),
+		"scrapligo_active_sessions",
+		"scrapligo_netconf_rpc_errors_total",
+		"scrapligo_opens_total",
+		"scrapligo_operation_failures_total",
+		"scrapligo_result_bytes_total",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// only successful opens count towards open latency
+	if testutil.CollectAndCount(registry, "scrapligo_open_duration_seconds") != 1 {
+		t.Fatal("expected open latency of only the successful open")
+	}
+}
+
+func TestNewRecorderAlreadyRegistered(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	_, err := scrapligometricsprometheus.NewRecorder(registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = scrapligometricsprometheus.NewRecorder(registry)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
 )
 
 // Action executes a netconf action rpc.
@@ -12,25 +13,42 @@ func (n *Netconf) Action(
 	action string,
 	options ...Option,
 ) (*Result, error) {
+	op, err := n.ActionAsync(ctx, action, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return op.Wait(ctx)
+}
+
+// ActionAsync is the async flavor of Action -- the rpc is submitted and an OperationHandle is
+// returned immediately, see Action for supported options.
+func (n *Netconf) ActionAsync(
+	ctx context.Context,
+	action string,
+	options ...Option,
+) (*OperationHandle, error) {
 	_ = options
 
 	if n.ptr == 0 {
 		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
 	}
 
-	cancel := false
-
-	var operationID uint32
-
-	err := n.ffiMap.Netconf.Action(
-		n.ptr,
-		&operationID,
-		&cancel,
-		action,
+	op, err := n.submit(
+		ctx,
+		"action",
+		func(m *scrapligoffi.NetconfMapping, id *uint32, cancel *scrapligoffi.CancelFlag) error {
+			return m.Action(
+				n.ptr,
+				id,
+				cancel,
+				action,
+			)
+		},
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	return n.getResult(ctx, &cancel, operationID)
+	return op, nil
 }
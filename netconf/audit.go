@@ -0,0 +1,55 @@
+package netconf
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	scrapligoaudit "github.com/scrapli/scrapligo/v2/audit"
+)
+
+// audit writes the audit record of the rpc to the audit sink, if any.
+func (n *Netconf) audit(
+	ctx context.Context,
+	start time.Time,
+	rpc *RPC,
+	result *Result,
+	err error,
+) {
+	if n.options.Audit == nil {
+		return
+	}
+
+	record := &scrapligoaudit.Record{
+		Time:           start,
+		Operator:       scrapligoaudit.Operator(ctx),
+		Driver:         "netconf",
+		Host:           rpc.Host,
+		Port:           rpc.Port,
+		Operation:      rpc.Name,
+		Payload:        rpc.Payload,
+		ElapsedSeconds: time.Since(start).Seconds(),
+		DryRun:         n.dryRuns(rpc.Name),
+	}
+
+	if result != nil {
+		record.Outputs = []string{result.Result}
+		record.Failed = result.Failed
+
+		for _, rpcError := range result.Errors {
+			if rpcError != "" {
+				record.RPCErrors = append(record.RPCErrors, rpcError)
+			}
+		}
+	}
+
+	if err != nil {
+		record.Failed = true
+		record.Error = err.Error()
+	}
+
+	auditErr := n.options.Audit.Write(record)
+	if auditErr != nil {
+		n.l.Warn(fmt.Sprintf("failed writing audit record: %v", auditErr))
+	}
+}
@@ -0,0 +1,68 @@
+package netconf_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	scrapligoaudit "github.com/scrapli/scrapligo/v2/audit"
+	scrapligooptions "github.com/scrapli/scrapligo/v2/options"
+)
+
+func TestAudit(t *testing.T) {
+	testFixturePath, err := filepath.Abs("./fixtures/lock-simple")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	var b bytes.Buffer
+
+	n := getNetconf(
+		t,
+		testFixturePath,
+		scrapligooptions.WithAuditSink(scrapligoaudit.NewSink(&b)),
+	)
+
+	_, err = n.Open(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_, _ = n.Close(ctx)
+	}()
+
+	r, err := n.Lock(scrapligoaudit.WithOperator(ctx, "carl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = n.Unlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 audit records, got %d", len(lines))
+	}
+
+	var record scrapligoaudit.Record
+
+	err = json.Unmarshal([]byte(lines[0]), &record)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if record.Operator != "carl" || record.Operation != "lock" ||
+		!strings.Contains(record.Payload, "<lock>") || record.Outputs[0] != r.Result {
+		t.Fatalf("unexpected record %+v", record)
+	}
+}
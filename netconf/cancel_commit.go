@@ -4,6 +4,7 @@ import (
 	"context"
 
 	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
 )
 
 func newCancelCommitOptions(options ...Option) *cancelCommitOptions {
@@ -25,27 +26,48 @@ func (n *Netconf) CancelCommit(
 	ctx context.Context,
 	options ...Option,
 ) (*Result, error) {
+	op, err := n.CancelCommitAsync(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return op.Wait(ctx)
+}
+
+// CancelCommitAsync is the async flavor of CancelCommit -- the rpc is submitted and an
+// OperationHandle is returned immediately, see CancelCommit for supported options.
+func (n *Netconf) CancelCommitAsync(
+	ctx context.Context,
+	options ...Option,
+) (*OperationHandle, error) {
 	_ = options
 
 	if n.ptr == 0 {
 		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
 	}
 
-	cancel := false
-
-	var operationID uint32
-
 	loadedOptions := newCancelCommitOptions(options...)
 
-	err := n.ffiMap.Netconf.CancelCommit(
-		n.ptr,
-		&operationID,
-		&cancel,
-		loadedOptions.persistID,
+	err := n.requireCapability("cancel-commit", CapabilityConfirmedCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := n.submit(
+		ctx,
+		"cancel-commit",
+		func(m *scrapligoffi.NetconfMapping, id *uint32, cancel *scrapligoffi.CancelFlag) error {
+			return m.CancelCommit(
+				n.ptr,
+				id,
+				cancel,
+				loadedOptions.persistID,
+			)
+		},
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	return n.getResult(ctx, &cancel, operationID)
+	return op, nil
 }
@@ -0,0 +1,413 @@
+package netconf
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
+)
+
+const (
+	// CapabilityBase10 is the netconf base 1.0 capability.
+	CapabilityBase10 = "urn:ietf:params:netconf:base:1.0"
+	// CapabilityBase11 is the netconf base 1.1 capability.
+	CapabilityBase11 = "urn:ietf:params:netconf:base:1.1"
+	// CapabilityWritableRunning is the :writable-running capability.
+	CapabilityWritableRunning = "urn:ietf:params:netconf:capability:writable-running"
+	// CapabilityCandidate is the :candidate capability.
+	CapabilityCandidate = "urn:ietf:params:netconf:capability:candidate"
+	// CapabilityConfirmedCommit is the :confirmed-commit capability.
+	CapabilityConfirmedCommit = "urn:ietf:params:netconf:capability:confirmed-commit"
+	// CapabilityRollbackOnError is the :rollback-on-error capability.
+	CapabilityRollbackOnError = "urn:ietf:params:netconf:capability:rollback-on-error"
+	// CapabilityValidate is the :validate capability.
+	CapabilityValidate = "urn:ietf:params:netconf:capability:validate"
+	// CapabilityStartup is the :startup capability.
+	CapabilityStartup = "urn:ietf:params:netconf:capability:startup"
+	// CapabilityURL is the :url capability.
+	CapabilityURL = "urn:ietf:params:netconf:capability:url"
+	// CapabilityXpath is the :xpath capability.
+	CapabilityXpath = "urn:ietf:params:netconf:capability:xpath"
+	// CapabilityNotification is the :notification capability.
+	CapabilityNotification = "urn:ietf:params:netconf:capability:notification"
+	// CapabilityInterleave is the :interleave capability.
+	CapabilityInterleave = "urn:ietf:params:netconf:capability:interleave"
+	// CapabilityWithDefaults is the :with-defaults capability.
+	CapabilityWithDefaults = "urn:ietf:params:netconf:capability:with-defaults"
+	// CapabilityYangLibrary is the :yang-library capability.
+	CapabilityYangLibrary = "urn:ietf:params:netconf:capability:yang-library"
+
+	// ModuleNetconfNmda is the name of the yang module that (when advertised) indicates the server
+	// supports the nmda get-data/edit-data rpcs.
+	ModuleNetconfNmda = "ietf-netconf-nmda"
+)
+
+var (
+	helloStartTag = []byte("<hello")   //nolint: gochecknoglobals
+	helloEndTag   = []byte("</hello>") //nolint: gochecknoglobals
+)
+
+// YangModule represents a yang module advertised in the servers capabilities.
+type YangModule struct {
+	Namespace  string
+	Name       string
+	Revision   string
+	Features   []string
+	Deviations []string
+}
+
+// WithDefaultsCapability holds the modes advertised with the :with-defaults capability.
+type WithDefaultsCapability struct {
+	BasicMode     DefaultsType
+	AlsoSupported []DefaultsType
+}
+
+// Supports returns true if the given defaults type is either the basic mode or one of the also
+// supported modes.
+func (c *WithDefaultsCapability) Supports(t DefaultsType) bool {
+	return c.BasicMode == t || slices.Contains(c.AlsoSupported, t)
+}
+
+// Capabilities holds the parsed capabilities a server advertised in its hello message.
+type Capabilities struct {
+	// Raw is the capabilities exactly as advertised (whitespace trimmed).
+	Raw []string
+	// BaseVersions holds the advertised base protocol versions, i.e. "1.0" and/or "1.1".
+	BaseVersions []string
+	// WithDefaults is non nil if the server advertised the :with-defaults capability.
+	WithDefaults *WithDefaultsCapability
+	// Modules holds yang modules advertised in the hello, note that servers supporting yang
+	// library 1.1 (:yang-library:1.1) do not advertise modules in their hello message.
+	Modules []*YangModule
+
+	// uris holds the capability uris without any query parameters, mapped to the parameters.
+	uris map[string]url.Values
+}
+
+// ParseCapabilities parses the given capability strings into a Capabilities object.
+func ParseCapabilities(capabilities []string) *Capabilities {
+	c := &Capabilities{
+		Raw:  make([]string, 0, len(capabilities)),
+		uris: make(map[string]url.Values, len(capabilities)),
+	}
+
+	for _, capability := range capabilities {
+		capability = strings.TrimSpace(capability)
+		if capability == "" {
+			continue
+		}
+
+		c.Raw = append(c.Raw, capability)
+
+		uri, query, _ := strings.Cut(capability, "?")
+
+		params, err := url.ParseQuery(query)
+		if err != nil {
+			params = url.Values{}
+		}
+
+		c.uris[uri] = params
+
+		switch {
+		case uri == CapabilityBase10:
+			c.BaseVersions = append(c.BaseVersions, "1.0")
+		case uri == CapabilityBase11:
+			c.BaseVersions = append(c.BaseVersions, "1.1")
+		case capabilityMatches(uri, CapabilityWithDefaults):
+			c.WithDefaults = parseWithDefaultsCapability(params)
+		case params.Has("module"):
+			c.Modules = append(c.Modules, &YangModule{
+				Namespace:  uri,
+				Name:       params.Get("module"),
+				Revision:   params.Get("revision"),
+				Features:   splitCapabilityParam(params.Get("features")),
+				Deviations: splitCapabilityParam(params.Get("deviations")),
+			})
+		}
+	}
+
+	return c
+}
+
+func splitCapabilityParam(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(s, ",")
+}
+
+func defaultsTypeFromString(s string) (DefaultsType, bool) {
+	switch s {
+	case "report-all":
+		return DefaultsTypeReportAll, true
+	case "report-all-tagged":
+		return DefaultsTypeReportAllTagged, true
+	case "trim":
+		return DefaultsTypeTrim, true
+	case "explicit":
+		return DefaultsTypeExplicit, true
+	default:
+		return 0, false
+	}
+}
+
+func defaultsTypeToString(t DefaultsType) string {
+	switch t {
+	case DefaultsTypeReportAll:
+		return "report-all"
+	case DefaultsTypeReportAllTagged:
+		return "report-all-tagged"
+	case DefaultsTypeTrim:
+		return "trim"
+	case DefaultsTypeExplicit:
+		return "explicit"
+	default:
+		return "unknown"
+	}
+}
+
+func parseWithDefaultsCapability(params url.Values) *WithDefaultsCapability {
+	c := &WithDefaultsCapability{}
+
+	basicMode, ok := defaultsTypeFromString(params.Get("basic-mode"))
+	if ok {
+		c.BasicMode = basicMode
+	}
+
+	for _, mode := range splitCapabilityParam(params.Get("also-supported")) {
+		t, ok := defaultsTypeFromString(mode)
+		if !ok {
+			continue
+		}
+
+		c.AlsoSupported = append(c.AlsoSupported, t)
+	}
+
+	return c
+}
+
+// capabilityMatches returns true if the uri is the capability, or is the capability with a version
+// suffix (i.e. "...:candidate:1.0" matches "...:candidate").
+func capabilityMatches(uri, capability string) bool {
+	return uri == capability || strings.HasPrefix(uri, capability+":")
+}
+
+// HasCapability returns true if the server advertised the given capability. The capability may
+// be provided with or without a version suffix -- "...:candidate" matches any advertised version
+// while "...:candidate:1.0" only matches that exact version. Query parameters are ignored.
+func (c *Capabilities) HasCapability(capability string) bool {
+	capability, _, _ = strings.Cut(capability, "?")
+
+	for uri := range c.uris {
+		if capabilityMatches(uri, capability) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasBaseVersion returns true if the server advertised the given base version ("1.0" or "1.1").
+func (c *Capabilities) HasBaseVersion(version string) bool {
+	return slices.Contains(c.BaseVersions, version)
+}
+
+// HasCandidate returns true if the server advertised the :candidate capability.
+func (c *Capabilities) HasCandidate() bool {
+	return c.HasCapability(CapabilityCandidate)
+}
+
+// HasWritableRunning returns true if the server advertised the :writable-running capability.
+func (c *Capabilities) HasWritableRunning() bool {
+	return c.HasCapability(CapabilityWritableRunning)
+}
+
+// HasValidate returns true if the server advertised the :validate capability.
+func (c *Capabilities) HasValidate() bool {
+	return c.HasCapability(CapabilityValidate)
+}
+
+// HasConfirmedCommit returns true if the server advertised the :confirmed-commit capability.
+func (c *Capabilities) HasConfirmedCommit() bool {
+	return c.HasCapability(CapabilityConfirmedCommit)
+}
+
+// HasRollbackOnError returns true if the server advertised the :rollback-on-error capability.
+func (c *Capabilities) HasRollbackOnError() bool {
+	return c.HasCapability(CapabilityRollbackOnError)
+}
+
+// HasStartup returns true if the server advertised the :startup capability.
+func (c *Capabilities) HasStartup() bool {
+	return c.HasCapability(CapabilityStartup)
+}
+
+// HasWithDefaults returns true if the server advertised the :with-defaults capability and the
+// given defaults type is one of the advertised modes.
+func (c *Capabilities) HasWithDefaults(t DefaultsType) bool {
+	return c.WithDefaults != nil && c.WithDefaults.Supports(t)
+}
+
+// HasNmda returns true if the server indicates nmda support -- either by advertising the
+// ietf-netconf-nmda module directly, or by advertising yang library 1.1 (which nmda servers must
+// support, and which means modules are not listed in the hello at all).
+func (c *Capabilities) HasNmda() bool {
+	return c.GetModule(ModuleNetconfNmda) != nil || c.HasCapability(CapabilityYangLibrary+":1.1")
+}
+
+// GetModule returns the advertised module with the given name, or nil if it was not advertised.
+func (c *Capabilities) GetModule(name string) *YangModule {
+	for _, module := range c.Modules {
+		if module.Name == name {
+			return module
+		}
+	}
+
+	return nil
+}
+
+// parseServerHello extracts the capability strings from the first server hello message found in
+// b -- b may contain other content (auth prompts, framing delimiters etc.), we only care about the
+// hello element.
+func parseServerHello(b []byte) []string {
+	startIdx := bytes.Index(b, helloStartTag)
+	if startIdx < 0 {
+		return nil
+	}
+
+	endIdx := bytes.Index(b[startIdx:], helloEndTag)
+	if endIdx < 0 {
+		return nil
+	}
+
+	hello := &scrapligointernal.NetconfServerHello{}
+
+	err := xml.Unmarshal(b[startIdx:startIdx+endIdx+len(helloEndTag)], hello)
+	if err != nil {
+		return nil
+	}
+
+	return hello.Capabilities.Capability
+}
+
+func (n *Netconf) setServerCapabilities(capabilities []string) {
+	if len(capabilities) == 0 {
+		return
+	}
+
+	n.capabilitiesLock.Lock()
+	defer n.capabilitiesLock.Unlock()
+
+	n.capabilities = ParseCapabilities(capabilities)
+}
+
+func (n *Netconf) getServerCapabilities() *Capabilities {
+	n.capabilitiesLock.Lock()
+	defer n.capabilitiesLock.Unlock()
+
+	return n.capabilities
+}
+
+// GetServerCapabilities returns the parsed capabilities the server advertised during the
+// capabilities exchange. This returns an error if the Netconf object has not been opened or the
+// server capabilities could not be determined.
+func (n *Netconf) GetServerCapabilities() (*Capabilities, error) {
+	capabilities := n.getServerCapabilities()
+	if capabilities == nil {
+		return nil, scrapligoerrors.NewNetconfError("server capabilities unavailable", nil)
+	}
+
+	return capabilities, nil
+}
+
+// HasCapability returns true if the server advertised the given capability, see
+// Capabilities.HasCapability for matching details. If the server capabilities are unavailable
+// this returns false.
+func (n *Netconf) HasCapability(capability string) bool {
+	capabilities := n.getServerCapabilities()
+	if capabilities == nil {
+		return false
+	}
+
+	return capabilities.HasCapability(capability)
+}
+
+func missingCapabilityError(operation, capability string) error {
+	return scrapligoerrors.NewNetconfError(
+		fmt.Sprintf(
+			"%s requires capability %q but the server did not advertise it",
+			operation,
+			capability,
+		),
+		scrapligoerrors.ErrMissingCapability,
+	)
+}
+
+// requireCapability returns an ErrMissingCapability flavored error if the server capabilities are
+// known and the given capability was not advertised. If the capabilities are unknown we let the
+// server be the judge of things.
+func (n *Netconf) requireCapability(operation, capability string) error {
+	capabilities := n.getServerCapabilities()
+	if capabilities == nil || capabilities.HasCapability(capability) {
+		return nil
+	}
+
+	return missingCapabilityError(operation, capability)
+}
+
+// requireDatastore checks that datastores which are only available with an optional capability
+// (candidate/startup) were advertised by the server.
+func (n *Netconf) requireDatastore(operation string, t *DatastoreType) error {
+	if t == nil {
+		return nil
+	}
+
+	switch *t { //nolint: exhaustive
+	case DatastoreTypeCandidate:
+		return n.requireCapability(operation, CapabilityCandidate)
+	case DatastoreTypeStartup:
+		return n.requireCapability(operation, CapabilityStartup)
+	default:
+		return nil
+	}
+}
+
+// requireWritableDatastore is like requireDatastore but also ensures the running datastore is
+// writable when it is the target of an operation.
+func (n *Netconf) requireWritableDatastore(operation string, t *DatastoreType) error {
+	if t != nil && *t == DatastoreTypeRunning {
+		return n.requireCapability(operation, CapabilityWritableRunning)
+	}
+
+	return n.requireDatastore(operation, t)
+}
+
+// requireDefaultsType checks that the server advertised the :with-defaults capability including
+// the requested mode.
+func (n *Netconf) requireDefaultsType(operation string, t *DefaultsType) error {
+	capabilities := n.getServerCapabilities()
+	if t == nil || capabilities == nil || capabilities.HasWithDefaults(*t) {
+		return nil
+	}
+
+	return missingCapabilityError(
+		fmt.Sprintf("%s with-defaults mode %q", operation, defaultsTypeToString(*t)),
+		CapabilityWithDefaults,
+	)
+}
+
+// requireNmda checks that the server supports nmda operations (get-data/edit-data).
+func (n *Netconf) requireNmda(operation string) error {
+	capabilities := n.getServerCapabilities()
+	if capabilities == nil || capabilities.HasNmda() {
+		return nil
+	}
+
+	return missingCapabilityError(operation, ModuleNetconfNmda)
+}
@@ -0,0 +1,105 @@
+package netconf_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	scrapligonetconf "github.com/scrapli/scrapligo/v2/netconf"
+)
+
+func TestParseCapabilities(t *testing.T) {
+	capabilities := scrapligonetconf.ParseCapabilities([]string{
+		"urn:ietf:params:netconf:base:1.0",
+		"urn:ietf:params:netconf:base:1.1",
+		"urn:ietf:params:netconf:capability:writable-running:1.0",
+		"urn:ietf:params:netconf:capability:confirmed-commit:1.1",
+		" urn:ietf:params:netconf:capability:with-defaults:1.0?basic-mode=explicit&also-supported=trim,report-all ",
+		"urn:ietf:params:xml:ns:yang:ietf-interfaces?module=ietf-interfaces&revision=2018-02-20&features=arbitrary-names,pre-provisioning&deviations=foo-deviations",
+	})
+
+	if !capabilities.HasBaseVersion("1.0") || !capabilities.HasBaseVersion("1.1") {
+		t.Fatalf("expected base versions 1.0 and 1.1, got %v", capabilities.BaseVersions)
+	}
+
+	if !capabilities.HasWritableRunning() || !capabilities.HasConfirmedCommit() {
+		t.Fatal("expected writable-running and confirmed-commit capabilities")
+	}
+
+	if capabilities.HasCandidate() || capabilities.HasValidate() {
+		t.Fatal("expected no candidate or validate capabilities")
+	}
+
+	if !capabilities.HasCapability(scrapligonetconf.CapabilityConfirmedCommit + ":1.1") {
+		t.Fatal("expected exact versioned capability match")
+	}
+
+	if capabilities.HasCapability(scrapligonetconf.CapabilityConfirmedCommit + ":1.0") {
+		t.Fatal("expected versioned capability mismatch")
+	}
+
+	if !capabilities.HasWithDefaults(scrapligonetconf.DefaultsTypeExplicit) ||
+		!capabilities.HasWithDefaults(scrapligonetconf.DefaultsTypeTrim) ||
+		capabilities.HasWithDefaults(scrapligonetconf.DefaultsTypeReportAllTagged) {
+		t.Fatalf("unexpected with-defaults modes %+v", capabilities.WithDefaults)
+	}
+
+	module := capabilities.GetModule("ietf-interfaces")
+	if module == nil {
+		t.Fatal("expected ietf-interfaces module")
+	}
+
+	if module.Revision != "2018-02-20" ||
+		!slices.Equal(module.Features, []string{"arbitrary-names", "pre-provisioning"}) ||
+		!slices.Equal(module.Deviations, []string{"foo-deviations"}) {
+		t.Fatalf("unexpected module %+v", module)
+	}
+
+	if capabilities.HasNmda() {
+		t.Fatal("expected no nmda support")
+	}
+}
+
+func TestGetServerCapabilities(t *testing.T) {
+	testName := "get-server-capabilities"
+
+	testFixturePath, err := filepath.Abs(fmt.Sprintf("./fixtures/%s", testName))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	n := getNetconf(t, testFixturePath)
+
+	_, err = n.GetServerCapabilities()
+	if err == nil {
+		t.Fatal("expected error fetching capabilities prior to open")
+	}
+
+	_, err = n.Open(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_, _ = n.Close(ctx)
+	}()
+
+	capabilities, err := n.GetServerCapabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !capabilities.HasBaseVersion("1.1") || !capabilities.HasCandidate() {
+		t.Fatal("expected base 1.1 and candidate capabilities")
+	}
+
+	if !n.HasCapability(scrapligonetconf.CapabilityValidate) {
+		t.Fatal("expected validate capability")
+	}
+}
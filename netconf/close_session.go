@@ -4,6 +4,7 @@ import (
 	"context"
 
 	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
 )
 
 // CloseSession executes a netconf close-session rpc.
@@ -11,24 +12,40 @@ func (n *Netconf) CloseSession(
 	ctx context.Context,
 	options ...Option,
 ) (*Result, error) {
+	op, err := n.CloseSessionAsync(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return op.Wait(ctx)
+}
+
+// CloseSessionAsync is the async flavor of CloseSession -- the rpc is submitted and an
+// OperationHandle is returned immediately, see CloseSession for supported options.
+func (n *Netconf) CloseSessionAsync(
+	ctx context.Context,
+	options ...Option,
+) (*OperationHandle, error) {
 	_ = options
 
 	if n.ptr == 0 {
 		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
 	}
 
-	cancel := false
-
-	var operationID uint32
-
-	err := n.ffiMap.Netconf.CloseSession(
-		n.ptr,
-		&operationID,
-		&cancel,
+	op, err := n.submit(
+		ctx,
+		"close-session",
+		func(m *scrapligoffi.NetconfMapping, id *uint32, cancel *scrapligoffi.CancelFlag) error {
+			return m.CloseSession(
+				n.ptr,
+				id,
+				cancel,
+			)
+		},
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	return n.getResult(ctx, &cancel, operationID)
+	return op, nil
 }
@@ -4,31 +4,54 @@ import (
 	"context"
 
 	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
 )
 
-// Commit executes a netconf commit rpc.
+// Commit executes a netconf commit rpc. Fails fast (without sending the rpc) if the server did not
+// advertise the :candidate capability.
 func (n *Netconf) Commit(
 	ctx context.Context,
 	options ...Option,
 ) (*Result, error) {
+	op, err := n.CommitAsync(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return op.Wait(ctx)
+}
+
+// CommitAsync is the async flavor of Commit -- the rpc is submitted and an OperationHandle is
+// returned immediately, see Commit for supported options.
+func (n *Netconf) CommitAsync(
+	ctx context.Context,
+	options ...Option,
+) (*OperationHandle, error) {
 	_ = options
 
 	if n.ptr == 0 {
 		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
 	}
 
-	cancel := false
-
-	var operationID uint32
+	err := n.requireCapability("commit", CapabilityCandidate)
+	if err != nil {
+		return nil, err
+	}
 
-	err := n.ffiMap.Netconf.Commit(
-		n.ptr,
-		&operationID,
-		&cancel,
+	op, err := n.submit(
+		ctx,
+		"commit",
+		func(m *scrapligoffi.NetconfMapping, id *uint32, cancel *scrapligoffi.CancelFlag) error {
+			return m.Commit(
+				n.ptr,
+				id,
+				cancel,
+			)
+		},
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	return n.getResult(ctx, &cancel, operationID)
+	return op, nil
 }
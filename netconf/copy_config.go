@@ -4,6 +4,7 @@ import (
 	"context"
 
 	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
 )
 
 func newCopyConfigOptions(options ...Option) *copyConfigOptions {
@@ -48,26 +49,52 @@ func (n *Netconf) CopyConfig(
 	ctx context.Context,
 	options ...Option,
 ) (*Result, error) {
+	op, err := n.CopyConfigAsync(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return op.Wait(ctx)
+}
+
+// CopyConfigAsync is the async flavor of CopyConfig -- the rpc is submitted and an OperationHandle
+// is returned immediately, see CopyConfig for supported options.
+func (n *Netconf) CopyConfigAsync(
+	ctx context.Context,
+	options ...Option,
+) (*OperationHandle, error) {
 	if n.ptr == 0 {
 		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
 	}
 
-	cancel := false
+	loadedOptions := newCopyConfigOptions(options...)
 
-	var operationID uint32
+	err := n.requireWritableDatastore("copy-config", loadedOptions.target)
+	if err != nil {
+		return nil, err
+	}
 
-	loadedOptions := newCopyConfigOptions(options...)
+	err = n.requireDatastore("copy-config", loadedOptions.source)
+	if err != nil {
+		return nil, err
+	}
 
-	err := n.ffiMap.Netconf.CopyConfig(
-		n.ptr,
-		&operationID,
-		&cancel,
-		loadedOptions.getTarget(),
-		loadedOptions.getSource(),
+	op, err := n.submit(
+		ctx,
+		"copy-config",
+		func(m *scrapligoffi.NetconfMapping, id *uint32, cancel *scrapligoffi.CancelFlag) error {
+			return m.CopyConfig(
+				n.ptr,
+				id,
+				cancel,
+				loadedOptions.getTarget(),
+				loadedOptions.getSource(),
+			)
+		},
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	return n.getResult(ctx, &cancel, operationID)
+	return op, nil
 }
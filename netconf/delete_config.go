@@ -4,6 +4,7 @@ import (
 	"context"
 
 	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
 )
 
 func newDeleteConfigOptions(options ...Option) *deleteConfigOptions {
@@ -36,25 +37,46 @@ func (n *Netconf) DeleteConfig(
 	ctx context.Context,
 	options ...Option,
 ) (*Result, error) {
-	if n.ptr == 0 {
-		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
+	op, err := n.DeleteConfigAsync(ctx, options...)
+	if err != nil {
+		return nil, err
 	}
 
-	cancel := false
+	return op.Wait(ctx)
+}
 
-	var operationID uint32
+// DeleteConfigAsync is the async flavor of DeleteConfig -- the rpc is submitted and an
+// OperationHandle is returned immediately, see DeleteConfig for supported options.
+func (n *Netconf) DeleteConfigAsync(
+	ctx context.Context,
+	options ...Option,
+) (*OperationHandle, error) {
+	if n.ptr == 0 {
+		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
+	}
 
 	loadedOptions := newDeleteConfigOptions(options...)
 
-	err := n.ffiMap.Netconf.DeleteConfig(
-		n.ptr,
-		&operationID,
-		&cancel,
-		loadedOptions.getTarget(),
+	err := n.requireDatastore("delete-config", loadedOptions.target)
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := n.submit(
+		ctx,
+		"delete-config",
+		func(m *scrapligoffi.NetconfMapping, id *uint32, cancel *scrapligoffi.CancelFlag) error {
+			return m.DeleteConfig(
+				n.ptr,
+				id,
+				cancel,
+				loadedOptions.getTarget(),
+			)
+		},
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	return n.getResult(ctx, &cancel, operationID)
+	return op, nil
 }
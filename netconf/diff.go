@@ -0,0 +1,434 @@
+package netconf
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+)
+
+// DiffChangeType is an enum(ish) representing the kind of change in a Diff.
+type DiffChangeType string
+
+const (
+	DiffChangeAdded    DiffChangeType = "added"
+	DiffChangeRemoved  DiffChangeType = "removed"
+	DiffChangeModified DiffChangeType = "modified"
+)
+
+// DiffChange is a single change between two datastores. Path is built from element names with
+// list entries identified by their keys (i.e. "/interfaces/interface[name='eth0']/enabled"). For
+// leafs Before/After hold the value, for other nodes they hold the xml of the (sub)tree.
+type DiffChange struct {
+	Type      DiffChangeType `json:"type"`
+	Path      string         `json:"path"`
+	Namespace string         `json:"namespace,omitempty"`
+	Before    string         `json:"before,omitempty"`
+	After     string         `json:"after,omitempty"`
+}
+
+// Diff holds the changes required to get from one datastore (or GetConfig result) to another.
+type Diff struct {
+	Changes []*DiffChange `json:"changes"`
+}
+
+// HasChanges returns true if the diff contains any changes.
+func (d *Diff) HasChanges() bool {
+	return len(d.Changes) > 0
+}
+
+// String returns a human friendly rendering of the diff, one change per line prefixed with "+"
+// (added), "-" (removed), or "~" (modified).
+func (d *Diff) String() string {
+	var b strings.Builder
+
+	for _, change := range d.Changes {
+		switch change.Type {
+		case DiffChangeAdded:
+			fmt.Fprintf(&b, "+ %s: %s\n", change.Path, change.After)
+		case DiffChangeRemoved:
+			fmt.Fprintf(&b, "- %s: %s\n", change.Path, change.Before)
+		case DiffChangeModified:
+			fmt.Fprintf(&b, "~ %s: %s -> %s\n", change.Path, change.Before, change.After)
+		}
+	}
+
+	return b.String()
+}
+
+// JSON returns the diff encoded as (indented) json.
+func (d *Diff) JSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+func newDiffOptions(options ...Option) *diffOptions {
+	o := &diffOptions{}
+
+	for _, opt := range options {
+		opt(o)
+	}
+
+	return o
+}
+
+type diffOptions struct {
+	listKeys map[string][]string
+}
+
+const (
+	// diffDefaultListKey is the key leaf assumed for lists with no configured keys -- it is by far
+	// the most common list key in the wild.
+	diffDefaultListKey = "name"
+)
+
+type differ struct {
+	listKeys map[string][]string
+	changes  []*DiffChange
+}
+
+func (d *differ) value(n *xmlNode) string {
+	if len(n.children) == 0 {
+		return strings.TrimSpace(n.directText.String())
+	}
+
+	b := &strings.Builder{}
+
+	xmlNodeToEditNode(n).render(b, "", false)
+
+	return b.String()
+}
+
+func (d *differ) record(changeType DiffChangeType, path string, before, after *xmlNode) {
+	change := &DiffChange{
+		Type: changeType,
+		Path: path,
+	}
+
+	if before != nil {
+		change.Namespace = before.name.Space
+		change.Before = d.value(before)
+	}
+
+	if after != nil {
+		change.Namespace = after.name.Space
+		change.After = d.value(after)
+	}
+
+	d.changes = append(d.changes, change)
+}
+
+func xmlNodeToEditNode(n *xmlNode) *EditNode {
+	node := &EditNode{
+		Name:      n.name.Local,
+		Namespace: n.name.Space,
+	}
+
+	if len(n.children) == 0 {
+		node.Value = strings.TrimSpace(n.directText.String())
+	}
+
+	for _, child := range n.children {
+		node.Children = append(node.Children, xmlNodeToEditNode(child))
+	}
+
+	return node
+}
+
+// canonical returns an order insensitive representation of the subtree rooted at n, used to
+// match list entries we have no keys for.
+func canonical(n *xmlNode) string {
+	if len(n.children) == 0 {
+		return fmt.Sprintf(
+			"{%s}%s=%s",
+			n.name.Space,
+			n.name.Local,
+			strings.TrimSpace(n.directText.String()),
+		)
+	}
+
+	children := make([]string, len(n.children))
+
+	for idx, child := range n.children {
+		children[idx] = canonical(child)
+	}
+
+	sort.Strings(children)
+
+	return fmt.Sprintf("{%s}%s(%s)", n.name.Space, n.name.Local, strings.Join(children, ","))
+}
+
+type diffGroup struct {
+	before []*xmlNode
+	after  []*xmlNode
+}
+
+// groupChildren groups the children of before and after by (namespaced) name, preserving the
+// order in which names first appear.
+func groupChildren(before, after *xmlNode) []*diffGroup {
+	var groups []*diffGroup
+
+	index := map[string]*diffGroup{}
+
+	add := func(n *xmlNode, isBefore bool) {
+		k := n.name.Space + " " + n.name.Local
+
+		group, ok := index[k]
+		if !ok {
+			group = &diffGroup{}
+			index[k] = group
+
+			groups = append(groups, group)
+		}
+
+		if isBefore {
+			group.before = append(group.before, n)
+		} else {
+			group.after = append(group.after, n)
+		}
+	}
+
+	if before != nil {
+		for _, child := range before.children {
+			add(child, true)
+		}
+	}
+
+	if after != nil {
+		for _, child := range after.children {
+			add(child, false)
+		}
+	}
+
+	return groups
+}
+
+func childValue(n *xmlNode, name string) (string, bool) {
+	for _, child := range n.children {
+		if child.name.Local == name && len(child.children) == 0 {
+			return strings.TrimSpace(child.directText.String()), true
+		}
+	}
+
+	return "", false
+}
+
+// entryKeys returns the key names used to identify entries of the list (or nil if entries should
+// be matched on their content).
+func (d *differ) entryKeys(name string, entries []*xmlNode) []string {
+	keys, ok := d.listKeys[name]
+	if ok {
+		return keys
+	}
+
+	for _, entry := range entries {
+		_, ok = childValue(entry, diffDefaultListKey)
+		if !ok {
+			return nil
+		}
+	}
+
+	return []string{diffDefaultListKey}
+}
+
+// entryIdentity returns the identity of a list (or leaf-list) entry and the path predicate that
+// identifies it.
+func entryIdentity(entry *xmlNode, keys []string, position int) (string, string) {
+	if len(entry.children) == 0 {
+		value := strings.TrimSpace(entry.directText.String())
+
+		return value, fmt.Sprintf("[.=%s]", diffQuote(value))
+	}
+
+	if keys == nil {
+		return canonical(entry), fmt.Sprintf("[%d]", position)
+	}
+
+	identity := make([]string, len(keys))
+
+	var predicate strings.Builder
+
+	for idx, key := range keys {
+		value, _ := childValue(entry, key)
+
+		identity[idx] = value
+
+		fmt.Fprintf(&predicate, "[%s=%s]", key, diffQuote(value))
+	}
+
+	return strings.Join(identity, "\x00"), predicate.String()
+}
+
+func diffQuote(s string) string {
+	if strings.Contains(s, "'") {
+		return `"` + s + `"`
+	}
+
+	return "'" + s + "'"
+}
+
+func (d *differ) diffList(path, name string, group *diffGroup) {
+	keys := d.entryKeys(name, slices.Concat(group.before, group.after))
+
+	type entry struct {
+		node      *xmlNode
+		predicate string
+	}
+
+	beforeEntries := map[string][]entry{}
+
+	var beforeOrder []string
+
+	for idx, n := range group.before {
+		identity, predicate := entryIdentity(n, keys, idx+1)
+
+		if _, ok := beforeEntries[identity]; !ok {
+			beforeOrder = append(beforeOrder, identity)
+		}
+
+		beforeEntries[identity] = append(beforeEntries[identity], entry{n, predicate})
+	}
+
+	for idx, n := range group.after {
+		identity, predicate := entryIdentity(n, keys, idx+1)
+
+		entryPath := path + "/" + name + predicate
+
+		candidates := beforeEntries[identity]
+		if len(candidates) == 0 {
+			d.record(DiffChangeAdded, entryPath, nil, n)
+
+			continue
+		}
+
+		beforeEntries[identity] = candidates[1:]
+
+		if keys != nil || len(n.children) == 0 {
+			// content matched entries are by definition identical, keyed entries may differ
+			d.diffNode(entryPath, candidates[0].node, n)
+		}
+	}
+
+	for _, identity := range beforeOrder {
+		for _, e := range beforeEntries[identity] {
+			d.record(DiffChangeRemoved, path+"/"+name+e.predicate, e.node, nil)
+		}
+	}
+}
+
+func (d *differ) diffNode(path string, before, after *xmlNode) {
+	beforeIsLeaf := len(before.children) == 0
+	afterIsLeaf := len(after.children) == 0
+
+	switch {
+	case beforeIsLeaf && afterIsLeaf:
+		if d.value(before) != d.value(after) {
+			d.record(DiffChangeModified, path, before, after)
+		}
+
+		return
+	case beforeIsLeaf != afterIsLeaf:
+		d.record(DiffChangeModified, path, before, after)
+
+		return
+	}
+
+	d.diffChildren(path, before, after)
+}
+
+func (d *differ) diffChildren(path string, before, after *xmlNode) {
+	for _, group := range groupChildren(before, after) {
+		var name string
+		if len(group.before) > 0 {
+			name = group.before[0].name.Local
+		} else {
+			name = group.after[0].name.Local
+		}
+
+		_, isConfiguredList := d.listKeys[name]
+
+		// entries w/ a known key are list entries even if there is only one of them on either
+		// side, otherwise a renamed entry would be diffed as a container w/ a modified key leaf
+		isKeyed := d.entryKeys(name, slices.Concat(group.before, group.after)) != nil
+
+		if isConfiguredList || isKeyed || len(group.before) > 1 || len(group.after) > 1 {
+			d.diffList(path, name, group)
+
+			continue
+		}
+
+		childPath := path + "/" + name
+
+		switch {
+		case len(group.before) == 0:
+			d.record(DiffChangeAdded, childPath, nil, group.after[0])
+		case len(group.after) == 0:
+			d.record(DiffChangeRemoved, childPath, group.before[0], nil)
+		default:
+			d.diffNode(childPath, group.before[0], group.after[0])
+		}
+	}
+}
+
+// DiffResults compares the bodies of two results (typically GetConfig results) and returns the
+// changes required to get from before to after. Elements are compared by name and namespace.
+// Repeated sibling elements, and elements with a known key, are treated as yang lists (or
+// leaf-lists) and matched by their keys so reordered entries are not reported as changes and a
+// changed key is reported as a removal and an addition; keys can be provided with
+// WithDiffListKeys, otherwise entries are keyed on their "name" leaf if every entry has one, and
+// on their entire content if not. Supported options:
+//   - WithDiffListKeys
+func DiffResults(before, after *Result, options ...Option) (*Diff, error) {
+	loadedOptions := newDiffOptions(options...)
+
+	beforeTree, err := parseXMLTree(before.Result)
+	if err != nil {
+		return nil, scrapligoerrors.NewUtilError("failed parsing 'before' result", err)
+	}
+
+	afterTree, err := parseXMLTree(after.Result)
+	if err != nil {
+		return nil, scrapligoerrors.NewUtilError("failed parsing 'after' result", err)
+	}
+
+	d := &differ{
+		listKeys: loadedOptions.listKeys,
+	}
+
+	d.diffChildren("", beforeTree.bodyNode(), afterTree.bodyNode())
+
+	return &Diff{Changes: d.changes}, nil
+}
+
+// DiffDatastores fetches the config of the source and target datastores and returns the changes
+// required to get from source to target -- for example the diff from running to candidate shows
+// what a commit would change. Any GetConfig options (i.e. filters) are applied to both GetConfig
+// rpcs, see DiffResults for diff options.
+func (n *Netconf) DiffDatastores(
+	ctx context.Context,
+	source, target DatastoreType,
+	options ...Option,
+) (*Diff, error) {
+	results := make([]*Result, 2) //nolint: mnd
+
+	for idx, datastore := range []DatastoreType{source, target} {
+		r, err := n.GetConfig(ctx, slices.Concat(options, []Option{WithSourceType(datastore)})...)
+		if err != nil {
+			return nil, err
+		}
+
+		if r.Failed {
+			return nil, scrapligoerrors.NewNetconfError(
+				"get-config for diff failed, rpc-reply contained errors",
+				nil,
+			)
+		}
+
+		results[idx] = r
+	}
+
+	return DiffResults(results[0], results[1], options...)
+}
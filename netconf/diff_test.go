@@ -0,0 +1,219 @@
+package netconf_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	scrapligonetconf "github.com/scrapli/scrapligo/v2/netconf"
+)
+
+const (
+	testDiffRunning = `<rpc-reply xmlns="urn:ietf:params:xml:ns:netconf:base:1.0" message-id="101">
+  <data>
+    <interfaces xmlns="urn:ietf:params:xml:ns:yang:ietf-interfaces">
+      <interface>
+        <name>eth0</name>
+        <enabled>true</enabled>
+      </interface>
+      <interface>
+        <name>eth1</name>
+        <enabled>true</enabled>
+      </interface>
+      <interface>
+        <name>eth2</name>
+        <enabled>true</enabled>
+      </interface>
+    </interfaces>
+    <system xmlns="urn:ietf:params:xml:ns:yang:ietf-system">
+      <hostname>router1</hostname>
+      <dns-resolver>
+        <search>a.example</search>
+        <search>b.example</search>
+      </dns-resolver>
+    </system>
+  </data>
+</rpc-reply>`
+	testDiffCandidate = `<rpc-reply xmlns="urn:ietf:params:xml:ns:netconf:base:1.0" message-id="102">
+  <data>
+    <system xmlns="urn:ietf:params:xml:ns:yang:ietf-system">
+      <hostname>router2</hostname>
+      <dns-resolver>
+        <search>b.example</search>
+        <search>a.example</search>
+      </dns-resolver>
+      <location>lab</location>
+    </system>
+    <interfaces xmlns="urn:ietf:params:xml:ns:yang:ietf-interfaces">
+      <interface>
+        <name>eth1</name>
+        <enabled>false</enabled>
+      </interface>
+      <interface>
+        <name>eth0</name>
+        <enabled>true</enabled>
+      </interface>
+      <interface>
+        <name>eth3</name>
+        <enabled>true</enabled>
+      </interface>
+    </interfaces>
+  </data>
+</rpc-reply>`
+)
+
+func TestDiffResults(t *testing.T) {
+	diff, err := scrapligonetconf.DiffResults(
+		&scrapligonetconf.Result{Result: testDiffRunning},
+		&scrapligonetconf.Result{Result: testDiffCandidate},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		"~ /interfaces/interface[name='eth1']/enabled: true -> false",
+		"+ /interfaces/interface[name='eth3']: <interface xmlns=\"urn:ietf:params:xml:ns:yang:ietf-interfaces\"><name>eth3</name><enabled>true</enabled></interface>",
+		"- /interfaces/interface[name='eth2']: <interface xmlns=\"urn:ietf:params:xml:ns:yang:ietf-interfaces\"><name>eth2</name><enabled>true</enabled></interface>",
+		"~ /system/hostname: router1 -> router2",
+		"+ /system/location: lab",
+		"",
+	}, "\n")
+
+	if diff.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, diff.String())
+	}
+
+	b, err := diff.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := &scrapligonetconf.Diff{}
+
+	err = json.Unmarshal(b, decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decoded.Changes) != len(diff.Changes) ||
+		decoded.Changes[0].Type != scrapligonetconf.DiffChangeModified ||
+		decoded.Changes[0].Namespace != "urn:ietf:params:xml:ns:yang:ietf-interfaces" {
+		t.Fatalf("unexpected json round trip %s", b)
+	}
+
+	same, err := scrapligonetconf.DiffResults(
+		&scrapligonetconf.Result{Result: testDiffRunning},
+		&scrapligonetconf.Result{Result: testDiffRunning},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if same.HasChanges() {
+		t.Fatalf("expected no changes, got:\n%s", same)
+	}
+}
+
+func TestDiffResultsListKeys(t *testing.T) {
+	before := `<rpc-reply><data><vlans xmlns="urn:example:vlans">` +
+		`<vlan><id>10</id><description>users</description></vlan>` +
+		`<vlan><id>20</id><description>voice</description></vlan>` +
+		`</vlans></data></rpc-reply>`
+	after := `<rpc-reply><data><vlans xmlns="urn:example:vlans">` +
+		`<vlan><id>20</id><description>phones</description></vlan>` +
+		`<vlan><id>10</id><description>users</description></vlan>` +
+		`</vlans></data></rpc-reply>`
+
+	diff, err := scrapligonetconf.DiffResults(
+		&scrapligonetconf.Result{Result: before},
+		&scrapligonetconf.Result{Result: after},
+		scrapligonetconf.WithDiffListKeys(map[string][]string{"vlan": {"id"}}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "~ /vlans/vlan[id='20']/description: voice -> phones\n"
+
+	if diff.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, diff.String())
+	}
+
+	// without keys the entries are matched on content, so the modified entry shows up as a
+	// remove and an add but the reordered (unchanged) entry does not show up at all
+	diff, err = scrapligonetconf.DiffResults(
+		&scrapligonetconf.Result{Result: before},
+		&scrapligonetconf.Result{Result: after},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(diff.Changes) != 2 ||
+		diff.Changes[0].Type != scrapligonetconf.DiffChangeAdded ||
+		diff.Changes[1].Type != scrapligonetconf.DiffChangeRemoved {
+		t.Fatalf("unexpected changes:\n%s", diff)
+	}
+}
+
+func TestDiffResultsSingleEntryList(t *testing.T) {
+	before := `<rpc-reply><data><interfaces xmlns="urn:example:interfaces">` +
+		`<interface><name>eth0</name><enabled>true</enabled></interface>` +
+		`</interfaces></data></rpc-reply>`
+	modified := `<rpc-reply><data><interfaces xmlns="urn:example:interfaces">` +
+		`<interface><name>eth0</name><enabled>false</enabled></interface>` +
+		`</interfaces></data></rpc-reply>`
+	renamed := `<rpc-reply><data><interfaces xmlns="urn:example:interfaces">` +
+		`<interface><name>eth1</name><enabled>true</enabled></interface>` +
+		`</interfaces></data></rpc-reply>`
+
+	cases := map[string]struct {
+		after    string
+		options  []scrapligonetconf.Option
+		expected string
+	}{
+		"modified": {
+			after:    modified,
+			expected: "~ /interfaces/interface[name='eth0']/enabled: true -> false\n",
+		},
+		"key-renamed": {
+			after: renamed,
+			expected: strings.Join([]string{
+				"+ /interfaces/interface[name='eth1']: <interface xmlns=\"urn:example:interfaces\"><name>eth1</name><enabled>true</enabled></interface>",
+				"- /interfaces/interface[name='eth0']: <interface xmlns=\"urn:example:interfaces\"><name>eth0</name><enabled>true</enabled></interface>",
+				"",
+			}, "\n"),
+		},
+		"configured-key-renamed": {
+			after: renamed,
+			options: []scrapligonetconf.Option{
+				scrapligonetconf.WithDiffListKeys(
+					map[string][]string{"interface": {"name", "enabled"}},
+				),
+			},
+			expected: strings.Join([]string{
+				"+ /interfaces/interface[name='eth1'][enabled='true']: <interface xmlns=\"urn:example:interfaces\"><name>eth1</name><enabled>true</enabled></interface>",
+				"- /interfaces/interface[name='eth0'][enabled='true']: <interface xmlns=\"urn:example:interfaces\"><name>eth0</name><enabled>true</enabled></interface>",
+				"",
+			}, "\n"),
+		},
+	}
+
+	for caseName, caseData := range cases {
+		t.Run(caseName, func(t *testing.T) {
+			diff, err := scrapligonetconf.DiffResults(
+				&scrapligonetconf.Result{Result: before},
+				&scrapligonetconf.Result{Result: caseData.after},
+				caseData.options...,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff.String() != caseData.expected {
+				t.Fatalf("expected:\n%s\ngot:\n%s", caseData.expected, diff.String())
+			}
+		})
+	}
+}
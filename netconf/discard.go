@@ -4,6 +4,7 @@ import (
 	"context"
 
 	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
 )
 
 // Discard executes a netconf discard rpc.
@@ -11,24 +12,45 @@ func (n *Netconf) Discard(
 	ctx context.Context,
 	options ...Option,
 ) (*Result, error) {
+	op, err := n.DiscardAsync(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return op.Wait(ctx)
+}
+
+// DiscardAsync is the async flavor of Discard -- the rpc is submitted and an OperationHandle is
+// returned immediately, see Discard for supported options.
+func (n *Netconf) DiscardAsync(
+	ctx context.Context,
+	options ...Option,
+) (*OperationHandle, error) {
 	_ = options
 
 	if n.ptr == 0 {
 		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
 	}
 
-	cancel := false
-
-	var operationID uint32
+	err := n.requireCapability("discard", CapabilityCandidate)
+	if err != nil {
+		return nil, err
+	}
 
-	err := n.ffiMap.Netconf.Discard(
-		n.ptr,
-		&operationID,
-		&cancel,
+	op, err := n.submit(
+		ctx,
+		"discard",
+		func(m *scrapligoffi.NetconfMapping, id *uint32, cancel *scrapligoffi.CancelFlag) error {
+			return m.Discard(
+				n.ptr,
+				id,
+				cancel,
+			)
+		},
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	return n.getResult(ctx, &cancel, operationID)
+	return op, nil
 }
@@ -0,0 +1,432 @@
+package netconf
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	scrapligoconstants "github.com/scrapli/scrapligo/v2/constants"
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+)
+
+const (
+	netconfMonitoringNamespace = "urn:ietf:params:xml:ns:yang:ietf-netconf-monitoring"
+	yangLibraryNamespace       = "urn:ietf:params:xml:ns:yang:ietf-yang-library"
+
+	netconfMonitoringSchemasFilter = `<netconf-state xmlns="` + netconfMonitoringNamespace + `">` +
+		`<schemas/></netconf-state>`
+	yangLibraryFilter = `<yang-library xmlns="` + yangLibraryNamespace + `">` +
+		`<module-set/></yang-library>`
+	yangLibraryModulesStateFilter = `<modules-state xmlns="` + yangLibraryNamespace + `"/>`
+
+	yangFileExtension = ".yang"
+)
+
+// SchemaInfo describes a single schema (module or submodule) available on a server.
+type SchemaInfo struct {
+	Identifier string
+	Version    string
+}
+
+// Filename returns the conventional filename for the schema -- "name@revision.yang", or just
+// "name.yang" if the schema has no revision.
+func (s SchemaInfo) Filename() string {
+	if s.Version == "" {
+		return s.Identifier + yangFileExtension
+	}
+
+	return fmt.Sprintf("%s@%s%s", s.Identifier, s.Version, yangFileExtension)
+}
+
+// Path returns the path of the schema file (see Filename) in dir. The identifier and version are
+// supplied by the server, so an error is returned if either holds a path separator or is a dot
+// name -- a schema must never be written outside of dir.
+func (s SchemaInfo) Path(dir string) (string, error) {
+	for _, v := range []string{s.Identifier, s.Version} {
+		if strings.ContainsAny(v, `/\`) || strings.Contains(v, "..") || v == "." {
+			return "", scrapligoerrors.NewNetconfError(
+				fmt.Sprintf("refusing unsafe schema name %q", s.Filename()),
+				nil,
+			)
+		}
+	}
+
+	path := filepath.Join(dir, s.Filename())
+
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel != filepath.Base(path) {
+		return "", scrapligoerrors.NewNetconfError(
+			fmt.Sprintf("refusing unsafe schema name %q", s.Filename()),
+			err,
+		)
+	}
+
+	return path, nil
+}
+
+// SchemaDownloadResult holds the outcome of downloading a single schema.
+type SchemaDownloadResult struct {
+	Schema  SchemaInfo
+	Path    string
+	Skipped bool
+	Err     error
+}
+
+// SchemaDownloadProgress is passed to the progress callback (see WithSchemaProgressCallback) after
+// each schema is processed.
+type SchemaDownloadProgress struct {
+	Total     int
+	Completed int
+	Skipped   int
+	Failed    int
+	Last      *SchemaDownloadResult
+}
+
+type schemaProgressReporter struct {
+	progress SchemaDownloadProgress
+	callback func(progress SchemaDownloadProgress)
+}
+
+// report records the result and invokes the users callback (if any). Schemas are downloaded one
+// at a time, so the callback is always invoked from the goroutine calling DownloadSchemas.
+func (r *schemaProgressReporter) report(result *SchemaDownloadResult) {
+	r.progress.Completed++
+
+	switch {
+	case result.Err != nil:
+		r.progress.Failed++
+	case result.Skipped:
+		r.progress.Skipped++
+	}
+
+	r.progress.Last = result
+
+	if r.callback != nil {
+		r.callback(r.progress)
+	}
+}
+
+func newDownloadSchemasOptions(options ...Option) *downloadSchemasOptions {
+	o := &downloadSchemasOptions{}
+
+	for _, opt := range options {
+		opt(o)
+	}
+
+	return o
+}
+
+type downloadSchemasOptions struct {
+	progressCallback func(progress SchemaDownloadProgress)
+	overwrite        bool
+}
+
+type monitoringSchemasReply struct {
+	Schemas []struct {
+		Identifier string `xml:"identifier"`
+		Version    string `xml:"version"`
+		Format     string `xml:"format"`
+	} `xml:"data>netconf-state>schemas>schema"`
+}
+
+type yangLibraryModule struct {
+	Name      string `xml:"name"`
+	Revision  string `xml:"revision"`
+	Submodule []struct {
+		Name     string `xml:"name"`
+		Revision string `xml:"revision"`
+	} `xml:"submodule"`
+}
+
+type yangLibraryReply struct {
+	ModuleSetModules []yangLibraryModule `xml:"data>yang-library>module-set>module"`
+	ModuleSetImports []yangLibraryModule `xml:"data>yang-library>module-set>import-only-module"`
+	ModulesState     []yangLibraryModule `xml:"data>modules-state>module"`
+}
+
+type getSchemaReply struct {
+	Data string `xml:"data"`
+}
+
+// isYangFormat returns true if the (possibly prefixed, i.e. "ncm:yang") format is yang.
+func isYangFormat(format string) bool {
+	if format == "" {
+		// format is a mandatory key, but be lenient and assume yang
+		return true
+	}
+
+	_, after, found := strings.Cut(format, ":")
+	if found {
+		format = after
+	}
+
+	return format == "yang"
+}
+
+func parseMonitoringSchemas(reply string) ([]SchemaInfo, error) {
+	parsed := &monitoringSchemasReply{}
+
+	err := xml.Unmarshal([]byte(reply), parsed)
+	if err != nil {
+		return nil, err
+	}
+
+	var schemas []SchemaInfo
+
+	for _, schema := range parsed.Schemas {
+		if !isYangFormat(strings.TrimSpace(schema.Format)) {
+			continue
+		}
+
+		schemas = appendSchema(schemas, schema.Identifier, schema.Version)
+	}
+
+	return schemas, nil
+}
+
+func parseYangLibrarySchemas(reply string) ([]SchemaInfo, error) {
+	parsed := &yangLibraryReply{}
+
+	err := xml.Unmarshal([]byte(reply), parsed)
+	if err != nil {
+		return nil, err
+	}
+
+	var schemas []SchemaInfo
+
+	for _, modules := range [][]yangLibraryModule{
+		parsed.ModuleSetModules,
+		parsed.ModuleSetImports,
+		parsed.ModulesState,
+	} {
+		for _, module := range modules {
+			schemas = appendSchema(schemas, module.Name, module.Revision)
+
+			for _, submodule := range module.Submodule {
+				schemas = appendSchema(schemas, submodule.Name, submodule.Revision)
+			}
+		}
+	}
+
+	return schemas, nil
+}
+
+// appendSchema appends the schema to schemas if it is not already present -- servers often list
+// the same submodule for multiple modules, or list a module both as implemented and import-only.
+func appendSchema(schemas []SchemaInfo, identifier, version string) []SchemaInfo {
+	schema := SchemaInfo{
+		Identifier: strings.TrimSpace(identifier),
+		Version:    strings.TrimSpace(version),
+	}
+
+	if schema.Identifier == "" {
+		return schemas
+	}
+
+	for _, existing := range schemas {
+		if existing == schema {
+			return schemas
+		}
+	}
+
+	return append(schemas, schema)
+}
+
+// ListSchemas returns the yang schemas (modules and submodules) the server makes available. The
+// schema list is read from ietf-netconf-monitoring, falling back to ietf-yang-library (both the
+// nmda "yang-library" and legacy "modules-state" flavors) if the monitoring list is unavailable
+// or empty.
+func (n *Netconf) ListSchemas(ctx context.Context) ([]SchemaInfo, error) {
+	var errs []error
+
+	for _, source := range []struct {
+		filter string
+		parse  func(reply string) ([]SchemaInfo, error)
+	}{
+		{filter: netconfMonitoringSchemasFilter, parse: parseMonitoringSchemas},
+		{filter: yangLibraryFilter, parse: parseYangLibrarySchemas},
+		{filter: yangLibraryModulesStateFilter, parse: parseYangLibrarySchemas},
+	} {
+		schemas, err := n.listSchemas(ctx, source.filter, source.parse)
+		if err == nil {
+			return schemas, nil
+		}
+
+		errs = append(errs, err)
+	}
+
+	return nil, scrapligoerrors.NewNetconfError(
+		"failed listing schemas from ietf-netconf-monitoring or ietf-yang-library",
+		errors.Join(errs...),
+	)
+}
+
+// listSchemas gets and parses the schema list for a single source (filter) -- an error is
+// returned if the get fails, the server replies with rpc-errors, or the list is unparsable or
+// empty, so the caller can fall back to the next source.
+func (n *Netconf) listSchemas(
+	ctx context.Context,
+	filter string,
+	parse func(reply string) ([]SchemaInfo, error),
+) ([]SchemaInfo, error) {
+	r, err := n.Get(ctx, WithFilter(filter))
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Failed {
+		return nil, scrapligoerrors.NewNetconfError(
+			fmt.Sprintf(
+				"get for schema list failed: %s",
+				strings.TrimSpace(strings.Join(r.Errors, " ")),
+			),
+			nil,
+		)
+	}
+
+	schemas, err := parse(r.Result)
+	if err != nil {
+		return nil, scrapligoerrors.NewNetconfError("failed parsing schema list", err)
+	}
+
+	if len(schemas) == 0 {
+		return nil, scrapligoerrors.NewNetconfError("schema list was empty", nil)
+	}
+
+	return schemas, nil
+}
+
+func (n *Netconf) downloadSchema(
+	ctx context.Context,
+	schema SchemaInfo,
+	path string,
+) error {
+	options := []Option{WithSchemaFormat(SchemaFormatYang)}
+
+	if schema.Version != "" {
+		options = append(options, WithVersion(schema.Version))
+	}
+
+	r, err := n.GetSchema(ctx, schema.Identifier, options...)
+	if err != nil {
+		return err
+	}
+
+	if r.Failed {
+		return scrapligoerrors.NewNetconfError(
+			fmt.Sprintf(
+				"get-schema for %q failed: %s",
+				schema.Identifier,
+				strings.TrimSpace(strings.Join(r.Errors, " ")),
+			),
+			nil,
+		)
+	}
+
+	parsed := &getSchemaReply{}
+
+	err = xml.Unmarshal([]byte(r.Result), parsed)
+	if err != nil {
+		return scrapligoerrors.NewNetconfError(
+			fmt.Sprintf("failed parsing get-schema reply for %q", schema.Identifier),
+			err,
+		)
+	}
+
+	content := strings.TrimSpace(parsed.Data)
+	if content == "" {
+		return scrapligoerrors.NewNetconfError(
+			fmt.Sprintf("get-schema reply for %q was empty", schema.Identifier),
+			nil,
+		)
+	}
+
+	return os.WriteFile(
+		path,
+		[]byte(content+"\n"),
+		scrapligoconstants.PermissionsOwnerReadWriteEveryoneRead,
+	)
+}
+
+// DownloadSchemas fetches every yang module and submodule the server makes available (see
+// ListSchemas) and writes each to dir as "name@revision.yang" -- schemas whose names would place
+// them outside of dir fail rather than being written (see SchemaInfo.Path). Schemas that already
+// exist in dir are skipped unless WithSchemaOverwrite is provided. A failure to download a single
+// schema does not stop the process -- the returned results hold the outcome of each schema and an
+// error wrapping all individual failures is returned alongside them. Supported options:
+//   - WithSchemaProgressCallback
+//   - WithSchemaOverwrite
+func (n *Netconf) DownloadSchemas(
+	ctx context.Context,
+	dir string,
+	options ...Option,
+) ([]*SchemaDownloadResult, error) {
+	if n.ptr == 0 {
+		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
+	}
+
+	loadedOptions := newDownloadSchemasOptions(options...)
+
+	err := os.MkdirAll(dir, scrapligoconstants.PermissionsOwnerReadWriteExecute)
+	if err != nil {
+		return nil, scrapligoerrors.NewUtilError(
+			fmt.Sprintf("failed ensuring schema directory %q", dir),
+			err,
+		)
+	}
+
+	schemas, err := n.ListSchemas(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reporter := &schemaProgressReporter{
+		progress: SchemaDownloadProgress{Total: len(schemas)},
+		callback: loadedOptions.progressCallback,
+	}
+
+	results := make([]*SchemaDownloadResult, len(schemas))
+
+	var errs []error
+
+	for i, schema := range schemas {
+		if ctx.Err() != nil {
+			return results[:i], ctx.Err()
+		}
+
+		result := &SchemaDownloadResult{
+			Schema: schema,
+		}
+
+		result.Path, result.Err = schema.Path(dir)
+		if result.Err == nil {
+			_, statErr := os.Stat(result.Path)
+			if statErr == nil && !loadedOptions.overwrite {
+				result.Skipped = true
+			} else {
+				result.Err = n.downloadSchema(ctx, schema, result.Path)
+			}
+		}
+
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+		}
+
+		results[i] = result
+
+		reporter.report(result)
+	}
+
+	if len(errs) > 0 {
+		return results, scrapligoerrors.NewNetconfError(
+			fmt.Sprintf("failed downloading %d of %d schemas", len(errs), len(schemas)),
+			errors.Join(errs...),
+		)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,155 @@
+package netconf_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	scrapligonetconf "github.com/scrapli/scrapligo/v2/netconf"
+)
+
+func TestDownloadSchemas(t *testing.T) {
+	parentName := "download-schemas"
+
+	cases := map[string]struct {
+		description   string
+		options       []scrapligonetconf.Option
+		expectedFiles []string
+	}{
+		"simple": {
+			description: "simple - download all yang schemas listed in netconf-monitoring",
+			expectedFiles: []string{
+				"ietf-yang-types@2013-07-15.yang",
+				"ietf-inet-types@2013-07-15.yang",
+			},
+		},
+	}
+
+	for caseName, c := range cases {
+		testName := fmt.Sprintf("%s-%s", parentName, caseName)
+
+		t.Run(testName, func(t *testing.T) {
+			t.Logf("%s: starting", testName)
+
+			testFixturePath, err := filepath.Abs(fmt.Sprintf("./fixtures/%s", testName))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			defer cancel()
+
+			n := getNetconf(t, testFixturePath)
+
+			_, err = n.Open(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer func() {
+				_, _ = n.Close(ctx)
+			}()
+
+			var lastProgress scrapligonetconf.SchemaDownloadProgress
+
+			dir := t.TempDir()
+
+			results, err := n.DownloadSchemas(
+				ctx,
+				dir,
+				append(
+					c.options,
+					scrapligonetconf.WithSchemaProgressCallback(
+						func(progress scrapligonetconf.SchemaDownloadProgress) {
+							lastProgress = progress
+						},
+					),
+				)...,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(results) != len(c.expectedFiles) {
+				t.Fatalf("expected %d results, got %d", len(c.expectedFiles), len(results))
+			}
+
+			if lastProgress.Completed != len(c.expectedFiles) || lastProgress.Failed != 0 {
+				t.Fatalf("unexpected final progress %+v", lastProgress)
+			}
+
+			for _, expectedFile := range c.expectedFiles {
+				b, err := os.ReadFile(filepath.Join(dir, expectedFile))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if len(b) == 0 {
+					t.Fatalf("expected schema file %q to have content", expectedFile)
+				}
+			}
+		})
+	}
+}
+
+func TestSchemaInfoPath(t *testing.T) {
+	dir := t.TempDir()
+
+	cases := map[string]struct {
+		schema      scrapligonetconf.SchemaInfo
+		expectedErr bool
+	}{
+		"simple": {
+			schema: scrapligonetconf.SchemaInfo{
+				Identifier: "ietf-inet-types",
+				Version:    "2013-07-15",
+			},
+		},
+		"no-version": {
+			schema: scrapligonetconf.SchemaInfo{Identifier: "ietf-inet-types"},
+		},
+		"identifier-traversal": {
+			schema:      scrapligonetconf.SchemaInfo{Identifier: "../../etc/cron.d/evil"},
+			expectedErr: true,
+		},
+		"version-traversal": {
+			schema: scrapligonetconf.SchemaInfo{
+				Identifier: "evil",
+				Version:    "/../../../tmp/evil",
+			},
+			expectedErr: true,
+		},
+		"identifier-backslash": {
+			schema:      scrapligonetconf.SchemaInfo{Identifier: `..\evil`},
+			expectedErr: true,
+		},
+		"identifier-dot-dot": {
+			schema:      scrapligonetconf.SchemaInfo{Identifier: ".."},
+			expectedErr: true,
+		},
+	}
+
+	for caseName, caseData := range cases {
+		t.Run(caseName, func(t *testing.T) {
+			path, err := caseData.schema.Path(dir)
+			if caseData.expectedErr {
+				if err == nil {
+					t.Fatalf("expected error for unsafe schema name, got path %q", path)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if path != filepath.Join(dir, caseData.schema.Filename()) {
+				t.Fatalf("expected path in %q, got %q", dir, path)
+			}
+		})
+	}
+}
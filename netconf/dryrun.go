@@ -0,0 +1,39 @@
+package netconf
+
+import (
+	"time"
+
+	scrapligodryrun "github.com/scrapli/scrapligo/v2/dryrun"
+	scrapligoutil "github.com/scrapli/scrapligo/v2/util"
+)
+
+// dryRuns returns true if the rpc is recorded rather than submitted -- the Netconf object is in
+// dry-run mode and the rpc changes configuration.
+func (n *Netconf) dryRuns(rpc string) bool {
+	if n.options.DryRun.Plan == nil {
+		return false
+	}
+
+	switch rpc {
+	case "edit-config", "edit-data", "copy-config", "delete-config", "commit":
+		return true
+	default:
+		return false
+	}
+}
+
+// dryRun records the rpc in the dry-run plan and returns a synthetic (empty) Result.
+func (n *Netconf) dryRun(rpc *RPC) *Result {
+	n.options.DryRun.Plan.Record(&scrapligodryrun.Step{
+		Time:      time.Now(),
+		Driver:    "netconf",
+		Host:      rpc.Host,
+		Port:      rpc.Port,
+		Operation: rpc.Name,
+		Payload:   rpc.Payload,
+	})
+
+	now := scrapligoutil.SafeInt64ToUint64(time.Now().UnixNano())
+
+	return NewResult(rpc.Payload, rpc.Host, rpc.Port, now, now, nil, "", nil, nil)
+}
@@ -0,0 +1,70 @@
+package netconf_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	scrapligodryrun "github.com/scrapli/scrapligo/v2/dryrun"
+	scrapligonetconf "github.com/scrapli/scrapligo/v2/netconf"
+	scrapligooptions "github.com/scrapli/scrapligo/v2/options"
+)
+
+func TestDryRun(t *testing.T) {
+	testFixturePath, err := filepath.Abs("./fixtures/lock-simple")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	plan := scrapligodryrun.NewPlan()
+
+	n := getNetconf(t, testFixturePath, scrapligooptions.WithDryRun(plan))
+
+	_, err = n.Open(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_, _ = n.Close(ctx)
+	}()
+
+	_, err = n.Lock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := n.EditConfig(
+		ctx,
+		"<system><hostname>dry-run</hostname></system>",
+		scrapligonetconf.WithTargetType(scrapligonetconf.DatastoreTypeRunning),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Failed {
+		t.Fatal("expected synthetic result to not be failed")
+	}
+
+	_, err = n.Unlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps := plan.Steps()
+
+	if len(steps) != 1 {
+		t.Fatalf("expected 1 recorded step, got %d", len(steps))
+	}
+
+	if steps[0].Operation != "edit-config" ||
+		!strings.Contains(steps[0].Payload, "<hostname>dry-run</hostname>") {
+		t.Fatalf("unexpected recorded step %+v", steps[0])
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
 )
 
 func newEditConfigOptions(options ...Option) *editConfigOptions {
@@ -70,29 +71,51 @@ func (n *Netconf) EditConfig(
 	config string,
 	options ...Option,
 ) (*Result, error) {
-	if n.ptr == 0 {
-		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
+	op, err := n.EditConfigAsync(ctx, config, options...)
+	if err != nil {
+		return nil, err
 	}
 
-	cancel := false
+	return op.Wait(ctx)
+}
 
-	var operationID uint32
+// EditConfigAsync is the async flavor of EditConfig -- the rpc is submitted and an OperationHandle
+// is returned immediately, see EditConfig for supported options.
+func (n *Netconf) EditConfigAsync(
+	ctx context.Context,
+	config string,
+	options ...Option,
+) (*OperationHandle, error) {
+	if n.ptr == 0 {
+		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
+	}
 
 	loadedOptions := newEditConfigOptions(options...)
 
-	err := n.ffiMap.Netconf.EditConfig(
-		n.ptr,
-		&operationID,
-		&cancel,
-		config,
-		loadedOptions.getTarget(),
-		loadedOptions.getDefaultOperation(),
-		loadedOptions.getTestOption(),
-		loadedOptions.getErrorOption(),
+	err := n.requireWritableDatastore("edit-config", loadedOptions.target)
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := n.submit(
+		ctx,
+		"edit-config",
+		func(m *scrapligoffi.NetconfMapping, id *uint32, cancel *scrapligoffi.CancelFlag) error {
+			return m.EditConfig(
+				n.ptr,
+				id,
+				cancel,
+				config,
+				loadedOptions.getTarget(),
+				loadedOptions.getDefaultOperation(),
+				loadedOptions.getTestOption(),
+				loadedOptions.getErrorOption(),
+			)
+		},
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	return n.getResult(ctx, &cancel, operationID)
+	return op, nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
 )
 
 func newEditDataOptions(options ...Option) *editDataOptions {
@@ -48,27 +49,49 @@ func (n *Netconf) EditData(
 	content string,
 	options ...Option,
 ) (*Result, error) {
-	if n.ptr == 0 {
-		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
+	op, err := n.EditDataAsync(ctx, content, options...)
+	if err != nil {
+		return nil, err
 	}
 
-	cancel := false
+	return op.Wait(ctx)
+}
 
-	var operationID uint32
+// EditDataAsync is the async flavor of EditData -- the rpc is submitted and an OperationHandle is
+// returned immediately, see EditData for supported options.
+func (n *Netconf) EditDataAsync(
+	ctx context.Context,
+	content string,
+	options ...Option,
+) (*OperationHandle, error) {
+	if n.ptr == 0 {
+		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
+	}
 
 	loadedOptions := newEditDataOptions(options...)
 
-	err := n.ffiMap.Netconf.EditData(
-		n.ptr,
-		&operationID,
-		&cancel,
-		loadedOptions.getDatastore(),
-		content,
-		loadedOptions.getDefaultOperation(),
+	err := n.requireNmda("edit-data")
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := n.submit(
+		ctx,
+		"edit-data",
+		func(m *scrapligoffi.NetconfMapping, id *uint32, cancel *scrapligoffi.CancelFlag) error {
+			return m.EditData(
+				n.ptr,
+				id,
+				cancel,
+				loadedOptions.getDatastore(),
+				content,
+				loadedOptions.getDefaultOperation(),
+			)
+		},
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	return n.getResult(ctx, &cancel, operationID)
+	return op, nil
 }
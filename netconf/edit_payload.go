@@ -0,0 +1,364 @@
+package netconf
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+)
+
+const (
+	netconfBaseNamespace = "urn:ietf:params:xml:ns:netconf:base:1.0"
+	operationAttrName    = "operation"
+	operationAttrPrefix  = "nc"
+	xmlnsAttrName        = "xmlns"
+)
+
+// Operation is an enum(ish) representing the netconf "operation" attribute that can be set on any
+// node of an edit-config or edit-data payload.
+type Operation string
+
+const (
+	OperationMerge   Operation = "merge"
+	OperationReplace Operation = "replace"
+	OperationCreate  Operation = "create"
+	OperationDelete  Operation = "delete"
+	OperationRemove  Operation = "remove"
+)
+
+func (o Operation) validate() error {
+	switch o {
+	case "", OperationMerge, OperationReplace, OperationCreate, OperationDelete, OperationRemove:
+		return nil
+	default:
+		return fmt.Errorf("invalid operation %q", string(o))
+	}
+}
+
+// MarshalXMLAttr implements xml.MarshalerAttr so an Operation can be used as a struct field to set
+// the operation of the element the struct represents, for example:
+//
+//	type Interface struct {
+//		XMLName   xml.Name          `xml:"urn:ietf:params:xml:ns:yang:ietf-interfaces interface"`
+//		Operation netconf.Operation `xml:"operation,attr,omitempty"`
+//		Name      string            `xml:"name"`
+//	}
+//
+// The attribute is always placed in the netconf base namespace regardless of the field tag.
+func (o Operation) MarshalXMLAttr(_ xml.Name) (xml.Attr, error) {
+	if o == "" {
+		return xml.Attr{}, nil
+	}
+
+	err := o.validate()
+	if err != nil {
+		return xml.Attr{}, err
+	}
+
+	return xml.Attr{
+		Name:  xml.Name{Space: netconfBaseNamespace, Local: operationAttrName},
+		Value: string(o),
+	}, nil
+}
+
+// EditNode is a generic, ordered tree representation of an edit-config/edit-data payload, useful
+// when there is no typed model to marshal from. Namespace is inherited from the parent node if
+// unset. Value is only rendered for nodes without children.
+type EditNode struct {
+	Name       string
+	Namespace  string
+	Operation  Operation
+	Attributes []xml.Attr
+	Value      string
+	Children   []*EditNode
+}
+
+// AddChild appends a child node and returns it, allowing trees to be built up fluently.
+func (e *EditNode) AddChild(name, value string) *EditNode {
+	child := &EditNode{
+		Name:  name,
+		Value: value,
+	}
+
+	e.Children = append(e.Children, child)
+
+	return child
+}
+
+func (e *EditNode) hasOperation() bool {
+	if e.Operation != "" {
+		return true
+	}
+
+	for _, child := range e.Children {
+		if child.hasOperation() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e *EditNode) validate() error {
+	if e.Name == "" {
+		return errors.New("edit node has no name")
+	}
+
+	err := e.Operation.validate()
+	if err != nil {
+		return err
+	}
+
+	for _, child := range e.Children {
+		err = child.validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func escapeXML(b *strings.Builder, s string) {
+	// writes to a strings.Builder never fail
+	_ = xml.EscapeText(b, []byte(s))
+}
+
+func (e *EditNode) render(b *strings.Builder, parentNamespace string, declareOperation bool) {
+	b.WriteString("<")
+	b.WriteString(e.Name)
+
+	namespace := e.Namespace
+	if namespace == "" {
+		namespace = parentNamespace
+	}
+
+	if namespace != parentNamespace {
+		b.WriteString(` xmlns="`)
+		escapeXML(b, namespace)
+		b.WriteString(`"`)
+	}
+
+	if declareOperation {
+		fmt.Fprintf(b, ` xmlns:%s="%s"`, operationAttrPrefix, netconfBaseNamespace)
+	}
+
+	for idx, attr := range e.Attributes {
+		b.WriteString(" ")
+
+		if attr.Name.Space != "" {
+			// namespaced attributes get a locally declared prefix, there is no guarantee any
+			// other prefix would be in scope
+			prefix := fmt.Sprintf("a%d", idx)
+
+			fmt.Fprintf(b, `xmlns:%s="`, prefix)
+			escapeXML(b, attr.Name.Space)
+			fmt.Fprintf(b, `" %s:`, prefix)
+		}
+
+		b.WriteString(attr.Name.Local)
+		b.WriteString(`="`)
+		escapeXML(b, attr.Value)
+		b.WriteString(`"`)
+	}
+
+	if e.Operation != "" {
+		fmt.Fprintf(b, ` %s:%s="%s"`, operationAttrPrefix, operationAttrName, e.Operation)
+	}
+
+	if len(e.Children) == 0 && e.Value == "" {
+		b.WriteString("/>")
+
+		return
+	}
+
+	b.WriteString(">")
+
+	if len(e.Children) == 0 {
+		escapeXML(b, e.Value)
+	}
+
+	for _, child := range e.Children {
+		child.render(b, namespace, false)
+	}
+
+	b.WriteString("</")
+	b.WriteString(e.Name)
+	b.WriteString(">")
+}
+
+func renderEditNodes(nodes []*EditNode) (string, error) {
+	b := &strings.Builder{}
+
+	for _, node := range nodes {
+		err := node.validate()
+		if err != nil {
+			return "", err
+		}
+
+		node.render(b, "", node.hasOperation())
+	}
+
+	return b.String(), nil
+}
+
+// editNodesFromXML converts marshalled xml into edit nodes -- this lets us normalize whatever
+// encoding/xml spits out (namespace declarations on every element, generated prefixes for the
+// operation attribute, etc.) into a sane payload.
+func editNodesFromXML(raw []byte) ([]*EditNode, error) {
+	d := xml.NewDecoder(bytes.NewReader(raw))
+
+	var (
+		roots []*EditNode
+		stack []*EditNode
+	)
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, err
+		}
+
+		switch typedTok := tok.(type) {
+		case xml.StartElement:
+			node := &EditNode{
+				Name:      typedTok.Name.Local,
+				Namespace: typedTok.Name.Space,
+			}
+
+			for _, attr := range typedTok.Attr {
+				switch {
+				case attr.Name.Space == netconfBaseNamespace &&
+					attr.Name.Local == operationAttrName:
+					node.Operation = Operation(attr.Value)
+				case attr.Name.Space == xmlnsAttrName && attr.Value == netconfBaseNamespace,
+					attr.Name.Space == "" && attr.Name.Local == xmlnsAttrName:
+					// the namespace of the element itself or the prefix for the operation attr
+					// which will be re-rendered appropriately
+				case attr.Name.Space == xmlnsAttrName:
+					// other prefix declarations are retained as they may be referenced by
+					// values (i.e. identityrefs), the decoder reports these with a space of
+					// "xmlns" so put them back in to the form the renderer expects
+					node.Attributes = append(node.Attributes, xml.Attr{
+						Name:  xml.Name{Local: xmlnsAttrName + ":" + attr.Name.Local},
+						Value: attr.Value,
+					})
+				default:
+					node.Attributes = append(node.Attributes, attr)
+				}
+			}
+
+			if len(stack) == 0 {
+				roots = append(roots, node)
+			} else {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, node)
+			}
+
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].Value += string(typedTok)
+			}
+		}
+	}
+
+	for _, root := range roots {
+		clearContainerValues(root)
+	}
+
+	return roots, nil
+}
+
+// clearContainerValues drops the (whitespace) character data between the children of a node.
+func clearContainerValues(node *EditNode) {
+	if len(node.Children) == 0 {
+		return
+	}
+
+	node.Value = ""
+
+	for _, child := range node.Children {
+		clearContainerValues(child)
+	}
+}
+
+// MarshalEditPayload renders v as an edit-config/edit-data payload -- the content that goes
+// inside of the config (or edit-data config) element. v may be an *EditNode, a []*EditNode, or
+// any value encoding/xml can marshal (i.e. structs with xml tags, or slices of them). Operation
+// fields/nodes are rendered as "nc:operation" attributes with the "nc" prefix declared on the top
+// level elements that need it.
+func MarshalEditPayload(v any) (string, error) {
+	var nodes []*EditNode
+
+	switch typedV := v.(type) {
+	case *EditNode:
+		nodes = []*EditNode{typedV}
+	case EditNode:
+		nodes = []*EditNode{&typedV}
+	case []*EditNode:
+		nodes = typedV
+	default:
+		raw, err := xml.Marshal(v)
+		if err != nil {
+			return "", scrapligoerrors.NewUtilError("failed marshalling edit payload", err)
+		}
+
+		nodes, err = editNodesFromXML(raw)
+		if err != nil {
+			return "", scrapligoerrors.NewUtilError("failed normalizing edit payload", err)
+		}
+	}
+
+	if len(nodes) == 0 {
+		return "", scrapligoerrors.NewUtilError("edit payload is empty", nil)
+	}
+
+	payload, err := renderEditNodes(nodes)
+	if err != nil {
+		return "", scrapligoerrors.NewUtilError("invalid edit payload", err)
+	}
+
+	return payload, nil
+}
+
+// EditConfigValue is the same as EditConfig but marshals v (see MarshalEditPayload) into the
+// config payload rather than accepting a raw xml string.
+func (n *Netconf) EditConfigValue(
+	ctx context.Context,
+	v any,
+	options ...Option,
+) (*Result, error) {
+	config, err := MarshalEditPayload(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return n.EditConfig(ctx, config, options...)
+}
+
+// EditDataValue is the same as EditData but marshals v (see MarshalEditPayload) into the content
+// payload rather than accepting a raw xml string.
+func (n *Netconf) EditDataValue(
+	ctx context.Context,
+	v any,
+	options ...Option,
+) (*Result, error) {
+	content, err := MarshalEditPayload(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return n.EditData(ctx, content, options...)
+}
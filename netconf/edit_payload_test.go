@@ -0,0 +1,102 @@
+package netconf_test
+
+import (
+	"encoding/xml"
+	"testing"
+
+	scrapligonetconf "github.com/scrapli/scrapligo/v2/netconf"
+)
+
+type testEditInterface struct {
+	XMLName   xml.Name                   `xml:"interface"`
+	Operation scrapligonetconf.Operation `xml:"operation,attr,omitempty"`
+	Name      string                     `xml:"name"`
+	Enabled   *bool                      `xml:"enabled,omitempty"`
+}
+
+type testEditInterfaces struct {
+	XMLName    xml.Name            `xml:"urn:ietf:params:xml:ns:yang:ietf-interfaces interfaces"`
+	Interfaces []testEditInterface `xml:"interface"`
+}
+
+func TestMarshalEditPayload(t *testing.T) {
+	enabled := true
+
+	cases := map[string]struct {
+		value       any
+		expected    string
+		expectedErr bool
+	}{
+		"struct": {
+			value: testEditInterfaces{
+				Interfaces: []testEditInterface{
+					{Name: "eth0", Enabled: &enabled, Operation: scrapligonetconf.OperationMerge},
+					{Name: "eth1", Operation: scrapligonetconf.OperationDelete},
+				},
+			},
+			expected: `<interfaces xmlns="urn:ietf:params:xml:ns:yang:ietf-interfaces" xmlns:nc="urn:ietf:params:xml:ns:netconf:base:1.0">` +
+				`<interface nc:operation="merge"><name>eth0</name><enabled>true</enabled></interface>` +
+				`<interface nc:operation="delete"><name>eth1</name></interface>` +
+				`</interfaces>`,
+		},
+		"struct-no-operation": {
+			value: &testEditInterfaces{
+				Interfaces: []testEditInterface{{Name: "eth0"}},
+			},
+			expected: `<interfaces xmlns="urn:ietf:params:xml:ns:yang:ietf-interfaces">` +
+				`<interface><name>eth0</name></interface>` +
+				`</interfaces>`,
+		},
+		"struct-invalid-operation": {
+			value: testEditInterfaces{
+				Interfaces: []testEditInterface{{Name: "eth0", Operation: "frobnicate"}},
+			},
+			expectedErr: true,
+		},
+		"tree": {
+			value: func() *scrapligonetconf.EditNode {
+				root := &scrapligonetconf.EditNode{
+					Name:      "system",
+					Namespace: "urn:some:data",
+				}
+
+				root.AddChild("hostname", "my-router&co").Operation = scrapligonetconf.OperationReplace
+
+				ntp := root.AddChild("ntp", "")
+				ntp.Namespace = "urn:some:ntp"
+				ntp.Operation = scrapligonetconf.OperationRemove
+
+				return root
+			}(),
+			expected: `<system xmlns="urn:some:data" xmlns:nc="urn:ietf:params:xml:ns:netconf:base:1.0">` +
+				`<hostname nc:operation="replace">my-router&amp;co</hostname>` +
+				`<ntp xmlns="urn:some:ntp" nc:operation="remove"/>` +
+				`</system>`,
+		},
+		"tree-missing-name": {
+			value:       &scrapligonetconf.EditNode{Namespace: "urn:some:data"},
+			expectedErr: true,
+		},
+	}
+
+	for caseName, c := range cases {
+		t.Run(caseName, func(t *testing.T) {
+			actual, err := scrapligonetconf.MarshalEditPayload(c.value)
+			if c.expectedErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if actual != c.expected {
+				t.Fatalf("expected:\n%s\ngot:\n%s", c.expected, actual)
+			}
+		})
+	}
+}
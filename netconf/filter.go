@@ -0,0 +1,429 @@
+package netconf
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+)
+
+// Filter is implemented by the filter builders (SubtreeFilter and XPathFilter) and is accepted by
+// WithFilterBuilder.
+type Filter interface {
+	// Build validates the filter and renders it into a FilterSpec.
+	Build() (*FilterSpec, error)
+}
+
+// FilterSpec is a rendered filter -- the fields map directly to the WithFilter, WithFilterType,
+// WithFilterNamespacePrefix and WithFilterNamespace options.
+type FilterSpec struct {
+	Filter          string
+	Type            FilterType
+	NamespacePrefix string
+	Namespace       string
+}
+
+var (
+	yangIdentifierPatternInst     *regexp.Regexp //nolint: gochecknoglobals
+	yangIdentifierPatternInstOnce sync.Once      //nolint: gochecknoglobals
+)
+
+func yangIdentifierPattern() *regexp.Regexp {
+	yangIdentifierPatternInstOnce.Do(func() {
+		yangIdentifierPatternInst = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+	})
+
+	return yangIdentifierPatternInst
+}
+
+// FilterNode is a node in a SubtreeFilter. Per RFC 6241 section 6 a node is a containment node if
+// it has children, a content match node if it has a value, and a selection node otherwise.
+type FilterNode struct {
+	name      string
+	namespace string
+	value     *string
+	children  []*FilterNode
+}
+
+// Namespaced sets the namespace of the node (children inherit it unless they set their own), this
+// is required for top level nodes and for nodes in a different module than their parent (i.e.
+// augmentations). Returns the node to allow chaining.
+func (n *FilterNode) Namespaced(namespace string) *FilterNode {
+	n.namespace = namespace
+
+	return n
+}
+
+// Container adds a containment node to n and returns the newly created node.
+func (n *FilterNode) Container(name string) *FilterNode {
+	child := &FilterNode{name: name}
+
+	n.children = append(n.children, child)
+
+	return child
+}
+
+// Select adds a selection node to n for each name -- selection nodes select the node and all of
+// its descendants. Returns n to allow chaining.
+func (n *FilterNode) Select(names ...string) *FilterNode {
+	for _, name := range names {
+		n.children = append(n.children, &FilterNode{name: name})
+	}
+
+	return n
+}
+
+// Match adds a content match node to n -- only siblings of nodes whose value equals value are
+// selected. Returns n to allow chaining. The value must not be empty, an empty element is a
+// selection node (see Select) so the filter fails to build.
+func (n *FilterNode) Match(name, value string) *FilterNode {
+	n.children = append(n.children, &FilterNode{name: name, value: &value})
+
+	return n
+}
+
+func (n *FilterNode) validate(parentNamespace string) error {
+	if !yangIdentifierPattern().MatchString(n.name) {
+		return fmt.Errorf("invalid node name %q", n.name)
+	}
+
+	namespace := n.namespace
+	if namespace == "" {
+		namespace = parentNamespace
+	}
+
+	if namespace == "" {
+		return fmt.Errorf("node %q has no namespace", n.name)
+	}
+
+	if n.value != nil && *n.value == "" {
+		return fmt.Errorf(
+			"node %q has an empty content match value, which would render as a selection node",
+			n.name,
+		)
+	}
+
+	if n.value != nil && len(n.children) > 0 {
+		return fmt.Errorf("node %q cannot be both a content match and containment node", n.name)
+	}
+
+	for _, child := range n.children {
+		err := child.validate(namespace)
+		if err != nil {
+			return fmt.Errorf("%s/%w", n.name, err)
+		}
+	}
+
+	return nil
+}
+
+func (n *FilterNode) toEditNode() *EditNode {
+	node := &EditNode{
+		Name:      n.name,
+		Namespace: n.namespace,
+	}
+
+	if n.value != nil {
+		node.Value = *n.value
+	}
+
+	for _, child := range n.children {
+		node.Children = append(node.Children, child.toEditNode())
+	}
+
+	return node
+}
+
+// SubtreeFilter builds a subtree filter. For example, selecting the "enabled" and "type" leafs of
+// interface "eth0":
+//
+//	f := NewSubtreeFilter()
+//	f.Add("interfaces", "urn:ietf:params:xml:ns:yang:ietf-interfaces").
+//		Container("interface").
+//		Match("name", "eth0").
+//		Select("enabled", "type")
+type SubtreeFilter struct {
+	nodes []*FilterNode
+}
+
+// NewSubtreeFilter returns a new, empty, SubtreeFilter.
+func NewSubtreeFilter() *SubtreeFilter {
+	return &SubtreeFilter{}
+}
+
+// Add adds a top level node in the given namespace to the filter and returns the newly created
+// node. Filters may contain any number of top level nodes in any number of namespaces.
+func (f *SubtreeFilter) Add(name, namespace string) *FilterNode {
+	node := &FilterNode{name: name, namespace: namespace}
+
+	f.nodes = append(f.nodes, node)
+
+	return node
+}
+
+// Build validates and renders the filter. Namespaces are declared on the nodes themselves so the
+// resulting FilterSpec never sets a filter namespace.
+func (f *SubtreeFilter) Build() (*FilterSpec, error) {
+	if len(f.nodes) == 0 {
+		return nil, scrapligoerrors.NewUtilError("subtree filter has no nodes", nil)
+	}
+
+	editNodes := make([]*EditNode, len(f.nodes))
+
+	for idx, node := range f.nodes {
+		err := node.validate("")
+		if err != nil {
+			return nil, scrapligoerrors.NewUtilError("invalid subtree filter", err)
+		}
+
+		editNodes[idx] = node.toEditNode()
+	}
+
+	filter, err := renderEditNodes(editNodes)
+	if err != nil {
+		return nil, scrapligoerrors.NewUtilError("failed rendering subtree filter", err)
+	}
+
+	return &FilterSpec{
+		Filter: filter,
+		Type:   FilterTypeSubtree,
+	}, nil
+}
+
+var (
+	xpathPrefixedNamePatternInst     *regexp.Regexp //nolint: gochecknoglobals
+	xpathPrefixedNamePatternInstOnce sync.Once      //nolint: gochecknoglobals
+)
+
+// xpathPrefixedNamePattern matches prefixed name tests ("if:interface" or "if:*"), the leading
+// group ensures we don't match in the middle of a name or on the "::" of an axis specifier.
+func xpathPrefixedNamePattern() *regexp.Regexp {
+	xpathPrefixedNamePatternInstOnce.Do(func() {
+		xpathPrefixedNamePatternInst = regexp.MustCompile(
+			`(^|[^A-Za-z0-9_.:-])([A-Za-z_][A-Za-z0-9_.-]*):(\*|[A-Za-z_][A-Za-z0-9_.-]*)`,
+		)
+	})
+
+	return xpathPrefixedNamePatternInst
+}
+
+// XPathFilter builds an xpath filter with an associated prefix to namespace map.
+type XPathFilter struct {
+	expression string
+	namespaces map[string]string
+}
+
+// NewXPathFilter returns a new XPathFilter for the given expression.
+func NewXPathFilter(expression string) *XPathFilter {
+	return &XPathFilter{
+		expression: expression,
+		namespaces: map[string]string{},
+	}
+}
+
+// WithNamespace maps prefix to namespace for the filter expression. Returns the filter to allow
+// chaining.
+func (f *XPathFilter) WithNamespace(prefix, namespace string) *XPathFilter {
+	f.namespaces[prefix] = namespace
+
+	return f
+}
+
+// xpathUnquotedSegments calls fn for each segment of expression that is not within a string
+// literal, fn returns the (possibly rewritten) segment. Literals are passed through as is.
+func xpathUnquotedSegments(expression string, fn func(segment string) string) (string, error) {
+	var (
+		out   strings.Builder
+		quote byte
+		start int
+	)
+
+	for idx := 0; idx < len(expression); idx++ {
+		c := expression[idx]
+
+		switch {
+		case quote != 0:
+			if c == quote {
+				out.WriteString(expression[start : idx+1])
+
+				quote = 0
+				start = idx + 1
+			}
+		case c == '\'' || c == '"':
+			out.WriteString(fn(expression[start:idx]))
+
+			quote = c
+			start = idx
+		}
+	}
+
+	if quote != 0 {
+		return "", errors.New("unterminated string literal")
+	}
+
+	out.WriteString(fn(expression[start:]))
+
+	return out.String(), nil
+}
+
+func checkXPathBalanced(expression string) error {
+	var depth [2]int
+
+	// a close before its open (i.e. "][") is unbalanced whatever follows, including in later
+	// segments, so once that happens the depth is no longer tracked
+	var closedBeforeOpen bool
+
+	_, err := xpathUnquotedSegments(expression, func(segment string) string {
+		for _, c := range segment {
+			if closedBeforeOpen {
+				break
+			}
+
+			switch c {
+			case '[':
+				depth[0]++
+			case ']':
+				depth[0]--
+			case '(':
+				depth[1]++
+			case ')':
+				depth[1]--
+			}
+
+			closedBeforeOpen = depth[0] < 0 || depth[1] < 0
+		}
+
+		return segment
+	})
+	if err != nil {
+		return err
+	}
+
+	if closedBeforeOpen || depth[0] != 0 || depth[1] != 0 {
+		return errors.New("unbalanced brackets or parentheses")
+	}
+
+	return nil
+}
+
+func xpathLiteral(s string) (string, error) {
+	switch {
+	case !strings.Contains(s, "'"):
+		return "'" + s + "'", nil
+	case !strings.Contains(s, `"`):
+		return `"` + s + `"`, nil
+	default:
+		return "", fmt.Errorf("cannot quote %q as an xpath literal", s)
+	}
+}
+
+// rewritePrefixes replaces prefixed name tests with an equivalent wildcard + namespace-uri
+// predicate, e.g. "if:interface" becomes "*[local-name()='interface' and namespace-uri()='...']".
+// This is how filters with more than one namespace are sent since the netconf operations only
+// accept a single filter namespace declaration.
+func (f *XPathFilter) rewritePrefixes() (string, error) {
+	var rewriteErr error
+
+	rewritten, err := xpathUnquotedSegments(f.expression, func(segment string) string {
+		return xpathPrefixedNamePattern().ReplaceAllStringFunc(segment, func(match string) string {
+			parts := xpathPrefixedNamePattern().FindStringSubmatch(match)
+
+			namespace, err := xpathLiteral(f.namespaces[parts[2]])
+			if err != nil {
+				rewriteErr = err
+
+				return match
+			}
+
+			if parts[3] == "*" {
+				return fmt.Sprintf("%s*[namespace-uri()=%s]", parts[1], namespace)
+			}
+
+			return fmt.Sprintf(
+				"%s*[local-name()='%s' and namespace-uri()=%s]",
+				parts[1],
+				parts[3],
+				namespace,
+			)
+		})
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return rewritten, rewriteErr
+}
+
+func (f *XPathFilter) usedPrefixes() ([]string, error) {
+	seen := map[string]bool{}
+
+	var prefixes []string
+
+	_, err := xpathUnquotedSegments(f.expression, func(segment string) string {
+		for _, parts := range xpathPrefixedNamePattern().FindAllStringSubmatch(segment, -1) {
+			if !seen[parts[2]] {
+				seen[parts[2]] = true
+
+				prefixes = append(prefixes, parts[2])
+			}
+		}
+
+		return segment
+	})
+
+	sort.Strings(prefixes)
+
+	return prefixes, err
+}
+
+// Build validates and renders the filter. Every prefix used in the expression must be mapped to
+// a namespace. If the expression uses a single prefix the filter is sent as is with the prefix
+// declared via the filter namespace options, otherwise prefixed name tests are rewritten to
+// namespace-uri() predicates so no declarations are needed.
+func (f *XPathFilter) Build() (*FilterSpec, error) {
+	if strings.TrimSpace(f.expression) == "" {
+		return nil, scrapligoerrors.NewUtilError("xpath filter expression is empty", nil)
+	}
+
+	err := checkXPathBalanced(f.expression)
+	if err != nil {
+		return nil, scrapligoerrors.NewUtilError("invalid xpath filter", err)
+	}
+
+	prefixes, err := f.usedPrefixes()
+	if err != nil {
+		return nil, scrapligoerrors.NewUtilError("invalid xpath filter", err)
+	}
+
+	for _, prefix := range prefixes {
+		if _, ok := f.namespaces[prefix]; !ok {
+			return nil, scrapligoerrors.NewUtilError(
+				fmt.Sprintf("invalid xpath filter, prefix %q has no namespace", prefix),
+				nil,
+			)
+		}
+	}
+
+	switch len(prefixes) {
+	case 0:
+		return &FilterSpec{Filter: f.expression, Type: FilterTypeXpath}, nil
+	case 1:
+		return &FilterSpec{
+			Filter:          f.expression,
+			Type:            FilterTypeXpath,
+			NamespacePrefix: prefixes[0],
+			Namespace:       f.namespaces[prefixes[0]],
+		}, nil
+	default:
+		rewritten, err := f.rewritePrefixes()
+		if err != nil {
+			return nil, scrapligoerrors.NewUtilError("invalid xpath filter", err)
+		}
+
+		return &FilterSpec{Filter: rewritten, Type: FilterTypeXpath}, nil
+	}
+}
@@ -0,0 +1,201 @@
+package netconf_test
+
+import (
+	"testing"
+
+	scrapligonetconf "github.com/scrapli/scrapligo/v2/netconf"
+)
+
+const (
+	testFilterInterfacesNamespace = "urn:ietf:params:xml:ns:yang:ietf-interfaces"
+	testFilterIPNamespace         = "urn:ietf:params:xml:ns:yang:ietf-ip"
+)
+
+func TestSubtreeFilter(t *testing.T) {
+	cases := map[string]struct {
+		filter      func() *scrapligonetconf.SubtreeFilter
+		expected    string
+		expectedErr bool
+	}{
+		"containment-match-select": {
+			filter: func() *scrapligonetconf.SubtreeFilter {
+				f := scrapligonetconf.NewSubtreeFilter()
+				f.Add("interfaces", testFilterInterfacesNamespace).
+					Container("interface").
+					Match("name", "eth0").
+					Select("enabled", "type")
+
+				return f
+			},
+			expected: `<interfaces xmlns="urn:ietf:params:xml:ns:yang:ietf-interfaces">` +
+				`<interface><name>eth0</name><enabled/><type/></interface>` +
+				`</interfaces>`,
+		},
+		"multiple-namespaces": {
+			filter: func() *scrapligonetconf.SubtreeFilter {
+				f := scrapligonetconf.NewSubtreeFilter()
+				iface := f.Add("interfaces", testFilterInterfacesNamespace).Container("interface")
+				iface.Container("ipv4").Namespaced(testFilterIPNamespace).Select("address")
+
+				f.Add("system", "urn:ietf:params:xml:ns:yang:ietf-system").Select("hostname")
+
+				return f
+			},
+			expected: `<interfaces xmlns="urn:ietf:params:xml:ns:yang:ietf-interfaces">` +
+				`<interface><ipv4 xmlns="urn:ietf:params:xml:ns:yang:ietf-ip"><address/></ipv4></interface>` +
+				`</interfaces>` +
+				`<system xmlns="urn:ietf:params:xml:ns:yang:ietf-system"><hostname/></system>`,
+		},
+		"escaped-match": {
+			filter: func() *scrapligonetconf.SubtreeFilter {
+				f := scrapligonetconf.NewSubtreeFilter()
+				f.Add("system", "urn:some:data").Match("description", "a<b")
+
+				return f
+			},
+			expected: `<system xmlns="urn:some:data"><description>a&lt;b</description></system>`,
+		},
+		"empty": {
+			filter:      scrapligonetconf.NewSubtreeFilter,
+			expectedErr: true,
+		},
+		"missing-namespace": {
+			filter: func() *scrapligonetconf.SubtreeFilter {
+				f := scrapligonetconf.NewSubtreeFilter()
+				f.Add("interfaces", "")
+
+				return f
+			},
+			expectedErr: true,
+		},
+		"empty-match": {
+			filter: func() *scrapligonetconf.SubtreeFilter {
+				f := scrapligonetconf.NewSubtreeFilter()
+				f.Add("interfaces", testFilterInterfacesNamespace).Match("name", "")
+
+				return f
+			},
+			expectedErr: true,
+		},
+		"invalid-name": {
+			filter: func() *scrapligonetconf.SubtreeFilter {
+				f := scrapligonetconf.NewSubtreeFilter()
+				f.Add("interfaces", testFilterInterfacesNamespace).Select("bad name")
+
+				return f
+			},
+			expectedErr: true,
+		},
+	}
+
+	for caseName, c := range cases {
+		t.Run(caseName, func(t *testing.T) {
+			spec, err := c.filter().Build()
+			if c.expectedErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if spec.Type != scrapligonetconf.FilterTypeSubtree {
+				t.Fatalf("expected subtree filter type, got %d", spec.Type)
+			}
+
+			if spec.Filter != c.expected {
+				t.Fatalf("expected:\n%s\ngot:\n%s", c.expected, spec.Filter)
+			}
+		})
+	}
+}
+
+func TestXPathFilter(t *testing.T) {
+	cases := map[string]struct {
+		filter         *scrapligonetconf.XPathFilter
+		expected       string
+		expectedPrefix string
+		expectedErr    bool
+	}{
+		"no-prefix": {
+			filter:   scrapligonetconf.NewXPathFilter("/interfaces/interface[name='eth0']"),
+			expected: "/interfaces/interface[name='eth0']",
+		},
+		"single-prefix": {
+			filter: scrapligonetconf.NewXPathFilter("/if:interfaces/if:interface[if:name='a:b']").
+				WithNamespace("if", testFilterInterfacesNamespace),
+			expected:       "/if:interfaces/if:interface[if:name='a:b']",
+			expectedPrefix: "if",
+		},
+		"multiple-prefixes": {
+			filter: scrapligonetconf.NewXPathFilter("/if:interfaces/if:interface/ip:ipv4/ip:*").
+				WithNamespace("if", testFilterInterfacesNamespace).
+				WithNamespace("ip", testFilterIPNamespace),
+			expected: "/*[local-name()='interfaces' and namespace-uri()='urn:ietf:params:xml:ns:yang:ietf-interfaces']" +
+				"/*[local-name()='interface' and namespace-uri()='urn:ietf:params:xml:ns:yang:ietf-interfaces']" +
+				"/*[local-name()='ipv4' and namespace-uri()='urn:ietf:params:xml:ns:yang:ietf-ip']" +
+				"/*[namespace-uri()='urn:ietf:params:xml:ns:yang:ietf-ip']",
+		},
+		"axis-not-prefix": {
+			filter:   scrapligonetconf.NewXPathFilter("/interfaces/child::interface"),
+			expected: "/interfaces/child::interface",
+		},
+		"unknown-prefix": {
+			filter:      scrapligonetconf.NewXPathFilter("/if:interfaces"),
+			expectedErr: true,
+		},
+		"unbalanced": {
+			filter:      scrapligonetconf.NewXPathFilter("/interfaces/interface[name='eth0'"),
+			expectedErr: true,
+		},
+		"closed-before-open": {
+			filter:      scrapligonetconf.NewXPathFilter("/interfaces/interface]["),
+			expectedErr: true,
+		},
+		"closed-before-open-across-literal": {
+			filter:      scrapligonetconf.NewXPathFilter("/interfaces/interface]'eth0'["),
+			expectedErr: true,
+		},
+		"unterminated-literal": {
+			filter:      scrapligonetconf.NewXPathFilter("/interfaces/interface[name='eth0]"),
+			expectedErr: true,
+		},
+		"empty": {
+			filter:      scrapligonetconf.NewXPathFilter(" "),
+			expectedErr: true,
+		},
+	}
+
+	for caseName, c := range cases {
+		t.Run(caseName, func(t *testing.T) {
+			spec, err := c.filter.Build()
+			if c.expectedErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if spec.Type != scrapligonetconf.FilterTypeXpath {
+				t.Fatalf("expected xpath filter type, got %d", spec.Type)
+			}
+
+			if spec.Filter != c.expected {
+				t.Fatalf("expected:\n%s\ngot:\n%s", c.expected, spec.Filter)
+			}
+
+			if spec.NamespacePrefix != c.expectedPrefix {
+				t.Fatalf("expected prefix %q, got %q", c.expectedPrefix, spec.NamespacePrefix)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
 )
 
 func newGetOptions(options ...Option) *getOptions {
@@ -21,6 +22,7 @@ type getOptions struct {
 	filterType            *FilterType
 	filterNamespacePrefix string
 	filterNamespace       string
+	filterErr             error
 	defaultsType          *DefaultsType
 }
 
@@ -49,34 +51,60 @@ func (o *getOptions) getDefaultsType() *uint8 {
 //   - WithFilterType
 //   - WithFilterNamespacePrefix
 //   - WithFilterNamespace
+//   - WithFilterBuilder
 //   - WithDefaultsType
 func (n *Netconf) Get(
 	ctx context.Context,
 	options ...Option,
 ) (*Result, error) {
+	op, err := n.GetAsync(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return op.Wait(ctx)
+}
+
+// GetAsync is the async flavor of Get -- the rpc is submitted and an OperationHandle is returned
+// immediately, see Get for supported options.
+func (n *Netconf) GetAsync(
+	ctx context.Context,
+	options ...Option,
+) (*OperationHandle, error) {
 	if n.ptr == 0 {
 		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
 	}
 
-	cancel := false
+	loadedOptions := newGetOptions(options...)
 
-	var operationID uint32
+	if loadedOptions.filterErr != nil {
+		return nil, loadedOptions.filterErr
+	}
 
-	loadedOptions := newGetOptions(options...)
+	err := n.requireDefaultsType("get", loadedOptions.defaultsType)
+	if err != nil {
+		return nil, err
+	}
 
-	err := n.ffiMap.Netconf.Get(
-		n.ptr,
-		&operationID,
-		&cancel,
-		loadedOptions.filter,
-		loadedOptions.getFilterType(),
-		loadedOptions.filterNamespacePrefix,
-		loadedOptions.filterNamespace,
-		loadedOptions.getDefaultsType(),
+	op, err := n.submit(
+		ctx,
+		"get",
+		func(m *scrapligoffi.NetconfMapping, id *uint32, cancel *scrapligoffi.CancelFlag) error {
+			return m.Get(
+				n.ptr,
+				id,
+				cancel,
+				loadedOptions.filter,
+				loadedOptions.getFilterType(),
+				loadedOptions.filterNamespacePrefix,
+				loadedOptions.filterNamespace,
+				loadedOptions.getDefaultsType(),
+			)
+		},
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	return n.getResult(ctx, &cancel, operationID)
+	return op, nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
 )
 
 func newGetConfigOptions(options ...Option) *getConfigOptions {
@@ -22,6 +23,7 @@ type getConfigOptions struct {
 	filterType            *FilterType
 	filterNamespacePrefix string
 	filterNamespace       string
+	filterErr             error
 	defaultsType          *DefaultsType
 }
 
@@ -45,35 +47,66 @@ func (o *getConfigOptions) getDefaultsType() *uint8 { return nil }
 //   - WithFilterType
 //   - WithFilterNamespacePrefix
 //   - WithFilterNamespace
+//   - WithFilterBuilder
 //   - WithDefaultsType
 func (n *Netconf) GetConfig(
 	ctx context.Context,
 	options ...Option,
 ) (*Result, error) {
+	op, err := n.GetConfigAsync(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return op.Wait(ctx)
+}
+
+// GetConfigAsync is the async flavor of GetConfig -- the rpc is submitted and an OperationHandle is
+// returned immediately, see GetConfig for supported options.
+func (n *Netconf) GetConfigAsync(
+	ctx context.Context,
+	options ...Option,
+) (*OperationHandle, error) {
 	if n.ptr == 0 {
 		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
 	}
 
-	cancel := false
+	loadedOptions := newGetConfigOptions(options...)
+
+	if loadedOptions.filterErr != nil {
+		return nil, loadedOptions.filterErr
+	}
 
-	var operationID uint32
+	err := n.requireDatastore("get-config", loadedOptions.source)
+	if err != nil {
+		return nil, err
+	}
 
-	loadedOptions := newGetConfigOptions(options...)
+	err = n.requireDefaultsType("get-config", loadedOptions.defaultsType)
+	if err != nil {
+		return nil, err
+	}
 
-	err := n.ffiMap.Netconf.GetConfig(
-		n.ptr,
-		&operationID,
-		&cancel,
-		loadedOptions.getSource(),
-		loadedOptions.filter,
-		loadedOptions.getFilterType(),
-		loadedOptions.filterNamespacePrefix,
-		loadedOptions.filterNamespace,
-		loadedOptions.getDefaultsType(),
+	op, err := n.submit(
+		ctx,
+		"get-config",
+		func(m *scrapligoffi.NetconfMapping, id *uint32, cancel *scrapligoffi.CancelFlag) error {
+			return m.GetConfig(
+				n.ptr,
+				id,
+				cancel,
+				loadedOptions.getSource(),
+				loadedOptions.filter,
+				loadedOptions.getFilterType(),
+				loadedOptions.filterNamespacePrefix,
+				loadedOptions.filterNamespace,
+				loadedOptions.getDefaultsType(),
+			)
+		},
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	return n.getResult(ctx, &cancel, operationID)
+	return op, nil
 }
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
 	scrapligonetconf "github.com/scrapli/scrapligo/v2/netconf"
 )
 
@@ -61,3 +62,31 @@ func TestGetConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestGetConfigNilFilterBuilder(t *testing.T) {
+	testFixturePath, err := filepath.Abs("./fixtures/get-config-simple")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	n := getNetconf(t, testFixturePath)
+
+	_, err = n.Open(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_, _ = n.Close(ctx)
+	}()
+
+	for _, f := range []scrapligonetconf.Filter{nil, (*scrapligonetconf.SubtreeFilter)(nil)} {
+		_, err = n.GetConfig(ctx, scrapligonetconf.WithFilterBuilder(f))
+		if !scrapligoerrors.IsKind(err, scrapligoerrors.Options) {
+			t.Fatalf("expected options error for nil filter builder %#v, got %v", f, err)
+		}
+	}
+}
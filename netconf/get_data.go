@@ -4,6 +4,7 @@ import (
 	"context"
 
 	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
 )
 
 func newGetDataOptions(options ...Option) *getDataOptions {
@@ -22,6 +23,7 @@ type getDataOptions struct {
 	filterType            *FilterType
 	filterNamespacePrefix string
 	filterNamespace       string
+	filterErr             error
 	configFilter          *ConfigFilter
 	originFilters         string
 	maxDepth              uint32
@@ -76,12 +78,14 @@ func (o *getDataOptions) getDefaultsType() *uint8 {
 	return &v
 }
 
-// GetData executes a netconf get-data rpc. Supported options:
+// GetData executes a netconf get-data rpc. Fails fast (without sending the rpc) if the server did
+// not indicate nmda support. Supported options:
 //   - WithDatastore
 //   - WithFilter
 //   - WithFilterType
 //   - WithFilterNamespacePrefix
 //   - WithFilterNamespace
+//   - WithFilterBuilder
 //   - WithDefaultsType
 //   - WithConfigFilter
 //   - WithMaxDepth
@@ -91,34 +95,64 @@ func (n *Netconf) GetData(
 	ctx context.Context,
 	options ...Option,
 ) (*Result, error) {
+	op, err := n.GetDataAsync(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return op.Wait(ctx)
+}
+
+// GetDataAsync is the async flavor of GetData -- the rpc is submitted and an OperationHandle is
+// returned immediately, see GetData for supported options.
+func (n *Netconf) GetDataAsync(
+	ctx context.Context,
+	options ...Option,
+) (*OperationHandle, error) {
 	if n.ptr == 0 {
 		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
 	}
 
-	cancel := false
+	loadedOptions := newGetDataOptions(options...)
+
+	if loadedOptions.filterErr != nil {
+		return nil, loadedOptions.filterErr
+	}
 
-	var operationID uint32
+	err := n.requireNmda("get-data")
+	if err != nil {
+		return nil, err
+	}
 
-	loadedOptions := newGetDataOptions(options...)
+	err = n.requireDefaultsType("get-data", loadedOptions.defaultsType)
+	if err != nil {
+		return nil, err
+	}
 
-	err := n.ffiMap.Netconf.GetData(
-		n.ptr,
-		&operationID,
-		&cancel,
-		loadedOptions.getDatastore(),
-		loadedOptions.filter,
-		loadedOptions.getFilterType(),
-		loadedOptions.filterNamespacePrefix,
-		loadedOptions.filterNamespace,
-		loadedOptions.getConfigFilter(),
-		loadedOptions.originFilters,
-		loadedOptions.maxDepth,
-		loadedOptions.withOrigin,
-		loadedOptions.getDefaultsType(),
+	op, err := n.submit(
+		ctx,
+		"get-data",
+		func(m *scrapligoffi.NetconfMapping, id *uint32, cancel *scrapligoffi.CancelFlag) error {
+			return m.GetData(
+				n.ptr,
+				id,
+				cancel,
+				loadedOptions.getDatastore(),
+				loadedOptions.filter,
+				loadedOptions.getFilterType(),
+				loadedOptions.filterNamespacePrefix,
+				loadedOptions.filterNamespace,
+				loadedOptions.getConfigFilter(),
+				loadedOptions.originFilters,
+				loadedOptions.maxDepth,
+				loadedOptions.withOrigin,
+				loadedOptions.getDefaultsType(),
+			)
+		},
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	return n.getResult(ctx, &cancel, operationID)
+	return op, nil
 }
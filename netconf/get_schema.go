@@ -4,6 +4,7 @@ import (
 	"context"
 
 	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
 )
 
 func newGetSchemaOptions(options ...Option) *getSchemaOptions {
@@ -39,27 +40,44 @@ func (n *Netconf) GetSchema(
 	identifier string,
 	options ...Option,
 ) (*Result, error) {
-	if n.ptr == 0 {
-		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
+	op, err := n.GetSchemaAsync(ctx, identifier, options...)
+	if err != nil {
+		return nil, err
 	}
 
-	cancel := false
+	return op.Wait(ctx)
+}
 
-	var operationID uint32
+// GetSchemaAsync is the async flavor of GetSchema -- the rpc is submitted and an OperationHandle is
+// returned immediately, see GetSchema for supported options.
+func (n *Netconf) GetSchemaAsync(
+	ctx context.Context,
+	identifier string,
+	options ...Option,
+) (*OperationHandle, error) {
+	if n.ptr == 0 {
+		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
+	}
 
 	loadedOptions := newGetSchemaOptions(options...)
 
-	err := n.ffiMap.Netconf.GetSchema(
-		n.ptr,
-		&operationID,
-		&cancel,
-		identifier,
-		loadedOptions.version,
-		loadedOptions.getFormat(),
+	op, err := n.submit(
+		ctx,
+		"get-schema",
+		func(m *scrapligoffi.NetconfMapping, id *uint32, cancel *scrapligoffi.CancelFlag) error {
+			return m.GetSchema(
+				n.ptr,
+				id,
+				cancel,
+				identifier,
+				loadedOptions.version,
+				loadedOptions.getFormat(),
+			)
+		},
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	return n.getResult(ctx, &cancel, operationID)
+	return op, nil
 }
@@ -69,3 +69,43 @@ func TestGet(t *testing.T) {
 		})
 	}
 }
+
+// BenchmarkGet measures a get rpc round trip over the test transport, run with -benchmem to see
+// allocations per operation.
+func BenchmarkGet(b *testing.B) {
+	testFixturePath, err := filepath.Abs("./fixtures/get-simple")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		// each iteration needs a fresh netconf object since the test transport replays the
+		// fixture from the start, so open/close are excluded from the measurement
+		b.StopTimer()
+
+		n := getNetconf(b, testFixturePath)
+
+		_, err = n.Open(ctx)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.StartTimer()
+
+		_, err = n.Get(ctx)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.StopTimer()
+
+		_, _ = n.Close(ctx)
+
+		b.StartTimer()
+	}
+}
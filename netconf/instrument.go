@@ -0,0 +1,99 @@
+package netconf
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
+	scrapligometrics "github.com/scrapli/scrapligo/v2/metrics"
+)
+
+var errorTagPattern = regexp.MustCompile( //nolint: gochecknoglobals
+	`<(?:[\w-]+:)?error-tag>\s*([^<\s]+)\s*</`,
+)
+
+// rpcErrorTags returns the error-tag of each rpc-error in the rpc errors of a Result.
+func rpcErrorTags(rpcErrors []string) []string {
+	var tags []string
+
+	for _, rpcError := range rpcErrors {
+		for _, match := range errorTagPattern.FindAllStringSubmatch(rpcError, -1) {
+			tags = append(tags, match[1])
+		}
+	}
+
+	return tags
+}
+
+// startRPCSpan starts the span for the rpc of op as a child of whatever span is in ctx, the span
+// is ended by endRPCSpan once the rpc completes.
+func (n *Netconf) startRPCSpan(ctx context.Context, op *OperationHandle) {
+	_, op.span = n.options.StartSpan(
+		ctx,
+		"netconf."+op.rpc,
+		n.host,
+		op.submitted,
+		scrapligointernal.TraceAttributeNetconfRPC.String(op.rpc),
+	)
+}
+
+// endRPCSpan records the outcome of the rpc of op on its span and ends it.
+func (n *Netconf) endRPCSpan(op *OperationHandle, result *Result, err error) {
+	op.span.SetAttributes(scrapligointernal.TraceAttributeOperationID.Int64(int64(op.id.Load())))
+
+	if result != nil {
+		op.span.SetAttributes(n.options.TraceInput(result.Input)...)
+		op.span.SetAttributes(
+			scrapligointernal.TraceAttributeResultBytes.Int(len(result.ResultRaw)),
+			scrapligointernal.TraceAttributeFailed.Bool(result.Failed),
+		)
+
+		tags := rpcErrorTags(result.Errors)
+		if len(tags) > 0 {
+			op.span.SetAttributes(
+				scrapligointernal.TraceAttributeNetconfErrorTags.StringSlice(tags),
+			)
+		}
+	}
+
+	op.span.End(err)
+}
+
+// observeRPC reports the (completed) rpc of op to the metrics recorder.
+func (n *Netconf) observeRPC(op *OperationHandle, result *Result, err error) {
+	if n.options.Metrics == nil {
+		return
+	}
+
+	observed := &scrapligometrics.Operation{
+		Driver:   scrapligometrics.Netconf,
+		Host:     n.host,
+		Port:     n.options.Port,
+		Name:     op.rpc,
+		Duration: time.Since(op.submitted),
+		Err:      err,
+	}
+
+	if result != nil {
+		observed.Duration = result.EndTime.Sub(result.StartTime)
+		observed.InputBytes = len(result.Input)
+		observed.ResultBytes = len(result.ResultRaw)
+		observed.Failed = result.Failed
+		observed.RPCErrorTags = rpcErrorTags(result.Errors)
+	}
+
+	n.options.Metrics.ObserveOperation(observed)
+}
+
+func (n *Netconf) sessionOpened() {
+	if n.options.Metrics != nil {
+		n.options.Metrics.SessionOpened(scrapligometrics.Netconf, n.host)
+	}
+}
+
+func (n *Netconf) sessionClosed() {
+	if n.options.Metrics != nil {
+		n.options.Metrics.SessionClosed(scrapligometrics.Netconf, n.host)
+	}
+}
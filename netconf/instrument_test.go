@@ -0,0 +1,185 @@
+package netconf_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
+	scrapligometrics "github.com/scrapli/scrapligo/v2/metrics"
+	scrapligooptions "github.com/scrapli/scrapligo/v2/options"
+	scrapligotracingotel "github.com/scrapli/scrapligo/v2/tracing/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type testRecorder struct {
+	observe func(op *scrapligometrics.Operation)
+}
+
+func (r *testRecorder) ObserveOperation(op *scrapligometrics.Operation) {
+	r.observe(op)
+}
+
+func (r *testRecorder) SessionOpened(_ scrapligometrics.DriverKind, _ string) {}
+
+func (r *testRecorder) SessionClosed(_ scrapligometrics.DriverKind, _ string) {}
+
+func TestInstrumentation(t *testing.T) {
+	testFixturePath, err := filepath.Abs("./fixtures/lock-simple")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	ctx, parent := provider.Tracer("test").Start(ctx, "parent")
+
+	var (
+		observedLock sync.Mutex
+		observed     []string
+	)
+
+	recorder := &testRecorder{
+		observe: func(op *scrapligometrics.Operation) {
+			observedLock.Lock()
+			defer observedLock.Unlock()
+
+			observed = append(observed, op.Name)
+		},
+	}
+
+	n := getNetconf(
+		t,
+		testFixturePath,
+		scrapligooptions.WithTracer(scrapligotracingotel.NewTracer(provider)),
+		scrapligooptions.WithMetricsRecorder(recorder),
+	)
+
+	_, err = n.Open(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = n.Lock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = n.Unlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = n.Close(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parent.End()
+
+	observedLock.Lock()
+	defer observedLock.Unlock()
+
+	if strings.Join(observed, ",") != "open,lock,unlock,close" {
+		t.Fatalf("expected open, lock, unlock and close to be observed, got %v", observed)
+	}
+
+	spans := exporter.GetSpans().Snapshots()
+
+	expectedNames := []string{
+		"netconf.open",
+		"netconf.lock",
+		"netconf.unlock",
+		"netconf.close",
+		"parent",
+	}
+
+	if len(spans) != len(expectedNames) {
+		t.Fatalf("expected %d spans, got %d", len(expectedNames), len(spans))
+	}
+
+	for idx, span := range spans {
+		if span.Name() != expectedNames[idx] {
+			t.Fatalf("expected span %d to be %q, got %q", idx, expectedNames[idx], span.Name())
+		}
+
+		if span.Name() == "parent" {
+			continue
+		}
+
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Fatalf("expected span %q to be a child of the callers span", span.Name())
+		}
+
+		var input string
+
+		for _, kv := range span.Attributes() {
+			if kv.Key == attribute.Key(scrapligointernal.TraceAttributeInput) {
+				input = kv.Value.AsString()
+			}
+		}
+
+		if span.Name() == "netconf.lock" && input == "" {
+			t.Fatal("expected lock span to record the rpc input")
+		}
+	}
+}
+
+func TestInstrumentationAsyncNotWaited(t *testing.T) {
+	testFixturePath, err := filepath.Abs("./fixtures/lock-simple")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	n := getNetconf(
+		t,
+		testFixturePath,
+		scrapligooptions.WithTracer(scrapligotracingotel.NewTracer(provider)),
+	)
+
+	_, err = n.Open(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_, _ = n.Close(ctx)
+	}()
+
+	op, err := n.LockAsync(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-op.Done():
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for lock to complete")
+	}
+
+	// the span ends when the rpc completes, whether or not anyone ever waits on it
+	var names []string
+
+	for _, span := range exporter.GetSpans().Snapshots() {
+		names = append(names, span.Name())
+	}
+
+	if strings.Join(names, ",") != "netconf.open,netconf.lock" {
+		t.Fatalf("expected open and lock spans, got %v", names)
+	}
+}
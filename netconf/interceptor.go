@@ -0,0 +1,226 @@
+package netconf
+
+import (
+	"context"
+	"time"
+
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
+	scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
+	scrapligogobackend "github.com/scrapli/scrapligo/v2/internal/gobackend"
+	scrapligometrics "github.com/scrapli/scrapligo/v2/metrics"
+	scrapligooptions "github.com/scrapli/scrapligo/v2/options"
+	scrapligopolicy "github.com/scrapli/scrapligo/v2/policy"
+)
+
+// RPC describes a netconf rpc as it passes through the interceptor chain.
+type RPC struct {
+	// Name is the name of the rpc, i.e. "get-config", "edit-config", "raw-rpc" for RawRPC.
+	Name string
+	Host string
+	Port uint16
+	// Payload is the rendered content of the rpc element (the rpc element itself, message-id and
+	// framing are added on submission). Interceptors may modify the payload before invoking the
+	// rest of the chain, a modified payload is submitted as a raw rpc. The payload is always what
+	// is sent, regardless of the backend in use.
+	Payload string
+}
+
+// RPCInvoker executes an rpc -- the next interceptor in the chain, or, at the end of the chain,
+// submits the rpc and waits for the reply.
+type RPCInvoker func(ctx context.Context, rpc *RPC) (*Result, error)
+
+// Interceptor wraps the execution of netconf rpcs. An interceptor can inspect (and modify) the rpc
+// before calling next, inspect (and modify) the Result/error next returned, or not call next at all
+// to short-circuit the rpc -- in which case nothing is sent to the server and whatever the
+// interceptor returns is the result of the rpc. Interceptors execute in the order given. Open and
+// Close are not passed through the chain.
+type Interceptor func(ctx context.Context, rpc *RPC, next RPCInvoker) (*Result, error)
+
+// WithInterceptors appends the interceptors to the interceptor chain of the Netconf object.
+func WithInterceptors(interceptors ...Interceptor) scrapligooptions.Option {
+	return func(o *scrapligointernal.Options) error {
+		for _, interceptor := range interceptors {
+			o.Netconf.Interceptors = append(o.Netconf.Interceptors, interceptor)
+		}
+
+		return nil
+	}
+}
+
+// loadInterceptors builds the interceptor chain from the options, the options can only hold the
+// interceptors as "any" since they live below this package.
+func (n *Netconf) loadInterceptors() {
+	for _, interceptor := range n.options.Netconf.Interceptors {
+		i, ok := interceptor.(Interceptor)
+		if ok {
+			n.interceptors = append(n.interceptors, i)
+		}
+	}
+}
+
+// intercepts returns true if the rpc is passed through the interceptor chain. The end of the chain
+// is also where the policy (if any) is checked, dry-run rpcs are recorded, results are redacted and
+// rpcs are audited, so rpcs go through the "chain" when any of those are configured too.
+func (n *Netconf) intercepts(rpc string) bool {
+	intercepted := len(n.interceptors) > 0 ||
+		n.options.Policy != nil ||
+		n.options.DryRun.Plan != nil ||
+		n.options.Audit != nil ||
+		n.options.Redaction.Results
+
+	if !intercepted {
+		return false
+	}
+
+	return rpc != scrapligometrics.OperationOpen && rpc != scrapligometrics.OperationClose
+}
+
+// intercept renders the rpc submitted by f and passes it through the interceptor chain in its own
+// goroutine, op is completed with whatever the chain returns. libscrapli can only render an rpc by
+// sending it, so rpcs are rendered by the go backend -- when libscrapli is the active backend the
+// rendered payload is always submitted as a raw rpc so that the payload the interceptors, policy
+// and audit saw is exactly what is sent rather than libscrapli's own rendering of the rpc.
+func (n *Netconf) intercept(ctx context.Context, op *OperationHandle, f submitFunc) error {
+	rendered, err := scrapligogobackend.RenderNetconfRPC(f)
+	if err != nil {
+		return err
+	}
+
+	rendersOwnRPCs := n.options.Backend == scrapligointernal.BackendKindGo
+
+	invoke := RPCInvoker(func(ctx context.Context, rpc *RPC) (*Result, error) {
+		submit := f
+
+		if rpc.Payload != rendered.Body || !rendersOwnRPCs {
+			submit = func(
+				m *scrapligoffi.NetconfMapping,
+				id *uint32,
+				cancel *scrapligoffi.CancelFlag,
+			) error {
+				return m.RawRPC(
+					n.ptr,
+					id,
+					cancel,
+					rpc.Payload,
+					rendered.BaseNamespacePrefix,
+					rendered.ExtraNamespaces,
+				)
+			}
+		}
+
+		return n.invoke(ctx, op, rpc, submit)
+	})
+
+	for idx := len(n.interceptors) - 1; idx >= 0; idx-- {
+		interceptor, next := n.interceptors[idx], invoke
+
+		invoke = func(ctx context.Context, rpc *RPC) (*Result, error) {
+			return interceptor(ctx, rpc, next)
+		}
+	}
+
+	rpc := &RPC{
+		Name:    op.rpc,
+		Host:    n.host,
+		Port:    n.options.Port,
+		Payload: rendered.Body,
+	}
+
+	chainCtx, cancel := context.WithCancelCause(ctx)
+
+	op.cancelChain = cancel
+
+	go func() {
+		defer cancel(nil)
+
+		op.complete(invoke(chainCtx, rpc))
+	}()
+
+	return nil
+}
+
+// invoke is the end of the interceptor chain, it executes the rpc, redacts the result (if
+// configured) and audits it.
+func (n *Netconf) invoke(
+	ctx context.Context,
+	op *OperationHandle,
+	rpc *RPC,
+	f submitFunc,
+) (*Result, error) {
+	start := time.Now()
+
+	result, err := n.execute(ctx, op, rpc, f)
+
+	if result != nil && n.options.Redaction.Results {
+		redactor := n.options.GetRedactor()
+
+		result.Input = redactor.Redact(result.Input)
+		result.Result = redactor.Redact(result.Result)
+		result.ResultRaw = []byte(redactor.Redact(string(result.ResultRaw)))
+	}
+
+	n.audit(ctx, start, rpc, result, err)
+
+	return result, err
+}
+
+// execute checks the rpc against the policy (if any), records it in dry-run mode, or submits the
+// rpc via f and waits for the dispatcher to deliver the result.
+func (n *Netconf) execute(
+	ctx context.Context,
+	op *OperationHandle,
+	rpc *RPC,
+	f submitFunc,
+) (*Result, error) {
+	if n.ptr == 0 {
+		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
+	}
+
+	if n.options.Policy != nil {
+		err := n.options.Policy.Evaluate(&scrapligopolicy.Subject{
+			Host:  rpc.Host,
+			RPC:   rpc.Name,
+			Input: rpc.Payload,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if n.dryRuns(rpc.Name) {
+		return n.dryRun(rpc), nil
+	}
+
+	if ctx.Err() != nil {
+		return nil, context.Cause(ctx)
+	}
+
+	delivered := make(chan struct{})
+
+	var (
+		result *Result
+		err    error
+	)
+
+	op.deliver = func(r *Result, e error) {
+		result, err = r, e
+
+		close(delivered)
+	}
+
+	submitErr := n.register(op, f)
+	if submitErr != nil {
+		return nil, submitErr
+	}
+
+	select {
+	case <-delivered:
+	case <-ctx.Done():
+		op.cancelWithErr(context.Cause(ctx))
+
+		<-delivered
+	}
+
+	return result, err
+}
@@ -0,0 +1,89 @@
+package netconf_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	scrapligonetconf "github.com/scrapli/scrapligo/v2/netconf"
+)
+
+func TestInterceptors(t *testing.T) {
+	testFixturePath, err := filepath.Abs("./fixtures/lock-simple")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	var intercepted []string
+
+	n := getNetconf(
+		t,
+		testFixturePath,
+		scrapligonetconf.WithInterceptors(
+			func(
+				ctx context.Context,
+				rpc *scrapligonetconf.RPC,
+				next scrapligonetconf.RPCInvoker,
+			) (*scrapligonetconf.Result, error) {
+				intercepted = append(intercepted, rpc.Name+" "+rpc.Payload)
+
+				return next(ctx, rpc)
+			},
+			func(
+				ctx context.Context,
+				rpc *scrapligonetconf.RPC,
+				next scrapligonetconf.RPCInvoker,
+			) (*scrapligonetconf.Result, error) {
+				if rpc.Name != "get" {
+					return next(ctx, rpc)
+				}
+
+				return &scrapligonetconf.Result{Host: rpc.Host, Result: "short-circuited"}, nil
+			},
+		),
+	)
+
+	_, err = n.Open(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_, _ = n.Close(ctx)
+	}()
+
+	_, err = n.Lock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := n.Get(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Result != "short-circuited" {
+		t.Fatalf("expected short-circuited get result, got %q", r.Result)
+	}
+
+	_, err = n.Unlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(intercepted) != 3 {
+		t.Fatalf("expected 3 intercepted rpcs, got %d: %v", len(intercepted), intercepted)
+	}
+
+	for idx, expected := range []string{"lock <lock>", "get <get", "unlock <unlock>"} {
+		if !strings.HasPrefix(intercepted[idx], expected) {
+			t.Fatalf("expected intercepted rpc %d to start with %q, got %q",
+				idx, expected, intercepted[idx])
+		}
+	}
+}
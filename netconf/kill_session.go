@@ -4,6 +4,7 @@ import (
 	"context"
 
 	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
 )
 
 // KillSession executes a netconf kill session rpc.
@@ -11,23 +12,39 @@ func (n *Netconf) KillSession(
 	ctx context.Context,
 	sessionID uint64,
 ) (*Result, error) {
-	if n.ptr == 0 {
-		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
+	op, err := n.KillSessionAsync(ctx, sessionID)
+	if err != nil {
+		return nil, err
 	}
 
-	cancel := false
+	return op.Wait(ctx)
+}
 
-	var operationID uint32
+// KillSessionAsync is the async flavor of KillSession -- the rpc is submitted and an
+// OperationHandle is returned immediately, see KillSession for supported options.
+func (n *Netconf) KillSessionAsync(
+	ctx context.Context,
+	sessionID uint64,
+) (*OperationHandle, error) {
+	if n.ptr == 0 {
+		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
+	}
 
-	err := n.ffiMap.Netconf.KillSession(
-		n.ptr,
-		&operationID,
-		&cancel,
-		sessionID,
+	op, err := n.submit(
+		ctx,
+		"kill-session",
+		func(m *scrapligoffi.NetconfMapping, id *uint32, cancel *scrapligoffi.CancelFlag) error {
+			return m.KillSession(
+				n.ptr,
+				id,
+				cancel,
+				sessionID,
+			)
+		},
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	return n.getResult(ctx, &cancel, operationID)
+	return op, nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
 )
 
 func newLockOptions(options ...Option) *lockOptions {
@@ -36,25 +37,46 @@ func (n *Netconf) Lock(
 	ctx context.Context,
 	options ...Option,
 ) (*Result, error) {
-	if n.ptr == 0 {
-		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
+	op, err := n.LockAsync(ctx, options...)
+	if err != nil {
+		return nil, err
 	}
 
-	cancel := false
+	return op.Wait(ctx)
+}
 
-	var operationID uint32
+// LockAsync is the async flavor of Lock -- the rpc is submitted and an OperationHandle is returned
+// immediately, see Lock for supported options.
+func (n *Netconf) LockAsync(
+	ctx context.Context,
+	options ...Option,
+) (*OperationHandle, error) {
+	if n.ptr == 0 {
+		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
+	}
 
 	loadedOptions := newLockOptions(options...)
 
-	err := n.ffiMap.Netconf.Lock(
-		n.ptr,
-		&operationID,
-		&cancel,
-		loadedOptions.getTarget(),
+	err := n.requireDatastore("lock", loadedOptions.target)
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := n.submit(
+		ctx,
+		"lock",
+		func(m *scrapligoffi.NetconfMapping, id *uint32, cancel *scrapligoffi.CancelFlag) error {
+			return m.Lock(
+				n.ptr,
+				id,
+				cancel,
+				loadedOptions.getTarget(),
+			)
+		},
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	return n.getResult(ctx, &cancel, operationID)
+	return op, nil
 }
@@ -2,17 +2,15 @@ package netconf
 
 import (
 	"context"
-	"errors"
-	"fmt"
 	"sync"
 
-	scrapligoconstants "github.com/scrapli/scrapligo/v2/constants"
 	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
 	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
 	scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
+	scrapligogobackend "github.com/scrapli/scrapligo/v2/internal/gobackend"
 	scrapligologging "github.com/scrapli/scrapligo/v2/logging"
+	scrapligometrics "github.com/scrapli/scrapligo/v2/metrics"
 	scrapligooptions "github.com/scrapli/scrapligo/v2/options"
-	"golang.org/x/sys/unix"
 )
 
 func newCloseOptions(options ...Option) *closeOptions {
@@ -35,10 +33,21 @@ type Netconf struct {
 	ptr      uintptr
 	userData uintptr
 	pollFd   int
+	readyFd  *scrapligointernal.ReadyFd
 	ffiMap   *scrapligoffi.Mapping
 	host     string
 	options  *scrapligointernal.Options
 	l        *scrapligologging.AnyLogger
+
+	capabilitiesLock sync.Mutex
+	capabilities     *Capabilities
+
+	operationsLock sync.Mutex
+	operations     []*OperationHandle
+	dispatching    bool
+	dispatcherWg   sync.WaitGroup
+
+	interceptors []Interceptor
 }
 
 // NewNetconf returns a new instance of Netconf setup with the given options.
@@ -46,20 +55,14 @@ func NewNetconf(
 	host string,
 	opts ...scrapligooptions.Option,
 ) (*Netconf, error) {
-	ffiMap, err := scrapligoffi.GetMapping()
-	if err != nil {
-		return nil, err
-	}
-
 	n := &Netconf{
 		userData: scrapligointernal.GetUserDataDispatcherr().Register(),
-		ffiMap:   ffiMap,
 		host:     host,
 		options:  scrapligointernal.NewOptions(),
 	}
 
 	for _, opt := range opts {
-		err = opt(n.options)
+		err := opt(n.options)
 		if err != nil {
 			return nil, scrapligoerrors.NewOptionsError("failed applying option", err)
 		}
@@ -71,6 +74,30 @@ func NewNetconf(
 		n.options.Port = 830
 	}
 
+	// the mapping depends on the options -- the go backend binds the mapping to this driver's
+	// options rather than loading libscrapli
+	ffiMap, err := scrapligogobackend.GetMapping(n.options)
+	if err != nil {
+		return nil, err
+	}
+
+	n.ffiMap = ffiMap
+
+	n.loadInterceptors()
+
+	if n.options.Netconf.CapabilitiesCallback != nil {
+		// when the user provides a capabilities callback we get handed the server hello directly,
+		// so we snag the capabilities from there rather than trying to find them in the open
+		// result
+		userCapabilitiesCallback := n.options.Netconf.CapabilitiesCallback
+
+		n.options.Netconf.CapabilitiesCallback = func(serverHello string) string {
+			n.setServerCapabilities(parseServerHello([]byte(serverHello)))
+
+			return userCapabilitiesCallback(serverHello)
+		}
+	}
+
 	return n, nil
 }
 
@@ -118,7 +145,13 @@ func (n *Netconf) GetOptions() (string, error) {
 // Open opens the driver object. This method spawns the underlying zig driver which the Netconf
 // object then holds a pointer to. All Netconf operations operate against this pointer (though
 // this is transparent to the user).
+// If credential providers are set (see options.WithCredentialProvider) the credential sets they
+// supply are tried in order until one authenticates.
 func (n *Netconf) Open(ctx context.Context) (*Result, error) {
+	return scrapligointernal.OpenWithCredentials(ctx, n.options, n.host, n.open)
+}
+
+func (n *Netconf) open(ctx context.Context) (*Result, error) {
 	// ensure we dealloc if something happens, otherwise users calls to defer close would not be
 	// super handy
 	cleanup := true
@@ -132,6 +165,8 @@ func (n *Netconf) Open(ctx context.Context) (*Result, error) {
 		scrapligointernal.GetRecorderDispatcher().Deregister(n.userData)
 		scrapligointernal.GetNetconfCapabiltiesDispatcher().Deregister(n.userData)
 
+		n.abandonOperations()
+
 		n.ffiMap.Shared.Free(n.ptr)
 
 		n.ptr = 0
@@ -159,22 +194,35 @@ func (n *Netconf) Open(ctx context.Context) (*Result, error) {
 		return nil, scrapligoerrors.NewFfiError("failed to allocate netconf", nil)
 	}
 
-	cancel := false
-
-	var operationID uint32
+	n.readyFd, err = scrapligointernal.NewReadyFd(n.pollFd)
+	if err != nil {
+		return nil, scrapligoerrors.NewFfiError("failed to allocate netconf", err)
+	}
 
-	err = n.ffiMap.Netconf.Open(n.ptr, &operationID, &cancel)
+	op, err := n.submit(
+		ctx,
+		scrapligometrics.OperationOpen,
+		func(m *scrapligoffi.NetconfMapping, id *uint32, cancel *scrapligoffi.CancelFlag) error {
+			return m.Open(n.ptr, id, cancel)
+		},
+	)
 	if err != nil {
 		return nil, err
 	}
 
-	result, err := n.getResult(ctx, &cancel, operationID)
+	result, err := op.Wait(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	if n.getServerCapabilities() == nil {
+		n.setServerCapabilities(parseServerHello(result.ResultRaw))
+	}
+
 	cleanup = false
 
+	n.sessionOpened()
+
 	return result, nil
 }
 
@@ -185,27 +233,35 @@ func (n *Netconf) Close(ctx context.Context, options ...Option) (*Result, error)
 	}
 
 	defer func() {
+		n.sessionClosed()
+
 		scrapligointernal.GetLoggerDispatcher().Deregister(n.userData)
 		scrapligointernal.GetRecorderDispatcher().Deregister(n.userData)
 		scrapligointernal.GetNetconfCapabiltiesDispatcher().Deregister(n.userData)
 
+		// any operations still in flight will never complete once the driver is gone, so fail
+		// them and make sure the dispatcher is done with the poll fd before we free things
+		n.abandonOperations()
+
 		n.ffiMap.Shared.Free(n.ptr)
 
 		n.ptr = 0
 	}()
 
-	cancel := false
-
-	var operationID uint32
-
 	loadedOptions := newCloseOptions(options...)
 
-	err := n.ffiMap.Netconf.Close(n.ptr, &operationID, &cancel, loadedOptions.force)
+	op, err := n.submit(
+		ctx,
+		scrapligometrics.OperationClose,
+		func(m *scrapligoffi.NetconfMapping, id *uint32, cancel *scrapligoffi.CancelFlag) error {
+			return m.Close(n.ptr, id, cancel, loadedOptions.force)
+		},
+	)
 	if err != nil {
 		return nil, err
 	}
 
-	return n.getResult(ctx, &cancel, operationID)
+	return op.Wait(ctx)
 }
 
 // GetSessionID returns the session-id as parsed during the capabilities exchange -- if we for some
@@ -306,166 +362,3 @@ func (n *Netconf) GetNextSubscription(subscriptionID uint64) (string, error) {
 
 	return string(sub), nil
 }
-
-func (n *Netconf) getResult( //nolint: funlen,gocyclo
-	ctx context.Context,
-	cancel *bool,
-	operationID uint32,
-) (*Result, error) {
-	done := make(chan struct{}, 1)
-	defer close(done)
-
-	cancelLock := &sync.Mutex{}
-
-	go func() {
-		select {
-		case <-ctx.Done():
-			cancelLock.Lock()
-			defer cancelLock.Unlock()
-
-			*cancel = true
-
-			return
-		case <-done:
-			return
-		}
-	}()
-
-	var _n int
-
-	pollFds := []unix.PollFd{{Fd: int32(n.pollFd), Events: unix.POLLIN}} //nolint: gosec
-
-	for {
-		if ctx.Err() != nil {
-			cancelLock.Lock()
-
-			*cancel = true
-
-			cancelLock.Unlock()
-
-			return nil, ctx.Err()
-		}
-
-		pollFds[0].Revents = 0
-
-		var err error
-
-		_n, err = unix.Poll(pollFds, scrapligoconstants.ReadyFDPollTimeoutMs)
-		if err != nil {
-			if errors.Is(err, unix.EINTR) {
-				// python automagically handles interrupts i guess go doesnt, so just act like
-				// we do on the python side when polling the wakeup fd
-				continue
-			}
-
-			return nil, scrapligoerrors.NewFfiError("waiting on operation ready signal", err)
-		}
-
-		if _n > 0 {
-			if pollFds[0].Revents&unix.POLLNVAL != 0 {
-				return nil, scrapligoerrors.NewFfiError(
-					"waiting on operation ready signal",
-					unix.EBADF,
-				)
-			}
-
-			break
-		}
-	}
-
-	var out [1]byte
-
-	for {
-		_, err := unix.Read(n.pollFd, out[:])
-		if err == nil {
-			break
-		}
-
-		if errors.Is(err, unix.EINTR) {
-			// same as loop above -- retry on interrupts
-			continue
-		}
-
-		return nil, scrapligoerrors.NewFfiError("draining operation ready signal", err)
-	}
-
-	var (
-		inputSize       uintptr
-		resultRawSize   uintptr
-		resultSize      uintptr
-		rpcWarningsSize uintptr
-		rpcErrorsSize   uintptr
-		errSize         uintptr
-		lastErrStrSize  uintptr
-	)
-
-	err := n.ffiMap.Netconf.FetchOperationSizes(
-		n.ptr,
-		operationID,
-		&inputSize,
-		&resultRawSize,
-		&resultSize,
-		&rpcWarningsSize,
-		&rpcErrorsSize,
-		&errSize,
-		&lastErrStrSize,
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	var resultStartTime, resultEndTime uint64
-
-	input := make([]byte, inputSize)
-
-	resultRaw := make([]byte, resultRawSize)
-
-	result := make([]byte, resultSize)
-
-	rpcWarnings := make([]byte, rpcWarningsSize)
-
-	rpcErrors := make([]byte, rpcErrorsSize)
-
-	errString := make([]byte, errSize)
-
-	lastErrString := make([]byte, lastErrStrSize)
-
-	err = n.ffiMap.Netconf.FetchOperation(
-		n.ptr,
-		operationID,
-		&resultStartTime,
-		&resultEndTime,
-		&input,
-		&resultRaw,
-		&result,
-		&rpcWarnings,
-		&rpcErrors,
-		&errString,
-		&lastErrString,
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	if errSize != 0 {
-		outErrMsg := string(errString)
-
-		if lastErrStrSize > 0 {
-			outErrMsg += fmt.Sprintf(": %s", string(lastErrString))
-		}
-
-		return nil, scrapligoerrors.NewFfiError(outErrMsg, ctx.Err())
-	}
-
-	return NewResult(
-		string(input),
-		n.host,
-		n.options.Port,
-		resultStartTime,
-		resultEndTime,
-		resultRaw,
-		string(result),
-		rpcWarnings,
-		rpcErrors,
-	), nil
-}
@@ -0,0 +1,405 @@
+package netconf
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
+	scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
+	scrapligotracing "github.com/scrapli/scrapligo/v2/tracing"
+)
+
+// submitFunc submits an rpc via the given mapping, see submit.
+type submitFunc func(
+	m *scrapligoffi.NetconfMapping,
+	id *uint32,
+	cancel *scrapligoffi.CancelFlag,
+) error
+
+// OperationHandle is a handle to a submitted (in flight) netconf rpc. Any number of operations may
+// be in flight on a single Netconf object at once -- libscrapli tracks each by its operation id
+// and the Netconf object dispatches results to the appropriate handle as they become ready.
+// Cancelling the context the rpc was submitted with cancels the operation (as Cancel does).
+type OperationHandle struct {
+	// id is set on submission to libscrapli, for intercepted rpcs that happens asynchronously
+	id atomic.Uint32
+
+	// cancel is read by libscrapli for the duration of the operation, so it lives with the handle
+	// rather than on the stack of the submitting function
+	cancel     *scrapligoffi.CancelFlag
+	cancelLock sync.Mutex
+	cancelErr  error
+	// cancelChain cancels the interceptor chain (if any) the rpc is executing in
+	cancelChain context.CancelCauseFunc
+	// stopCancelOnDone stops cancelling the operation when the submitting context is done
+	stopCancelOnDone func() bool
+
+	done   chan struct{}
+	result *Result
+	err    error
+	// deliver, if set, receives the result from the dispatcher rather than the handle being
+	// completed directly -- the end of the interceptor chain waits on it
+	deliver func(result *Result, err error)
+
+	// the span for the rpc starts at submission and ends on completion, metrics are reported
+	// once the result is delivered
+	netconf   *Netconf
+	rpc       string
+	submitted time.Time
+	span      scrapligotracing.Span
+}
+
+// ID returns the libscrapli operation id of the operation.
+func (o *OperationHandle) ID() uint32 {
+	return o.id.Load()
+}
+
+// Done returns a channel that is closed when the operation is complete.
+func (o *OperationHandle) Done() <-chan struct{} {
+	return o.done
+}
+
+// Cancel requests cancellation of the operation. Cancellation is asynchronous -- Wait returns
+// once libscrapli has stopped processing the operation.
+func (o *OperationHandle) Cancel() {
+	o.cancelWithErr(context.Canceled)
+}
+
+func (o *OperationHandle) cancelWithErr(err error) {
+	o.cancelLock.Lock()
+	defer o.cancelLock.Unlock()
+
+	if o.cancelErr == nil {
+		o.cancelErr = err
+	}
+
+	o.cancel.Cancel()
+
+	if o.cancelChain != nil {
+		o.cancelChain(err)
+	}
+}
+
+// Wait blocks until the operation completes or the context is cancelled, in the latter case the
+// operation is cancelled and the context error is returned immediately.
+func (o *OperationHandle) Wait(ctx context.Context) (*Result, error) {
+	select {
+	case <-o.done:
+		return o.result, o.err
+	default:
+	}
+
+	select {
+	case <-o.done:
+		return o.result, o.err
+	case <-ctx.Done():
+		o.cancelWithErr(ctx.Err())
+
+		return nil, ctx.Err()
+	}
+}
+
+func (o *OperationHandle) complete(result *Result, err error) {
+	o.stopCancelOnDone()
+
+	o.netconf.endRPCSpan(o, result, err)
+
+	o.result = result
+	o.err = err
+
+	close(o.done)
+}
+
+// deliverResult hands the result of the submitted rpc to the handle, metrics are reported here
+// so that only rpcs that actually reached the server are counted.
+func (o *OperationHandle) deliverResult(result *Result, err error) {
+	o.netconf.observeRPC(o, result, err)
+
+	if o.deliver != nil {
+		o.deliver(result, err)
+
+		return
+	}
+
+	o.complete(result, err)
+}
+
+// submit submits an operation (the rpc named rpc) via the given func and returns its handle. If
+// the Netconf object has interceptors the rpc is passed through the interceptor chain first, with
+// ctx being the context the chain executes in. The (tracing) span for the rpc is a child of the
+// span in ctx, and the operation is cancelled if ctx is done before it completes.
+func (n *Netconf) submit(
+	ctx context.Context,
+	rpc string,
+	f submitFunc,
+) (*OperationHandle, error) {
+	op := &OperationHandle{
+		cancel:    scrapligoffi.NewCancelFlag(),
+		done:      make(chan struct{}),
+		netconf:   n,
+		rpc:       rpc,
+		submitted: time.Now(),
+	}
+
+	// set before submission so it is always set by the time the operation completes
+	op.stopCancelOnDone = context.AfterFunc(ctx, func() {
+		op.cancelWithErr(ctx.Err())
+	})
+
+	n.startRPCSpan(ctx, op)
+
+	var err error
+
+	if n.intercepts(rpc) {
+		err = n.intercept(ctx, op, f)
+	} else {
+		err = n.register(op, f)
+	}
+
+	if err != nil {
+		op.stopCancelOnDone()
+
+		n.endRPCSpan(op, nil, err)
+
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// register submits the rpc via f and registers the handle with the dispatcher. The operations
+// lock is held for the duration of the submission so the dispatcher can never see a ready signal
+// for an operation it does not yet know about.
+func (n *Netconf) register(op *OperationHandle, f submitFunc) error {
+	n.operationsLock.Lock()
+	defer n.operationsLock.Unlock()
+
+	var operationID uint32
+
+	err := f(&n.ffiMap.Netconf, &operationID, op.cancel)
+	if err != nil {
+		return err
+	}
+
+	op.id.Store(operationID)
+
+	n.operations = append(n.operations, op)
+
+	if !n.dispatching {
+		n.dispatching = true
+
+		n.dispatcherWg.Add(1)
+
+		go n.dispatch(n.readyFd)
+	}
+
+	return nil
+}
+
+// dispatch waits on the ready fd for operations to become ready and hands results to the waiting
+// handles. It runs only while there are operations in flight.
+func (n *Netconf) dispatch(readyFd *scrapligointernal.ReadyFd) {
+	defer n.dispatcherWg.Done()
+
+	for {
+		n.operationsLock.Lock()
+
+		if len(n.operations) == 0 {
+			n.dispatching = false
+
+			n.operationsLock.Unlock()
+
+			return
+		}
+
+		n.operationsLock.Unlock()
+
+		err := readyFd.Wait()
+		if err != nil {
+			n.failOperations(scrapligoerrors.NewFfiError("waiting on operation ready signal", err))
+
+			return
+		}
+
+		n.completeOperation()
+	}
+}
+
+// completeOperation is called for each ready signal. The signal does not say which operation it
+// is for, and nothing guarantees libscrapli completes pipelined operations in submission order,
+// so the in flight operations are checked by their operation id (oldest first) -- the first whose
+// results can be fetched is the one that completed and gets its results. If no operation has
+// results the oldest is completed with the error fetching them.
+func (n *Netconf) completeOperation() {
+	n.operationsLock.Lock()
+	defer n.operationsLock.Unlock()
+
+	if len(n.operations) == 0 {
+		// a signal for an operation that was already abandoned, nothing left to deliver to
+		return
+	}
+
+	var oldestErr error
+
+	for idx, op := range n.operations {
+		sizes, err := n.fetchOperationSizes(op.id.Load())
+		if err != nil {
+			if idx == 0 {
+				oldestErr = err
+			}
+
+			continue
+		}
+
+		n.operations = slices.Delete(n.operations, idx, idx+1)
+
+		op.deliverResult(n.fetchOperation(op, sizes))
+
+		return
+	}
+
+	op := n.operations[0]
+
+	n.operations = n.operations[1:]
+
+	op.deliverResult(nil, oldestErr)
+}
+
+// failOperations completes all in flight operations with the given error and stops dispatching.
+func (n *Netconf) failOperations(err error) {
+	n.operationsLock.Lock()
+	defer n.operationsLock.Unlock()
+
+	for _, op := range n.operations {
+		op.deliverResult(nil, err)
+	}
+
+	n.operations = nil
+	n.dispatching = false
+}
+
+// abandonOperations fails any in flight operations, closes the ready fd and waits for the
+// dispatcher to exit, this must happen before the driver (and therefore the poll fd) is freed.
+func (n *Netconf) abandonOperations() {
+	n.operationsLock.Lock()
+
+	for _, op := range n.operations {
+		op.deliverResult(nil, scrapligoerrors.NewFfiError("driver closed", nil))
+	}
+
+	n.operations = nil
+
+	if n.readyFd != nil {
+		// unblocks the dispatcher if it is waiting, nothing useful to do with a close error
+		_ = n.readyFd.Close()
+
+		n.readyFd = nil
+	}
+
+	n.operationsLock.Unlock()
+
+	n.dispatcherWg.Wait()
+}
+
+type operationSizes struct {
+	input       uintptr
+	resultRaw   uintptr
+	result      uintptr
+	rpcWarnings uintptr
+	rpcErrors   uintptr
+	err         uintptr
+	lastErrStr  uintptr
+}
+
+func (n *Netconf) fetchOperationSizes(operationID uint32) (*operationSizes, error) {
+	sizes := &operationSizes{}
+
+	err := n.ffiMap.Netconf.FetchOperationSizes(
+		n.ptr,
+		operationID,
+		&sizes.input,
+		&sizes.resultRaw,
+		&sizes.result,
+		&sizes.rpcWarnings,
+		&sizes.rpcErrors,
+		&sizes.err,
+		&sizes.lastErrStr,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return sizes, nil
+}
+
+func (n *Netconf) fetchOperation(op *OperationHandle, sizes *operationSizes) (*Result, error) {
+	var resultStartTime, resultEndTime uint64
+
+	// input, result and raw result end up owned by the Result so they are allocated exactly once
+	// here (and never copied again), the rest is scratch space so comes from the pool
+	input := make([]byte, sizes.input)
+
+	resultRaw := make([]byte, sizes.resultRaw)
+
+	result := make([]byte, sizes.result)
+
+	rpcWarnings := scrapligointernal.GetBuffer(sizes.rpcWarnings)
+	defer scrapligointernal.PutBuffer(rpcWarnings)
+
+	rpcErrors := scrapligointernal.GetBuffer(sizes.rpcErrors)
+	defer scrapligointernal.PutBuffer(rpcErrors)
+
+	errString := scrapligointernal.GetBuffer(sizes.err)
+	defer scrapligointernal.PutBuffer(errString)
+
+	lastErrString := scrapligointernal.GetBuffer(sizes.lastErrStr)
+	defer scrapligointernal.PutBuffer(lastErrString)
+
+	err := n.ffiMap.Netconf.FetchOperation(
+		n.ptr,
+		op.id.Load(),
+		&resultStartTime,
+		&resultEndTime,
+		&input,
+		&resultRaw,
+		&result,
+		rpcWarnings,
+		rpcErrors,
+		errString,
+		lastErrString,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if sizes.err != 0 {
+		outErrMsg := string(*errString)
+
+		if sizes.lastErrStr > 0 {
+			outErrMsg += fmt.Sprintf(": %s", string(*lastErrString))
+		}
+
+		op.cancelLock.Lock()
+		cancelErr := op.cancelErr
+		op.cancelLock.Unlock()
+
+		return nil, scrapligoerrors.NewOperationError(outErrMsg, cancelErr)
+	}
+
+	return NewResult(
+		scrapligointernal.BytesToString(input),
+		n.host,
+		n.options.Port,
+		resultStartTime,
+		resultEndTime,
+		resultRaw,
+		scrapligointernal.BytesToString(result),
+		*rpcWarnings,
+		*rpcErrors,
+	), nil
+}
@@ -0,0 +1,182 @@
+package netconf_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	mathrand "math/rand"
+	"path/filepath"
+	"testing"
+	"time"
+
+	scrapligonetconf "github.com/scrapli/scrapligo/v2/netconf"
+)
+
+func TestOperationAsync(t *testing.T) {
+	parentName := "operation-async"
+
+	cases := map[string]struct {
+		description string
+	}{
+		"simple": {
+			description: "simple - submit multiple rpcs before waiting on any of them",
+		},
+	}
+
+	for caseName := range cases {
+		testName := fmt.Sprintf("%s-%s", parentName, caseName)
+
+		t.Run(testName, func(t *testing.T) {
+			t.Logf("%s: starting", testName)
+
+			testFixturePath, err := filepath.Abs(fmt.Sprintf("./fixtures/%s", testName))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			defer cancel()
+
+			n := getNetconf(t, testFixturePath)
+
+			_, err = n.Open(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer func() {
+				_, _ = n.Close(ctx)
+			}()
+
+			lockOp, err := n.LockAsync(
+				ctx,
+				scrapligonetconf.WithTargetType(scrapligonetconf.DatastoreTypeCandidate),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			unlockOp, err := n.UnlockAsync(
+				ctx,
+				scrapligonetconf.WithTargetType(scrapligonetconf.DatastoreTypeCandidate),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if lockOp.ID() == unlockOp.ID() {
+				t.Fatal("expected distinct operation ids")
+			}
+
+			// wait in reverse order of submission, results must still land on the right handle
+			for _, op := range []*scrapligonetconf.OperationHandle{unlockOp, lockOp} {
+				r, err := op.Wait(ctx)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if r.Failed {
+					t.Fatalf("expected rpc to succeed, got errors %v", r.Errors)
+				}
+
+				select {
+				case <-op.Done():
+				default:
+					t.Fatal("expected done channel to be closed after wait")
+				}
+			}
+		})
+	}
+}
+
+// TestOperationCancelRandom cancels in flight rpcs at random points, this is primarily useful with
+// the race detector enabled (make test-race).
+func TestOperationCancelRandom(t *testing.T) {
+	// enough iterations for the race detector to have a fair shot at catching something
+	iterations := 100
+	if testing.Short() {
+		iterations = 10
+	}
+
+	testFixturePath, err := filepath.Abs("./fixtures/operation-async-simple")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for idx := range iterations {
+		t.Run(fmt.Sprintf("iteration-%d", idx), func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			defer cancel()
+
+			n := getNetconf(t, testFixturePath)
+
+			_, err := n.Open(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer func() {
+				_, _ = n.Close(ctx)
+			}()
+
+			op, err := n.LockAsync(
+				ctx,
+				scrapligonetconf.WithTargetType(scrapligonetconf.DatastoreTypeCandidate),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			time.Sleep(time.Duration(mathrand.Intn(5_000)) * time.Microsecond) //nolint: gosec
+
+			op.Cancel()
+
+			_, err = op.Wait(ctx)
+			if err != nil && !errors.Is(err, context.Canceled) {
+				t.Fatalf("expected nil or cancelled error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestOperationAsyncContextCancel(t *testing.T) {
+	testFixturePath, err := filepath.Abs("./fixtures/operation-async-simple")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	n := getNetconf(t, testFixturePath)
+
+	_, err = n.Open(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_, _ = n.Close(ctx)
+	}()
+
+	opCtx, opCancel := context.WithCancel(ctx)
+
+	op, err := n.LockAsync(
+		opCtx,
+		scrapligonetconf.WithTargetType(scrapligonetconf.DatastoreTypeCandidate),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// cancelling the submitting context cancels the operation w/out anyone calling Cancel, so it
+	// completes (cancelled or not, depending on how far it got) while waiting on a live context
+	opCancel()
+
+	_, err = op.Wait(ctx)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected nil or cancelled error, got %v", err)
+	}
+}
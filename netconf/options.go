@@ -1,5 +1,11 @@
 package netconf
 
+import (
+	"reflect"
+
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+)
+
 const (
 	// DefaultStreamValue is the default value for "stream" field on create/establish/modify
 	// subscription rpcs.
@@ -42,6 +48,8 @@ func WithDatastore(t DatastoreType) Option {
 			to.target = &t
 		case *unlockOptions:
 			to.target = &t
+		case *transactionOptions:
+			to.target = &t
 		}
 	}
 }
@@ -82,6 +90,8 @@ func WithTargetType(t DatastoreType) Option {
 			to.target = &t
 		case *unlockOptions:
 			to.target = &t
+		case *transactionOptions:
+			to.target = &t
 		}
 	}
 }
@@ -142,7 +152,55 @@ func WithFilterNamespace(s string) Option {
 	}
 }
 
-// WithDefaultsType apply a defaults type for the rpc.
+func buildFilter(f Filter) (*FilterSpec, error) {
+	if f == nil {
+		return nil, scrapligoerrors.NewOptionsError("filter builder is nil", nil)
+	}
+
+	// a typed nil (i.e. a nil *SubtreeFilter) is just as nil but would panic on Build
+	if v := reflect.ValueOf(f); v.Kind() == reflect.Pointer && v.IsNil() {
+		return nil, scrapligoerrors.NewOptionsError("filter builder is nil", nil)
+	}
+
+	return f.Build()
+}
+
+// WithFilterBuilder applies a filter built with a filter builder (see SubtreeFilter and
+// XPathFilter), setting the filter, filter type and filter namespace (if any) for the rpc. If the
+// filter fails to build (or is nil) the rpc fails with the build error without being sent. This
+// option overrides any other filter options that precede it.
+func WithFilterBuilder(f Filter) Option {
+	spec, err := buildFilter(f)
+	if err != nil {
+		spec = &FilterSpec{}
+	}
+
+	return func(o any) {
+		switch to := o.(type) {
+		case *getConfigOptions:
+			to.filter = spec.Filter
+			to.filterType = &spec.Type
+			to.filterNamespacePrefix = spec.NamespacePrefix
+			to.filterNamespace = spec.Namespace
+			to.filterErr = err
+		case *getOptions:
+			to.filter = spec.Filter
+			to.filterType = &spec.Type
+			to.filterNamespacePrefix = spec.NamespacePrefix
+			to.filterNamespace = spec.Namespace
+			to.filterErr = err
+		case *getDataOptions:
+			to.filter = spec.Filter
+			to.filterType = &spec.Type
+			to.filterNamespacePrefix = spec.NamespacePrefix
+			to.filterNamespace = spec.Namespace
+			to.filterErr = err
+		}
+	}
+}
+
+// WithDefaultsType apply a defaults type for the rpc. If the server did not advertise the
+// :with-defaults capability with the given mode the rpc will fail without being sent.
 func WithDefaultsType(t DefaultsType) Option {
 	return func(o any) {
 		switch to := o.(type) {
@@ -224,6 +282,8 @@ func WithDefaultOperation(t DefaultOperation) Option {
 			to.defaultOperation = &t
 		case *editDataOptions:
 			to.defaultOperation = &t
+		case *transactionOptions:
+			to.defaultOperation = &t
 		}
 	}
 }
@@ -234,6 +294,8 @@ func WithTestOption(t TestOption) Option {
 		switch to := o.(type) {
 		case *editConfigOptions:
 			to.testOption = &t
+		case *transactionOptions:
+			to.testOption = &t
 		}
 	}
 }
@@ -244,6 +306,8 @@ func WithErrorOption(t ErrorOption) Option {
 		switch to := o.(type) {
 		case *editConfigOptions:
 			to.errorOption = &t
+		case *transactionOptions:
+			to.errorOption = &t
 		}
 	}
 }
@@ -257,3 +321,48 @@ func WithPersistID(s string) Option {
 		}
 	}
 }
+
+// WithSkipValidate skips the validate step of a transaction even if the server advertises the
+// validate capability.
+func WithSkipValidate() Option {
+	return func(o any) {
+		switch to := o.(type) {
+		case *transactionOptions:
+			to.skipValidate = true
+		}
+	}
+}
+
+// WithSchemaProgressCallback sets a callback invoked after each schema is processed when
+// downloading schemas. Schemas are downloaded sequentially, so the callback is invoked from the
+// goroutine calling DownloadSchemas.
+func WithSchemaProgressCallback(f func(progress SchemaDownloadProgress)) Option {
+	return func(o any) {
+		switch to := o.(type) {
+		case *downloadSchemasOptions:
+			to.progressCallback = f
+		}
+	}
+}
+
+// WithSchemaOverwrite causes schema downloads to overwrite existing files rather than skipping
+// them.
+func WithSchemaOverwrite() Option {
+	return func(o any) {
+		switch to := o.(type) {
+		case *downloadSchemasOptions:
+			to.overwrite = true
+		}
+	}
+}
+
+// WithDiffListKeys sets the key leaf names for lists (by list element name) when diffing, i.e.
+// {"interface": {"name"}, "unit": {"name", "vlan-id"}}.
+func WithDiffListKeys(keys map[string][]string) Option {
+	return func(o any) {
+		switch to := o.(type) {
+		case *diffOptions:
+			to.listKeys = keys
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package netconf_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligooptions "github.com/scrapli/scrapligo/v2/options"
+	scrapligopolicy "github.com/scrapli/scrapligo/v2/policy"
+)
+
+func TestPolicy(t *testing.T) {
+	testFixturePath, err := filepath.Abs("./fixtures/lock-simple")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	policy, err := scrapligopolicy.NewPolicy(
+		scrapligopolicy.Allow,
+		scrapligopolicy.Rule{
+			Name:    "no-delete-config",
+			Action:  scrapligopolicy.Deny,
+			Pattern: `.*`,
+			RPCs:    []string{"delete-config"},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := getNetconf(t, testFixturePath, scrapligooptions.WithPolicy(policy))
+
+	_, err = n.Open(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_, _ = n.Close(ctx)
+	}()
+
+	_, err = n.DeleteConfig(ctx)
+	if !errors.Is(err, scrapligoerrors.ErrPolicyDenied) {
+		t.Fatalf("expected ErrPolicyDenied, got %v", err)
+	}
+
+	_, err = n.Lock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = n.Unlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
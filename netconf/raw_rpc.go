@@ -7,6 +7,7 @@ import (
 
 	scrapligoconstants "github.com/scrapli/scrapligo/v2/constants"
 	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
 )
 
 func newRawRPCOptions(options ...Option) *rawRPCOptions {
@@ -40,27 +41,44 @@ func (n *Netconf) RawRPC(
 	payload string,
 	options ...Option,
 ) (*Result, error) {
-	if n.ptr == 0 {
-		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
+	op, err := n.RawRPCAsync(ctx, payload, options...)
+	if err != nil {
+		return nil, err
 	}
 
-	cancel := false
+	return op.Wait(ctx)
+}
 
-	var operationID uint32
+// RawRPCAsync is the async flavor of RawRPC -- the rpc is submitted and an OperationHandle is
+// returned immediately, see RawRPC for supported options.
+func (n *Netconf) RawRPCAsync(
+	ctx context.Context,
+	payload string,
+	options ...Option,
+) (*OperationHandle, error) {
+	if n.ptr == 0 {
+		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
+	}
 
 	loadedOptions := newRawRPCOptions(options...)
 
-	err := n.ffiMap.Netconf.RawRPC(
-		n.ptr,
-		&operationID,
-		&cancel,
-		payload,
-		loadedOptions.baseNamespacePrefix,
-		loadedOptions.extraNamespacesToFFI(),
+	op, err := n.submit(
+		ctx,
+		"raw-rpc",
+		func(m *scrapligoffi.NetconfMapping, id *uint32, cancel *scrapligoffi.CancelFlag) error {
+			return m.RawRPC(
+				n.ptr,
+				id,
+				cancel,
+				payload,
+				loadedOptions.baseNamespacePrefix,
+				loadedOptions.extraNamespacesToFFI(),
+			)
+		},
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	return n.getResult(ctx, &cancel, operationID)
+	return op, nil
 }
@@ -0,0 +1,195 @@
+package netconf_test
+
+import (
+	"encoding/xml"
+	"slices"
+	"testing"
+
+	scrapligonetconf "github.com/scrapli/scrapligo/v2/netconf"
+)
+
+const (
+	testInterfacesReply = `<rpc-reply xmlns="urn:ietf:params:xml:ns:netconf:base:1.0" message-id="101">
+  <data>
+    <interfaces xmlns="urn:ietf:params:xml:ns:yang:ietf-interfaces">
+      <interface>
+        <name>eth0</name>
+        <enabled>true</enabled>
+        <type xmlns:ianaift="urn:ietf:params:xml:ns:yang:iana-if-type">ianaift:ethernetCsmacd</type>
+      </interface>
+      <interface>
+        <name>eth1</name>
+        <enabled>false</enabled>
+      </interface>
+    </interfaces>
+    <system xmlns="urn:ietf:params:xml:ns:yang:ietf-system">
+      <hostname>router1</hostname>
+    </system>
+  </data>
+</rpc-reply>`
+	testRawRPCReply = `<rpc-reply xmlns="urn:ietf:params:xml:ns:netconf:base:1.0" message-id="101">
+  <result xmlns="urn:example:ping" status="ok">5 packets transmitted</result>
+</rpc-reply>`
+	testInterfacesNamespace = "urn:ietf:params:xml:ns:yang:ietf-interfaces"
+)
+
+type testInterface struct {
+	Name    string `xml:"name"`
+	Enabled bool   `xml:"enabled"`
+}
+
+type testInterfaces struct {
+	XMLName    xml.Name        `xml:"urn:ietf:params:xml:ns:yang:ietf-interfaces interfaces"`
+	Interfaces []testInterface `xml:"interface"`
+}
+
+func TestResultUnmarshal(t *testing.T) {
+	r := &scrapligonetconf.Result{Result: testInterfacesReply}
+
+	interfaces := &testInterfaces{}
+
+	err := r.Unmarshal(interfaces)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []testInterface{{Name: "eth0", Enabled: true}, {Name: "eth1"}}
+
+	if !slices.Equal(interfaces.Interfaces, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, interfaces.Interfaces)
+	}
+
+	data := &struct {
+		Hostname string `xml:"system>hostname"`
+	}{}
+
+	err = r.Unmarshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if data.Hostname != "router1" {
+		t.Fatalf("expected hostname 'router1', got %q", data.Hostname)
+	}
+
+	missing := &struct {
+		XMLName xml.Name `xml:"urn:example:nope interfaces"`
+	}{}
+
+	if r.Unmarshal(missing) == nil {
+		t.Fatal("expected error unmarshalling element with mismatched namespace")
+	}
+
+	rawRPC := &struct {
+		XMLName xml.Name `xml:"result"`
+		Status  string   `xml:"status,attr"`
+		Value   string   `xml:",chardata"`
+	}{}
+
+	err = (&scrapligonetconf.Result{Result: testRawRPCReply}).Unmarshal(rawRPC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rawRPC.Status != "ok" || rawRPC.Value != "5 packets transmitted" {
+		t.Fatalf("unexpected raw rpc payload %+v", rawRPC)
+	}
+
+	failed := &scrapligonetconf.Result{Result: testInterfacesReply, Failed: true}
+
+	if failed.Unmarshal(interfaces) == nil {
+		t.Fatal("expected error unmarshalling failed result")
+	}
+}
+
+func TestResultQuery(t *testing.T) {
+	r := &scrapligonetconf.Result{Result: testInterfacesReply}
+
+	cases := map[string]struct {
+		expression  string
+		namespaces  map[string]string
+		expected    []string
+		expectedErr bool
+	}{
+		"child-steps": {
+			expression: "/interfaces/interface/name",
+			expected:   []string{"eth0", "eth1"},
+		},
+		"relative": {
+			expression: "system/hostname",
+			expected:   []string{"router1"},
+		},
+		"descendant": {
+			expression: "//name",
+			expected:   []string{"eth0", "eth1"},
+		},
+		"prefixed": {
+			expression: "/if:interfaces/if:interface/if:name",
+			namespaces: map[string]string{"if": testInterfacesNamespace},
+			expected:   []string{"eth0", "eth1"},
+		},
+		"prefixed-mismatch": {
+			expression: "/if:system/if:hostname",
+			namespaces: map[string]string{"if": testInterfacesNamespace},
+			expected:   nil,
+		},
+		"child-predicate": {
+			expression: "/interfaces/interface[enabled='false']/name",
+			expected:   []string{"eth1"},
+		},
+		"position-predicate": {
+			expression: "/interfaces/interface[2]/name",
+			expected:   []string{"eth1"},
+		},
+		"self-predicate": {
+			expression: "//name[.=\"eth0\"]",
+			expected:   []string{"eth0"},
+		},
+		"wildcard": {
+			expression: "/*/hostname",
+			expected:   []string{"router1"},
+		},
+		"text": {
+			expression: "/interfaces/interface[name='eth0']/type/text()",
+			expected:   []string{"ianaift:ethernetCsmacd"},
+		},
+		"unknown-prefix": {
+			expression:  "/foo:interfaces",
+			expectedErr: true,
+		},
+		"unbalanced": {
+			expression:  "/interfaces/interface[name='eth0'",
+			expectedErr: true,
+		},
+	}
+
+	for caseName, c := range cases {
+		t.Run(caseName, func(t *testing.T) {
+			actual, err := r.Query(c.expression, c.namespaces)
+			if c.expectedErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !slices.Equal(actual, c.expected) {
+				t.Fatalf("expected %v, got %v", c.expected, actual)
+			}
+		})
+	}
+
+	attrs, err := (&scrapligonetconf.Result{Result: testRawRPCReply}).Query("/result/@status", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(attrs, []string{"ok"}) {
+		t.Fatalf("expected attribute value 'ok', got %v", attrs)
+	}
+}
@@ -0,0 +1,190 @@
+package netconf
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+)
+
+const (
+	rpcReplyElementName = "rpc-reply"
+	dataElementName     = "data"
+)
+
+// Unmarshal decodes the body of the result into v. The rpc-reply (and data, if present) wrappers
+// are stripped so v only needs to model the payload the user actually cares about -- this works
+// for Get, GetConfig and GetData results (where the payload is wrapped in data) as well as RawRPC
+// results (where the payload is the rpc-reply body).
+//
+// If v is a pointer to a struct with an XMLName field, v is decoded from the first element of the
+// body matching that name (and namespace, if the tag includes one); otherwise the body container
+// itself is decoded into v such that fields of v map to the top level elements of the body.
+func (r *Result) Unmarshal(v any) error {
+	if r.Failed {
+		return scrapligoerrors.NewNetconfError(
+			"cannot unmarshal result, rpc-reply contained errors",
+			errors.New(strings.TrimSpace(strings.Join(r.Errors, "\n"))),
+		)
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return scrapligoerrors.NewUtilError("unmarshal target must be a non-nil pointer", nil)
+	}
+
+	err := unmarshalBody(r.Result, expectedXMLName(rv.Type().Elem()), v)
+	if err != nil {
+		return scrapligoerrors.NewUtilError("failed unmarshalling result", err)
+	}
+
+	return nil
+}
+
+// expectedXMLName returns the name from the XMLName field tag of t (if t is a struct that has one).
+func expectedXMLName(t reflect.Type) *xml.Name {
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	f, ok := t.FieldByName("XMLName")
+	if !ok || f.Type != reflect.TypeFor[xml.Name]() {
+		return nil
+	}
+
+	tag, _, _ := strings.Cut(f.Tag.Get("xml"), ",")
+
+	parts := strings.Fields(tag)
+
+	switch len(parts) {
+	case 1:
+		return &xml.Name{Local: parts[0]}
+	case 2: //nolint: mnd
+		return &xml.Name{Space: parts[0], Local: parts[1]}
+	default:
+		return nil
+	}
+}
+
+func xmlNameMatches(expected *xml.Name, actual xml.Name) bool {
+	if expected == nil {
+		return true
+	}
+
+	if expected.Space != "" && expected.Space != actual.Space {
+		return false
+	}
+
+	return expected.Local == actual.Local
+}
+
+func nextStartElement(d *xml.Decoder) (*xml.StartElement, error) {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch typedTok := tok.(type) {
+		case xml.StartElement:
+			return &typedTok, nil
+		case xml.EndElement:
+			// end of the current parent, no more children
+			return nil, io.EOF
+		}
+	}
+}
+
+// decodeMatchingChild decodes the first child of the element the decoder is positioned in that
+// matches expected into v, returns true if a matching child was found.
+func decodeMatchingChild(d *xml.Decoder, expected *xml.Name, v any) (bool, error) {
+	for {
+		child, err := nextStartElement(d)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return false, nil
+			}
+
+			return false, err
+		}
+
+		if xmlNameMatches(expected, child.Name) {
+			return true, d.DecodeElement(v, child)
+		}
+
+		err = d.Skip()
+		if err != nil {
+			return false, err
+		}
+	}
+}
+
+func unmarshalBody(body string, expected *xml.Name, v any) error {
+	d := xml.NewDecoder(strings.NewReader(body))
+
+	root, err := nextStartElement(d)
+	if err != nil {
+		return err
+	}
+
+	if root.Name.Local != rpcReplyElementName {
+		// not wrapped in a rpc-reply for whatever reason, just decode what we've got
+		return d.DecodeElement(v, root)
+	}
+
+	for {
+		child, err := nextStartElement(d)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return err
+		}
+
+		if child.Name.Local == dataElementName {
+			if expected == nil || xmlNameMatches(expected, child.Name) {
+				return d.DecodeElement(v, child)
+			}
+
+			found, err := decodeMatchingChild(d, expected, v)
+			if err != nil {
+				return err
+			}
+
+			if !found {
+				return fmt.Errorf("no element %q found in data", expected.Local)
+			}
+
+			return nil
+		}
+
+		if expected != nil && xmlNameMatches(expected, child.Name) {
+			return d.DecodeElement(v, child)
+		}
+
+		err = d.Skip()
+		if err != nil {
+			return err
+		}
+	}
+
+	if expected != nil && !xmlNameMatches(expected, root.Name) {
+		return fmt.Errorf("no element %q found in rpc-reply", expected.Local)
+	}
+
+	// no data element, the rpc-reply body *is* the payload, start over and decode the rpc-reply
+	// itself since we already consumed its children
+	d = xml.NewDecoder(strings.NewReader(body))
+
+	root, err = nextStartElement(d)
+	if err != nil {
+		return err
+	}
+
+	return d.DecodeElement(v, root)
+}
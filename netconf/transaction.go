@@ -0,0 +1,266 @@
+package netconf
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+)
+
+const (
+	// transactionCleanupTimeout bounds the discard/unlock rpcs executed when cleaning up a
+	// transaction -- these ignore the users context cancellation so we need *some* upper limit.
+	transactionCleanupTimeout = 30 * time.Second
+)
+
+// TransactionStep is an enum(ish) representing a step in a Transaction.
+type TransactionStep string
+
+const (
+	TransactionStepLock       TransactionStep = "lock"
+	TransactionStepEditConfig TransactionStep = "edit-config"
+	TransactionStepValidate   TransactionStep = "validate"
+	TransactionStepCommit     TransactionStep = "commit"
+	TransactionStepDiscard    TransactionStep = "discard"
+	TransactionStepUnlock     TransactionStep = "unlock"
+)
+
+func newTransactionOptions(options ...Option) *transactionOptions {
+	o := &transactionOptions{}
+
+	for _, opt := range options {
+		opt(o)
+	}
+
+	return o
+}
+
+type transactionOptions struct {
+	target           *DatastoreType
+	defaultOperation *DefaultOperation
+	testOption       *TestOption
+	errorOption      *ErrorOption
+	skipValidate     bool
+}
+
+// TransactionStepResult holds the outcome of a single step of a Transaction. Result may be nil if
+// the step failed before a reply was received, in which case Err will be set.
+type TransactionStepResult struct {
+	Step   TransactionStep
+	Result *Result
+	Err    error
+}
+
+// Failed returns true if the step errored or the rpc-reply for the step contained rpc-errors.
+func (r *TransactionStepResult) Failed() bool {
+	return r.Err != nil || (r.Result != nil && r.Result.Failed)
+}
+
+// TransactionReport is returned from Transaction and holds the result of each executed step in
+// the order they were executed.
+type TransactionReport struct {
+	Target    DatastoreType
+	Steps     []*TransactionStepResult
+	Committed bool
+}
+
+// Failed returns true if any step in the transaction failed.
+func (r *TransactionReport) Failed() bool {
+	for _, step := range r.Steps {
+		if step.Failed() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Step returns the result of the given step, or nil if the step was not executed.
+func (r *TransactionReport) Step(step TransactionStep) *TransactionStepResult {
+	for _, stepResult := range r.Steps {
+		if stepResult.Step == step {
+			return stepResult
+		}
+	}
+
+	return nil
+}
+
+func (r *TransactionReport) record(
+	step TransactionStep,
+	result *Result,
+	err error,
+) *TransactionStepResult {
+	stepResult := &TransactionStepResult{
+		Step:   step,
+		Result: result,
+		Err:    err,
+	}
+
+	r.Steps = append(r.Steps, stepResult)
+
+	return stepResult
+}
+
+func (n *Netconf) transactionTarget(loadedOptions *transactionOptions) (DatastoreType, error) {
+	if loadedOptions.target != nil {
+		return *loadedOptions.target, nil
+	}
+
+	switch {
+	case n.HasCapability(CapabilityCandidate):
+		return DatastoreTypeCandidate, nil
+	case n.HasCapability(CapabilityWritableRunning):
+		return DatastoreTypeRunning, nil
+	default:
+		return 0, scrapligoerrors.NewNetconfError(
+			"server advertised neither candidate nor writable-running capability, "+
+				"cannot determine transaction target",
+			nil,
+		)
+	}
+}
+
+// Transaction executes the "safe" config change workflow for the given config -- that is: lock
+// the target datastore, edit-config, validate, commit, and unlock. If any step fails the changes
+// are discarded (when targeting the candidate datastore) and the datastore is always unlocked. The
+// returned TransactionReport holds the Result of each executed step; in the event of a failure the
+// report is returned alongside the error.
+//
+// The target datastore is selected based on the server's advertised capabilities: candidate is
+// preferred, falling back to running if the server supports writable-running. When targeting
+// running the edit-config is sent with the rollback-on-error error option (if the server supports
+// it and no error option was provided) since there is no candidate to discard. Validate is only
+// executed when targeting candidate and the server advertises the validate capability.
+//
+// Supported options:
+//   - WithTargetType (or WithDatastore)
+//   - WithDefaultOperation
+//   - WithTestOption
+//   - WithErrorOption
+//   - WithSkipValidate
+func (n *Netconf) Transaction( //nolint: gocyclo
+	ctx context.Context,
+	config string,
+	options ...Option,
+) (*TransactionReport, error) {
+	if n.ptr == 0 {
+		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
+	}
+
+	loadedOptions := newTransactionOptions(options...)
+
+	target, err := n.transactionTarget(loadedOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &TransactionReport{
+		Target: target,
+	}
+
+	r, err := n.Lock(ctx, WithTargetType(target))
+	if step := report.record(TransactionStepLock, r, err); step.Failed() {
+		return report, transactionStepError(step)
+	}
+
+	editOptions := []Option{WithTargetType(target)}
+
+	if loadedOptions.defaultOperation != nil {
+		editOptions = append(editOptions, WithDefaultOperation(*loadedOptions.defaultOperation))
+	}
+
+	if loadedOptions.testOption != nil {
+		editOptions = append(editOptions, WithTestOption(*loadedOptions.testOption))
+	}
+
+	switch {
+	case loadedOptions.errorOption != nil:
+		editOptions = append(editOptions, WithErrorOption(*loadedOptions.errorOption))
+	case target == DatastoreTypeRunning && n.HasCapability(CapabilityRollbackOnError):
+		editOptions = append(editOptions, WithErrorOption(ErrorOptionRollbackOnError))
+	}
+
+	r, err = n.EditConfig(ctx, config, editOptions...)
+	if step := report.record(TransactionStepEditConfig, r, err); step.Failed() {
+		return report, n.transactionAbort(ctx, report, step)
+	}
+
+	if target != DatastoreTypeCandidate {
+		// nothing to validate or commit, edits to running (or whatever else the user asked for)
+		// are applied immediately
+		report.Committed = true
+
+		return report, n.transactionUnlock(ctx, report)
+	}
+
+	if !loadedOptions.skipValidate && n.HasCapability(CapabilityValidate) {
+		r, err = n.Validate(ctx, WithSource(target))
+		if step := report.record(TransactionStepValidate, r, err); step.Failed() {
+			return report, n.transactionAbort(ctx, report, step)
+		}
+	}
+
+	r, err = n.Commit(ctx)
+	if step := report.record(TransactionStepCommit, r, err); step.Failed() {
+		return report, n.transactionAbort(ctx, report, step)
+	}
+
+	report.Committed = true
+
+	return report, n.transactionUnlock(ctx, report)
+}
+
+// transactionAbort discards any changes (when targeting candidate) and unlocks the target after a
+// failed step, returning the error describing the failed step.
+func (n *Netconf) transactionAbort(
+	ctx context.Context,
+	report *TransactionReport,
+	failedStep *TransactionStepResult,
+) error {
+	if report.Target == DatastoreTypeCandidate {
+		cleanupCtx, cancel := transactionCleanupContext(ctx)
+		defer cancel()
+
+		r, err := n.Discard(cleanupCtx)
+		report.record(TransactionStepDiscard, r, err)
+	}
+
+	_ = n.transactionUnlock(ctx, report)
+
+	return transactionStepError(failedStep)
+}
+
+func (n *Netconf) transactionUnlock(ctx context.Context, report *TransactionReport) error {
+	cleanupCtx, cancel := transactionCleanupContext(ctx)
+	defer cancel()
+
+	r, err := n.Unlock(cleanupCtx, WithTargetType(report.Target))
+	if step := report.record(TransactionStepUnlock, r, err); step.Failed() {
+		return transactionStepError(step)
+	}
+
+	return nil
+}
+
+// transactionCleanupContext returns a context for cleanup rpcs -- cleanup should happen even if
+// the users context was cancelled, otherwise we would leave the datastore dirty and/or locked until
+// the session is closed.
+func transactionCleanupContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), transactionCleanupTimeout)
+}
+
+func transactionStepError(step *TransactionStepResult) error {
+	if step.Err != nil {
+		return scrapligoerrors.NewNetconfError(
+			fmt.Sprintf("transaction failed during %s", step.Step),
+			step.Err,
+		)
+	}
+
+	return scrapligoerrors.NewNetconfError(
+		fmt.Sprintf("transaction failed during %s, rpc-reply contained errors", step.Step),
+		nil,
+	)
+}
@@ -0,0 +1,146 @@
+package netconf_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	scrapligonetconf "github.com/scrapli/scrapligo/v2/netconf"
+)
+
+func TestTransaction(t *testing.T) {
+	parentName := "transaction"
+
+	cases := map[string]struct {
+		description   string
+		config        string
+		options       []scrapligonetconf.Option
+		expectedSteps []scrapligonetconf.TransactionStep
+		failedStep    scrapligonetconf.TransactionStep
+	}{
+		"simple": {
+			description: "simple - lock, edit, validate, commit and unlock the candidate config",
+			config:      ``,
+			expectedSteps: []scrapligonetconf.TransactionStep{
+				scrapligonetconf.TransactionStepLock,
+				scrapligonetconf.TransactionStepEditConfig,
+				scrapligonetconf.TransactionStepValidate,
+				scrapligonetconf.TransactionStepCommit,
+				scrapligonetconf.TransactionStepUnlock,
+			},
+		},
+		"edit-config-failed": {
+			description: "edit-config fails - the candidate is discarded and unlocked",
+			config:      ``,
+			expectedSteps: []scrapligonetconf.TransactionStep{
+				scrapligonetconf.TransactionStepLock,
+				scrapligonetconf.TransactionStepEditConfig,
+				scrapligonetconf.TransactionStepDiscard,
+				scrapligonetconf.TransactionStepUnlock,
+			},
+			failedStep: scrapligonetconf.TransactionStepEditConfig,
+		},
+		"validate-failed": {
+			description: "validate fails - the candidate is discarded and unlocked",
+			config:      ``,
+			expectedSteps: []scrapligonetconf.TransactionStep{
+				scrapligonetconf.TransactionStepLock,
+				scrapligonetconf.TransactionStepEditConfig,
+				scrapligonetconf.TransactionStepValidate,
+				scrapligonetconf.TransactionStepDiscard,
+				scrapligonetconf.TransactionStepUnlock,
+			},
+			failedStep: scrapligonetconf.TransactionStepValidate,
+		},
+		"commit-failed": {
+			description: "commit fails - the candidate is discarded and unlocked",
+			config:      ``,
+			expectedSteps: []scrapligonetconf.TransactionStep{
+				scrapligonetconf.TransactionStepLock,
+				scrapligonetconf.TransactionStepEditConfig,
+				scrapligonetconf.TransactionStepValidate,
+				scrapligonetconf.TransactionStepCommit,
+				scrapligonetconf.TransactionStepDiscard,
+				scrapligonetconf.TransactionStepUnlock,
+			},
+			failedStep: scrapligonetconf.TransactionStepCommit,
+		},
+	}
+
+	for caseName, c := range cases {
+		testName := fmt.Sprintf("%s-%s", parentName, caseName)
+
+		t.Run(testName, func(t *testing.T) {
+			t.Logf("%s: starting", testName)
+
+			testFixturePath, err := filepath.Abs(fmt.Sprintf("./fixtures/%s", testName))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			defer cancel()
+
+			n := getNetconf(t, testFixturePath)
+
+			_, err = n.Open(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer func() {
+				_, _ = n.Close(ctx)
+			}()
+
+			report, err := n.Transaction(ctx, c.config, c.options...)
+
+			if c.failedStep == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if report.Failed() || !report.Committed {
+					t.Fatal("expected transaction to be committed without failures")
+				}
+			} else {
+				if err == nil {
+					t.Fatal("expected transaction to fail")
+				}
+
+				if report.Committed {
+					t.Fatal("expected transaction not to be committed")
+				}
+
+				if step := report.Step(c.failedStep); step == nil || !step.Failed() {
+					t.Fatalf("expected step %s to have failed", c.failedStep)
+				}
+
+				for _, cleanupStep := range []scrapligonetconf.TransactionStep{
+					scrapligonetconf.TransactionStepDiscard,
+					scrapligonetconf.TransactionStepUnlock,
+				} {
+					if step := report.Step(cleanupStep); step == nil || step.Failed() {
+						t.Fatalf("expected cleanup step %s to have succeeded", cleanupStep)
+					}
+				}
+			}
+
+			if report.Target != scrapligonetconf.DatastoreTypeCandidate {
+				t.Fatalf("expected candidate target, got %d", report.Target)
+			}
+
+			actualSteps := make([]scrapligonetconf.TransactionStep, len(report.Steps))
+
+			for i, step := range report.Steps {
+				actualSteps[i] = step.Step
+			}
+
+			if !slices.Equal(actualSteps, c.expectedSteps) {
+				t.Fatalf("expected steps %v, got %v", c.expectedSteps, actualSteps)
+			}
+		})
+	}
+}
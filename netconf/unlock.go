@@ -4,6 +4,7 @@ import (
 	"context"
 
 	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
 )
 
 func newUnlockOptions(options ...Option) *unlockOptions {
@@ -36,25 +37,46 @@ func (n *Netconf) Unlock(
 	ctx context.Context,
 	options ...Option,
 ) (*Result, error) {
-	if n.ptr == 0 {
-		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
+	op, err := n.UnlockAsync(ctx, options...)
+	if err != nil {
+		return nil, err
 	}
 
-	cancel := false
+	return op.Wait(ctx)
+}
 
-	var operationID uint32
+// UnlockAsync is the async flavor of Unlock -- the rpc is submitted and an OperationHandle is
+// returned immediately, see Unlock for supported options.
+func (n *Netconf) UnlockAsync(
+	ctx context.Context,
+	options ...Option,
+) (*OperationHandle, error) {
+	if n.ptr == 0 {
+		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
+	}
 
 	loadedOptions := newUnlockOptions(options...)
 
-	err := n.ffiMap.Netconf.Unlock(
-		n.ptr,
-		&operationID,
-		&cancel,
-		loadedOptions.getTarget(),
+	err := n.requireDatastore("unlock", loadedOptions.target)
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := n.submit(
+		ctx,
+		"unlock",
+		func(m *scrapligoffi.NetconfMapping, id *uint32, cancel *scrapligoffi.CancelFlag) error {
+			return m.Unlock(
+				n.ptr,
+				id,
+				cancel,
+				loadedOptions.getTarget(),
+			)
+		},
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	return n.getResult(ctx, &cancel, operationID)
+	return op, nil
 }
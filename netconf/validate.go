@@ -4,6 +4,7 @@ import (
 	"context"
 
 	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligoffi "github.com/scrapli/scrapligo/v2/ffi"
 )
 
 func newValidateOptions(options ...Option) *validateOptions {
@@ -30,31 +31,58 @@ func (o *validateOptions) getSource() *uint8 {
 	return &v
 }
 
-// Validate executes a netconf validate rpc. Supported options:
+// Validate executes a netconf validate rpc. Fails fast (without sending the rpc) if the server did
+// not advertise the :validate capability. Supported options:
 //   - WithSource
 func (n *Netconf) Validate(
 	ctx context.Context,
 	options ...Option,
 ) (*Result, error) {
+	op, err := n.ValidateAsync(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return op.Wait(ctx)
+}
+
+// ValidateAsync is the async flavor of Validate -- the rpc is submitted and an OperationHandle is
+// returned immediately, see Validate for supported options.
+func (n *Netconf) ValidateAsync(
+	ctx context.Context,
+	options ...Option,
+) (*OperationHandle, error) {
 	if n.ptr == 0 {
 		return nil, scrapligoerrors.NewFfiError("driver pointer nil", nil)
 	}
 
-	cancel := false
+	loadedOptions := newValidateOptions(options...)
 
-	var operationID uint32
+	err := n.requireCapability("validate", CapabilityValidate)
+	if err != nil {
+		return nil, err
+	}
 
-	loadedOptions := newValidateOptions(options...)
+	err = n.requireDatastore("validate", loadedOptions.source)
+	if err != nil {
+		return nil, err
+	}
 
-	err := n.ffiMap.Netconf.Validate(
-		n.ptr,
-		&operationID,
-		&cancel,
-		loadedOptions.getSource(),
+	op, err := n.submit(
+		ctx,
+		"validate",
+		func(m *scrapligoffi.NetconfMapping, id *uint32, cancel *scrapligoffi.CancelFlag) error {
+			return m.Validate(
+				n.ptr,
+				id,
+				cancel,
+				loadedOptions.getSource(),
+			)
+		},
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	return n.getResult(ctx, &cancel, operationID)
+	return op, nil
 }
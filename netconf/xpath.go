@@ -0,0 +1,513 @@
+package netconf
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+)
+
+// xmlNode is a minimal dom node used for evaluating queries against a Result. The string value of
+// a node (the concatenation of all descendant character data, as in xpath) is stored as offsets
+// into the tree's shared text buffer so we don't have to copy it around for every ancestor.
+type xmlNode struct {
+	name       xml.Name
+	attrs      []xml.Attr
+	children   []*xmlNode
+	directText strings.Builder
+	textStart  int
+	textEnd    int
+}
+
+type xmlTree struct {
+	root *xmlNode
+	text strings.Builder
+}
+
+func (t *xmlTree) stringValue(n *xmlNode) string {
+	return strings.TrimSpace(t.text.String()[n.textStart:n.textEnd])
+}
+
+func parseXMLTree(body string) (*xmlTree, error) {
+	d := xml.NewDecoder(strings.NewReader(body))
+
+	tree := &xmlTree{
+		// synthetic document node, the actual document element is its child
+		root: &xmlNode{},
+	}
+
+	stack := []*xmlNode{tree.root}
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, err
+		}
+
+		switch typedTok := tok.(type) {
+		case xml.StartElement:
+			n := &xmlNode{
+				name:      typedTok.Name,
+				attrs:     typedTok.Attr,
+				textStart: tree.text.Len(),
+			}
+
+			parent := stack[len(stack)-1]
+			parent.children = append(parent.children, n)
+
+			stack = append(stack, n)
+		case xml.EndElement:
+			n := stack[len(stack)-1]
+			n.textEnd = tree.text.Len()
+
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			tree.text.Write(typedTok)
+			stack[len(stack)-1].directText.Write(typedTok)
+		}
+	}
+
+	tree.root.textEnd = tree.text.Len()
+
+	return tree, nil
+}
+
+// bodyNode returns the node that is the parent of the "interesting" part of a reply -- the data
+// element if present, otherwise the rpc-reply element, otherwise the document node.
+func (t *xmlTree) bodyNode() *xmlNode {
+	if len(t.root.children) == 0 {
+		return t.root
+	}
+
+	rpcReply := t.root.children[0]
+	if rpcReply.name.Local != rpcReplyElementName {
+		return t.root
+	}
+
+	for _, child := range rpcReply.children {
+		if child.name.Local == dataElementName {
+			return child
+		}
+	}
+
+	return rpcReply
+}
+
+type xpathStepKind int
+
+const (
+	xpathStepElement xpathStepKind = iota
+	xpathStepAttribute
+	xpathStepText
+)
+
+type xpathPredicate struct {
+	// position is the 1-based position for positional predicates, zero otherwise
+	position int
+	// kind/name identify what is compared for value predicates -- a child element, an attribute,
+	// or the node's own text ("text()" or ".")
+	kind  xpathStepKind
+	self  bool
+	name  xpathName
+	value string
+}
+
+type xpathName struct {
+	space    string
+	local    string
+	anySpace bool
+}
+
+func (n xpathName) matches(name xml.Name) bool {
+	if n.local != "*" && n.local != name.Local {
+		return false
+	}
+
+	return n.anySpace || n.space == name.Space
+}
+
+type xpathStep struct {
+	descendant bool
+	kind       xpathStepKind
+	name       xpathName
+	predicates []xpathPredicate
+}
+
+// splitXPath splits an expression on "/" ignoring any slashes within predicates or quotes; a
+// "//" yields an empty segment which marks the following step as a descendant step.
+func splitXPath(expression string) ([]string, error) {
+	var (
+		segments []string
+		current  strings.Builder
+		depth    int
+		quote    rune
+	)
+
+	for _, c := range expression {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth < 0 {
+				return nil, errors.New("unbalanced brackets")
+			}
+		case c == '/' && depth == 0:
+			segments = append(segments, current.String())
+			current.Reset()
+
+			continue
+		}
+
+		current.WriteRune(c)
+	}
+
+	if quote != 0 || depth != 0 {
+		return nil, errors.New("unterminated quote or predicate")
+	}
+
+	return append(segments, current.String()), nil
+}
+
+func resolveXPathName(raw string, namespaces map[string]string) (xpathName, error) {
+	prefix, local, prefixed := strings.Cut(raw, ":")
+	if !prefixed {
+		return xpathName{local: raw, anySpace: true}, nil
+	}
+
+	space, ok := namespaces[prefix]
+	if !ok {
+		return xpathName{}, fmt.Errorf("unknown namespace prefix %q", prefix)
+	}
+
+	return xpathName{space: space, local: local}, nil
+}
+
+func parseXPathPredicate(raw string, namespaces map[string]string) (xpathPredicate, error) {
+	raw = strings.TrimSpace(raw)
+
+	position, err := strconv.Atoi(raw)
+	if err == nil {
+		if position < 1 {
+			return xpathPredicate{}, fmt.Errorf("invalid position %d", position)
+		}
+
+		return xpathPredicate{position: position}, nil
+	}
+
+	lhs, rhs, ok := strings.Cut(raw, "=")
+	if !ok {
+		return xpathPredicate{}, fmt.Errorf("unsupported predicate %q", raw)
+	}
+
+	lhs = strings.TrimSpace(lhs)
+	rhs = strings.TrimSpace(rhs)
+
+	if len(rhs) < 2 || (rhs[0] != '\'' && rhs[0] != '"') || rhs[len(rhs)-1] != rhs[0] {
+		return xpathPredicate{}, fmt.Errorf("predicate value must be quoted in %q", raw)
+	}
+
+	predicate := xpathPredicate{value: rhs[1 : len(rhs)-1]}
+
+	err = nil
+
+	switch {
+	case lhs == "." || lhs == "text()":
+		predicate.self = true
+	case strings.HasPrefix(lhs, "@"):
+		predicate.kind = xpathStepAttribute
+		predicate.name, err = resolveXPathName(lhs[1:], namespaces)
+	default:
+		predicate.name, err = resolveXPathName(lhs, namespaces)
+	}
+
+	return predicate, err
+}
+
+// predicateEnd returns the index of the "]" closing the predicate at the start of s, ignoring any
+// brackets in quoted literals, or -1 if the predicate is not terminated.
+func predicateEnd(s string) int {
+	var quote rune
+
+	for idx, c := range s {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == ']':
+			return idx
+		}
+	}
+
+	return -1
+}
+
+func parseXPathStep(raw string, namespaces map[string]string) (xpathStep, error) {
+	step := xpathStep{}
+
+	nameTest, predicates, _ := strings.Cut(raw, "[")
+	if predicates != "" {
+		predicates = "[" + predicates
+	}
+
+	for predicates != "" {
+		end := predicateEnd(predicates)
+		if !strings.HasPrefix(predicates, "[") || end < 0 {
+			return step, fmt.Errorf("malformed predicates in step %q", raw)
+		}
+
+		predicate, err := parseXPathPredicate(predicates[1:end], namespaces)
+		if err != nil {
+			return step, err
+		}
+
+		step.predicates = append(step.predicates, predicate)
+		predicates = predicates[end+1:]
+	}
+
+	var err error
+
+	switch {
+	case nameTest == "":
+		return step, fmt.Errorf("empty step in %q", raw)
+	case nameTest == "text()":
+		step.kind = xpathStepText
+	case strings.HasPrefix(nameTest, "@"):
+		step.kind = xpathStepAttribute
+		step.name, err = resolveXPathName(nameTest[1:], namespaces)
+	default:
+		step.name, err = resolveXPathName(nameTest, namespaces)
+	}
+
+	return step, err
+}
+
+func parseXPath(expression string, namespaces map[string]string) ([]xpathStep, error) {
+	expression = strings.TrimSpace(expression)
+	expression = strings.TrimPrefix(expression, "/")
+
+	if expression == "" {
+		return nil, errors.New("empty expression")
+	}
+
+	segments, err := splitXPath(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		steps      []xpathStep
+		descendant bool
+	)
+
+	for idx, segment := range segments {
+		if segment == "" {
+			// a leading "//" leaves us with an empty first segment after trimming the first slash
+			descendant = true
+
+			continue
+		}
+
+		step, err := parseXPathStep(segment, namespaces)
+		if err != nil {
+			return nil, err
+		}
+
+		if step.kind != xpathStepElement && idx != len(segments)-1 {
+			return nil, fmt.Errorf("%q may only be used as the final step", segment)
+		}
+
+		step.descendant = descendant
+		descendant = false
+
+		steps = append(steps, step)
+	}
+
+	if descendant {
+		return nil, errors.New("expression may not end with '//'")
+	}
+
+	return steps, nil
+}
+
+func (t *xmlTree) predicateMatches(n *xmlNode, predicate xpathPredicate) bool {
+	switch {
+	case predicate.self:
+		return t.stringValue(n) == predicate.value
+	case predicate.kind == xpathStepAttribute:
+		for _, attr := range n.attrs {
+			if predicate.name.matches(attr.Name) && attr.Value == predicate.value {
+				return true
+			}
+		}
+	default:
+		for _, child := range n.children {
+			if predicate.name.matches(child.name) && t.stringValue(child) == predicate.value {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (t *xmlTree) applyPredicates(candidates []*xmlNode, predicates []xpathPredicate) []*xmlNode {
+	for _, predicate := range predicates {
+		if predicate.position > 0 {
+			if predicate.position > len(candidates) {
+				return nil
+			}
+
+			candidates = candidates[predicate.position-1 : predicate.position]
+
+			continue
+		}
+
+		var filtered []*xmlNode
+
+		for _, candidate := range candidates {
+			if t.predicateMatches(candidate, predicate) {
+				filtered = append(filtered, candidate)
+			}
+		}
+
+		candidates = filtered
+	}
+
+	return candidates
+}
+
+func collectDescendants(n *xmlNode, out []*xmlNode) []*xmlNode {
+	for _, child := range n.children {
+		out = append(out, child)
+		out = collectDescendants(child, out)
+	}
+
+	return out
+}
+
+func (t *xmlTree) evaluate(start *xmlNode, steps []xpathStep) []string {
+	nodes := []*xmlNode{start}
+
+	for _, step := range steps {
+		if step.kind != xpathStepElement {
+			return t.finalValues(nodes, step)
+		}
+
+		var next []*xmlNode
+
+		seen := map[*xmlNode]bool{}
+
+		for _, n := range nodes {
+			candidates := n.children
+			if step.descendant {
+				candidates = collectDescendants(n, nil)
+			}
+
+			var matched []*xmlNode
+
+			for _, candidate := range candidates {
+				if step.name.matches(candidate.name) {
+					matched = append(matched, candidate)
+				}
+			}
+
+			for _, m := range t.applyPredicates(matched, step.predicates) {
+				if !seen[m] {
+					seen[m] = true
+
+					next = append(next, m)
+				}
+			}
+		}
+
+		nodes = next
+	}
+
+	values := make([]string, len(nodes))
+
+	for idx, n := range nodes {
+		values[idx] = t.stringValue(n)
+	}
+
+	return values
+}
+
+func (t *xmlTree) finalValues(nodes []*xmlNode, step xpathStep) []string {
+	var values []string
+
+	for _, n := range nodes {
+		candidates := []*xmlNode{n}
+		if step.descendant {
+			candidates = append(candidates, collectDescendants(n, nil)...)
+		}
+
+		for _, candidate := range candidates {
+			if step.kind == xpathStepText {
+				text := strings.TrimSpace(candidate.directText.String())
+				if text != "" {
+					values = append(values, text)
+				}
+
+				continue
+			}
+
+			for _, attr := range candidate.attrs {
+				if step.name.matches(attr.Name) {
+					values = append(values, attr.Value)
+				}
+			}
+		}
+	}
+
+	return values
+}
+
+// Query evaluates an xpath(ish) expression against the body of the result (the children of the
+// data element for Get, GetConfig and GetData results, or the children of the rpc-reply for
+// RawRPC results) and returns the (trimmed) string value of each matching node. Only a subset of
+// xpath is supported -- location paths made of child ("/") and descendant ("//") steps, "*" name
+// tests, a final "@attribute" or "text()" step, and predicates that select by position ("[1]") or
+// compare a child, attribute, or the node itself to a quoted literal ("[name='eth0']",
+// "[@type='x']", "[.='x']").
+//
+// Unprefixed names match elements in any namespace; prefixed names must match the namespace
+// mapped to the prefix in namespaces, for example:
+//
+//	r.Query(
+//		"/if:interfaces/if:interface[if:enabled='true']/if:name",
+//		map[string]string{"if": "urn:ietf:params:xml:ns:yang:ietf-interfaces"},
+//	)
+func (r *Result) Query(expression string, namespaces map[string]string) ([]string, error) {
+	steps, err := parseXPath(expression, namespaces)
+	if err != nil {
+		return nil, scrapligoerrors.NewUtilError(
+			fmt.Sprintf("invalid query expression %q", expression),
+			err,
+		)
+	}
+
+	tree, err := parseXMLTree(r.Result)
+	if err != nil {
+		return nil, scrapligoerrors.NewUtilError("failed parsing result", err)
+	}
+
+	return tree.evaluate(tree.bodyNode(), steps), nil
+}
@@ -0,0 +1,18 @@
+package options
+
+import (
+	scrapligoaudit "github.com/scrapli/scrapligo/v2/audit"
+	scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
+)
+
+// WithAuditSink sets the sink the driver writes an audit record to for each cli operation or
+// netconf rpc it executes -- including those denied by policy or recorded in dry-run mode. Open
+// and close are not audited. The operator identity of a record is taken from the context of the
+// operation, see audit.WithOperator.
+func WithAuditSink(sink *scrapligoaudit.Sink) Option {
+	return func(o *scrapligointernal.Options) error {
+		o.Audit = sink
+
+		return nil
+	}
+}
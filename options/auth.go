@@ -1,6 +1,9 @@
 package options
 
-import scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
+import (
+	scrapligocredentials "github.com/scrapli/scrapligo/v2/credentials"
+	scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
+)
 
 // WithUsername sets the username to use for authentication to the target device.
 func WithUsername(s string) Option {
@@ -58,6 +61,22 @@ func WithLookupKeyValue(key, value string) Option {
 	}
 }
 
+// WithLookupCallback sets a func that is invoked with the host and key whenever a "__lookup::key"
+// value is needed (i.e. the enable secret when escalating privilege) and the lookup map has no
+// entry for the key. This allows fetching per device secrets or one time passwords on demand
+// rather than holding them in memory up front. Returning an empty value falls back to the
+// password (as a missing lookup map entry does), returning an error fails the operation that
+// needed the value. Lookup callbacks are only supported by the go backend (see WithBackendGo)
+// until libscrapli exposes a lookup callback, opening a libscrapli backed driver with a lookup
+// callback fails.
+func WithLookupCallback(f func(host, key string) (string, error)) Option {
+	return func(o *scrapligointernal.Options) error {
+		o.Auth.LookupCallback = f
+
+		return nil
+	}
+}
+
 // WithForceInSessionAuth unconditionally forces the in session auth to run.
 func WithForceInSessionAuth() Option {
 	return func(o *scrapligointernal.Options) error {
@@ -109,3 +128,18 @@ func WithPassphrasePattern(s string) Option {
 		return nil
 	}
 }
+
+// WithCredentialProvider adds a provider that supplies credentials (username, password, private
+// key content and lookup values) when the driver is opened rather than when it is created, see the
+// credentials package for the env, file and exec providers and the (opt-in) credentials/keyring
+// package for the keyring provider. The credential sets of all providers are tried in the order
+// the providers were added until one authenticates, fields a set leaves empty fall back to the
+// auth options set directly. A failing provider is skipped, its error is only returned if no set
+// authenticates.
+func WithCredentialProvider(p scrapligocredentials.Provider) Option {
+	return func(o *scrapligointernal.Options) error {
+		o.Auth.CredentialProviders = append(o.Auth.CredentialProviders, p)
+
+		return nil
+	}
+}
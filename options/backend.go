@@ -0,0 +1,25 @@
+package options
+
+import scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
+
+// WithBackendLibscrapli sets the driver backend to libscrapli, this is the default unless built
+// with the scrapligo_gobackend tag.
+func WithBackendLibscrapli() Option {
+	return func(o *scrapligointernal.Options) error {
+		o.Backend = scrapligointernal.BackendKindLibscrapli
+
+		return nil
+	}
+}
+
+// WithBackendGo sets the driver backend to the pure go (golang.org/x/crypto/ssh based) backend,
+// this requires no libscrapli shared library at all. The go backend supports the ssh and test
+// transports only -- the bin and ssh2 transport kinds are both served by the go ssh client, telnet
+// is not supported.
+func WithBackendGo() Option {
+	return func(o *scrapligointernal.Options) error {
+		o.Backend = scrapligointernal.BackendKindGo
+
+		return nil
+	}
+}
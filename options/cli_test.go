@@ -11,6 +11,8 @@ import (
 )
 
 func TestCLIOptions(t *testing.T) {
+	scrapligotesthelper.SkipGoBackend(t)
+
 	d, err := scrapligocli.NewCli(
 		"1.2.3.4",
 	)
@@ -0,0 +1,44 @@
+package options
+
+import (
+	scrapligodryrun "github.com/scrapli/scrapligo/v2/dryrun"
+	scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
+)
+
+// WithDryRun puts the driver in dry-run mode -- configuration changing operations (cli inputs
+// sent in a configuration mode, netconf edit-config, edit-data, copy-config, delete-config and
+// commit rpcs) are recorded in plan and return synthetic (empty, successful) results rather than
+// being sent to the device. Everything else is sent as usual, see WithDryRunFixture to serve those
+// operations from a recorded session instead. Cli inputs w/out a requested mode are sent in the
+// mode the session is in (the default mode of the definition once opened), if that is not known
+// (i.e. after a failed EnterMode) there is no telling whether they change configuration and the
+// operation fails.
+func WithDryRun(plan *scrapligodryrun.Plan) Option {
+	return func(o *scrapligointernal.Options) error {
+		o.DryRun.Plan = plan
+
+		return nil
+	}
+}
+
+// WithDryRunConfigModes sets the cli modes that are considered configuration modes in dry-run
+// mode, by default any mode with "config" in its name is.
+func WithDryRunConfigModes(modes ...string) Option {
+	return func(o *scrapligointernal.Options) error {
+		o.DryRun.ConfigModes = modes
+
+		return nil
+	}
+}
+
+// WithDryRunFixture serves the operations that are not recorded in dry-run mode from the session
+// recorded at path (see WithSessionRecorderPath) rather than from the device, such that a dry-run
+// does not connect to anything at all.
+func WithDryRunFixture(path string) Option {
+	return func(o *scrapligointernal.Options) error {
+		o.TransportKind = scrapligointernal.TransportKindTest
+		o.Transport.Test.F = path
+
+		return nil
+	}
+}
@@ -0,0 +1,18 @@
+package options
+
+import (
+	scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
+	scrapligometrics "github.com/scrapli/scrapligo/v2/metrics"
+)
+
+// WithMetricsRecorder sets the recorder the driver reports metrics to -- open/close and per
+// operation (cli operation or netconf rpc) statistics and active sessions. A single recorder can
+// (and generally should) be shared by all drivers, see the metrics/prometheus package for a
+// prometheus backed recorder.
+func WithMetricsRecorder(recorder scrapligometrics.Recorder) Option {
+	return func(o *scrapligointernal.Options) error {
+		o.Metrics = recorder
+
+		return nil
+	}
+}
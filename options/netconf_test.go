@@ -12,6 +12,8 @@ import (
 )
 
 func TestNETCONFOptions(t *testing.T) {
+	scrapligotesthelper.SkipGoBackend(t)
+
 	d, err := scrapligonetconf.NewNetconf(
 		"1.2.3.4",
 		scrapligooptions.WithNetconfErrorTag("<errrrrror>"),
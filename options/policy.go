@@ -0,0 +1,32 @@
+package options
+
+import (
+	scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
+	scrapligopolicy "github.com/scrapli/scrapligo/v2/policy"
+)
+
+// WithPolicy sets the (command) policy of the driver -- every cli input, and every netconf rpc
+// the policy has rules for, is evaluated against the policy before being sent, denied operations
+// fail with an ErrPolicyDenied error without anything being sent to the device.
+func WithPolicy(policy *scrapligopolicy.Policy) Option {
+	return func(o *scrapligointernal.Options) error {
+		o.Policy = policy
+
+		return nil
+	}
+}
+
+// WithPolicyFile loads the yaml policy file at path and sets it as the policy of the driver, see
+// WithPolicy and policy.ParsePolicy.
+func WithPolicyFile(path string) Option {
+	return func(o *scrapligointernal.Options) error {
+		policy, err := scrapligopolicy.LoadPolicy(path)
+		if err != nil {
+			return err
+		}
+
+		o.Policy = policy
+
+		return nil
+	}
+}
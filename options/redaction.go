@@ -0,0 +1,70 @@
+package options
+
+import (
+	"fmt"
+	"regexp"
+
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
+)
+
+// WithRedaction enables secret redaction in log messages (the driver logger) and session
+// recordings passed to the session recorder callback -- the configured password, private key
+// passphrase and lookup map values, plus the values of common secret/password/key config lines
+// (see WithRedactionNoBuiltinPatterns), are replaced with "<redacted>". Recordings written directly
+// to a file (WithSessionRecorderPath) are written by libscrapli and are not redacted. Note that the
+// recorder is handed the session in chunks as it is read, a secret split across chunks may not be
+// redacted.
+func WithRedaction() Option {
+	return func(o *scrapligointernal.Options) error {
+		o.Redaction.Enabled = true
+
+		return nil
+	}
+}
+
+// WithRedactionPatterns adds patterns to redact in addition to the builtin ones, and enables
+// redaction (see WithRedaction). If a pattern has a capture group the first group is retained and
+// the rest of the match is redacted, otherwise the whole match is redacted.
+func WithRedactionPatterns(patterns ...string) Option {
+	return func(o *scrapligointernal.Options) error {
+		for _, pattern := range patterns {
+			p, err := regexp.Compile(pattern)
+			if err != nil {
+				return scrapligoerrors.NewOptionsError(
+					fmt.Sprintf("invalid redaction pattern %q", pattern),
+					err,
+				)
+			}
+
+			o.Redaction.Patterns = append(o.Redaction.Patterns, p)
+		}
+
+		o.Redaction.Enabled = true
+
+		return nil
+	}
+}
+
+// WithRedactResults applies redaction to the inputs and results of Results too, and enables
+// redaction (see WithRedaction).
+func WithRedactResults() Option {
+	return func(o *scrapligointernal.Options) error {
+		o.Redaction.Enabled = true
+		o.Redaction.Results = true
+
+		return nil
+	}
+}
+
+// WithRedactionNoBuiltinPatterns disables the builtin patterns that redact the values of common
+// secret/password/key config lines, leaving the configured secrets (and any patterns added with
+// WithRedactionPatterns) to be redacted. Redaction is enabled (see WithRedaction).
+func WithRedactionNoBuiltinPatterns() Option {
+	return func(o *scrapligointernal.Options) error {
+		o.Redaction.Enabled = true
+		o.Redaction.NoBuiltinPatterns = true
+
+		return nil
+	}
+}
@@ -13,6 +13,8 @@ import (
 )
 
 func TestSessionOptions(t *testing.T) {
+	scrapligotesthelper.SkipGoBackend(t)
+
 	d, err := scrapligocli.NewCli(
 		"1.2.3.4",
 		scrapligooptions.WithReadSize(999),
@@ -0,0 +1,27 @@
+package options
+
+import (
+	scrapligointernal "github.com/scrapli/scrapligo/v2/internal"
+	scrapligotracing "github.com/scrapli/scrapligo/v2/tracing"
+)
+
+// WithTracer sets the tracer the driver emits spans with -- a span per Open/Close, cli
+// SendInput(s)/EnterMode operation and netconf rpc, see the tracing/otel package for an
+// opentelemetry tracer. Drivers emit no spans if no tracer is set.
+func WithTracer(tracer scrapligotracing.Tracer) Option {
+	return func(o *scrapligointernal.Options) error {
+		o.Tracing.Tracer = tracer
+
+		return nil
+	}
+}
+
+// WithTracingRedactInputs redacts the input(s) of operations in spans, useful if inputs may carry
+// anything sensitive. The input byte count is still recorded.
+func WithTracingRedactInputs() Option {
+	return func(o *scrapligointernal.Options) error {
+		o.Tracing.RedactInputs = true
+
+		return nil
+	}
+}
@@ -12,6 +12,8 @@ import (
 )
 
 func TestTransportBinOptions(t *testing.T) {
+	scrapligotesthelper.SkipGoBackend(t)
+
 	d, err := scrapligocli.NewCli(
 		"1.2.3.4",
 		scrapligooptions.WithTransportBin(),
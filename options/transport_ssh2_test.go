@@ -12,6 +12,8 @@ import (
 )
 
 func TestTransportSSH2Options(t *testing.T) {
+	scrapligotesthelper.SkipGoBackend(t)
+
 	d, err := scrapligocli.NewCli(
 		"1.2.3.4",
 		scrapligooptions.WithTransportSSH2(),
@@ -0,0 +1,241 @@
+package policy
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	"go.yaml.in/yaml/v3"
+)
+
+// Action is an enum(ish) representing what a Rule does with a matching input.
+type Action string
+
+const (
+	// Allow allows matching inputs to be sent.
+	Allow Action = "allow"
+	// Deny blocks matching inputs, the operation fails with an ErrPolicyDenied error.
+	Deny Action = "deny"
+)
+
+// Rule is a single policy rule. A rule matches an input if Pattern matches the input and the
+// input is sent to a matching host, platform and mode -- empty Hosts, Platforms or Modes match
+// anything. Rules without RPCs apply to cli inputs, rules with RPCs apply to the netconf rpcs of
+// the given names (i.e. "delete-config", "copy-config"), with Pattern matched against the rendered
+// rpc.
+type Rule struct {
+	// Name identifies the rule in errors, optional.
+	Name   string `yaml:"name"`
+	Action Action `yaml:"action"`
+	// Pattern is the regex matched against the input.
+	Pattern string `yaml:"pattern"`
+	// Hosts are regexes matched against the host.
+	Hosts []string `yaml:"hosts"`
+	// Platforms are platform (definition) names, i.e. "cisco_iosxe".
+	Platforms []string `yaml:"platforms"`
+	// Modes are mode names, i.e. "privileged_exec" -- the mode an input is sent in is the
+	// requested mode of the operation, or the mode the session is in (the default mode of the
+	// definition once opened, the mode last requested after that). If the mode is not known the
+	// input is evaluated as sent in the default mode of the definition.
+	Modes []string `yaml:"modes"`
+	// RPCs are netconf rpc names.
+	RPCs []string `yaml:"rpcs"`
+}
+
+type compiledRule struct {
+	Rule
+	name    string
+	pattern *regexp.Regexp
+	hosts   []*regexp.Regexp
+}
+
+func (r *compiledRule) matches(s *Subject) bool {
+	if len(r.RPCs) == 0 {
+		if s.RPC != "" {
+			return false
+		}
+	} else if !slices.Contains(r.RPCs, s.RPC) {
+		return false
+	}
+
+	if len(r.Platforms) > 0 && !slices.Contains(r.Platforms, s.Platform) {
+		return false
+	}
+
+	if len(r.Modes) > 0 && !slices.Contains(r.Modes, s.Mode) {
+		return false
+	}
+
+	if len(r.hosts) > 0 && !slices.ContainsFunc(r.hosts, func(host *regexp.Regexp) bool {
+		return host.MatchString(s.Host)
+	}) {
+		return false
+	}
+
+	return r.pattern.MatchString(s.Input)
+}
+
+// Subject is what a Policy is evaluated against -- a single cli input, or a netconf rpc.
+type Subject struct {
+	Host     string
+	Platform string
+	Mode     string
+	// RPC is the name of the netconf rpc, empty for cli inputs.
+	RPC string
+	// Input is the cli input, or the rendered netconf rpc.
+	Input string
+}
+
+// Policy is an ordered set of allow/deny rules -- the first rule that matches an input decides
+// whether it is sent, inputs no rule matches get the default action.
+type Policy struct {
+	defaultAction Action
+	rules         []*compiledRule
+}
+
+// NewPolicy returns a Policy with the given default action and rules.
+func NewPolicy(defaultAction Action, rules ...Rule) (*Policy, error) {
+	if defaultAction == "" {
+		defaultAction = Allow
+	}
+
+	if defaultAction != Allow && defaultAction != Deny {
+		return nil, scrapligoerrors.NewOptionsError(
+			fmt.Sprintf("invalid policy default action %q", defaultAction),
+			nil,
+		)
+	}
+
+	p := &Policy{
+		defaultAction: defaultAction,
+		rules:         make([]*compiledRule, len(rules)),
+	}
+
+	for idx, rule := range rules {
+		compiled, err := compileRule(idx, rule)
+		if err != nil {
+			return nil, err
+		}
+
+		p.rules[idx] = compiled
+	}
+
+	return p, nil
+}
+
+func compileRule(idx int, rule Rule) (*compiledRule, error) {
+	compiled := &compiledRule{
+		Rule: rule,
+		name: rule.Name,
+	}
+
+	if compiled.name == "" {
+		compiled.name = fmt.Sprintf("rule %d", idx)
+	}
+
+	if rule.Action != Allow && rule.Action != Deny {
+		return nil, scrapligoerrors.NewOptionsError(
+			fmt.Sprintf("invalid action %q for policy %s", rule.Action, compiled.name),
+			nil,
+		)
+	}
+
+	var err error
+
+	compiled.pattern, err = regexp.Compile(rule.Pattern)
+	if err != nil {
+		return nil, scrapligoerrors.NewOptionsError(
+			fmt.Sprintf("invalid pattern for policy %s", compiled.name),
+			err,
+		)
+	}
+
+	for _, host := range rule.Hosts {
+		hostPattern, err := regexp.Compile(host)
+		if err != nil {
+			return nil, scrapligoerrors.NewOptionsError(
+				fmt.Sprintf("invalid host pattern for policy %s", compiled.name),
+				err,
+			)
+		}
+
+		compiled.hosts = append(compiled.hosts, hostPattern)
+	}
+
+	return compiled, nil
+}
+
+type policyFile struct {
+	Default Action `yaml:"default"`
+	Rules   []Rule `yaml:"rules"`
+}
+
+// ParsePolicy parses a yaml policy like:
+//
+//	default: allow
+//	rules:
+//	  - name: no-write-erase
+//	    action: deny
+//	    pattern: '^\s*write\s+erase'
+//	  - name: no-config-in-production
+//	    action: deny
+//	    pattern: '.*'
+//	    hosts: ['^prod-']
+//	    modes: [configuration]
+//	  - name: no-copy-to-running
+//	    action: deny
+//	    rpcs: [copy-config]
+//	    pattern: '<target>\s*<running/>'
+func ParsePolicy(b []byte) (*Policy, error) {
+	var f policyFile
+
+	err := yaml.Unmarshal(b, &f)
+	if err != nil {
+		return nil, scrapligoerrors.NewOptionsError("failed parsing policy", err)
+	}
+
+	return NewPolicy(f.Default, f.Rules...)
+}
+
+// LoadPolicy loads the yaml policy file at path, see ParsePolicy.
+func LoadPolicy(path string) (*Policy, error) {
+	b, err := os.ReadFile(path) //nolint: gosec
+	if err != nil {
+		return nil, scrapligoerrors.NewOptionsError(
+			fmt.Sprintf("failed loading policy file at path %q", path),
+			err,
+		)
+	}
+
+	return ParsePolicy(b)
+}
+
+// Evaluate evaluates the policy against the subject, returning a "policy" flavor error wrapping
+// ErrPolicyDenied if the subject is denied.
+func (p *Policy) Evaluate(s *Subject) error {
+	action, name := p.defaultAction, "default"
+
+	for _, rule := range p.rules {
+		if rule.matches(s) {
+			action, name = rule.Action, rule.name
+
+			break
+		}
+	}
+
+	if action == Allow {
+		return nil
+	}
+
+	if s.RPC != "" {
+		return scrapligoerrors.NewPolicyError(
+			fmt.Sprintf("rpc %q to host %q denied by policy %s", s.RPC, s.Host, name),
+		)
+	}
+
+	return scrapligoerrors.NewPolicyError(
+		fmt.Sprintf("input %q to host %q denied by policy %s", s.Input, s.Host, name),
+	)
+}
@@ -0,0 +1,165 @@
+package policy_test
+
+import (
+	"errors"
+	"testing"
+
+	scrapligoerrors "github.com/scrapli/scrapligo/v2/errors"
+	scrapligopolicy "github.com/scrapli/scrapligo/v2/policy"
+)
+
+const testPolicy = `
+default: allow
+rules:
+  - name: no-write-erase
+    action: deny
+    pattern: '^\s*write\s+erase'
+  - name: show-in-production
+    action: allow
+    pattern: '^show '
+    hosts: ['^prod-']
+  - name: no-config-in-production
+    action: deny
+    pattern: '.*'
+    hosts: ['^prod-']
+    platforms: [cisco_iosxe]
+    modes: [configuration]
+  - name: no-copy-to-running
+    action: deny
+    rpcs: [copy-config]
+    pattern: '<target>\s*<running/>'
+`
+
+func TestPolicyEvaluate(t *testing.T) {
+	p, err := scrapligopolicy.ParsePolicy([]byte(testPolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]struct {
+		subject scrapligopolicy.Subject
+		denied  bool
+	}{
+		"write-erase": {
+			subject: scrapligopolicy.Subject{Host: "lab-1", Input: "  write  erase"},
+			denied:  true,
+		},
+		"write-memory": {
+			subject: scrapligopolicy.Subject{Host: "lab-1", Input: "write memory"},
+		},
+		"config-in-production": {
+			subject: scrapligopolicy.Subject{
+				Host:     "prod-1",
+				Platform: "cisco_iosxe",
+				Mode:     "configuration",
+				Input:    "interface loopback0",
+			},
+			denied: true,
+		},
+		"show-in-production-config": {
+			subject: scrapligopolicy.Subject{
+				Host:     "prod-1",
+				Platform: "cisco_iosxe",
+				Mode:     "configuration",
+				Input:    "show run",
+			},
+		},
+		"config-in-production-other-platform": {
+			subject: scrapligopolicy.Subject{
+				Host:     "prod-1",
+				Platform: "arista_eos",
+				Mode:     "configuration",
+				Input:    "interface loopback0",
+			},
+		},
+		"config-in-lab": {
+			subject: scrapligopolicy.Subject{
+				Host:     "lab-1",
+				Platform: "cisco_iosxe",
+				Mode:     "configuration",
+				Input:    "interface loopback0",
+			},
+		},
+		"copy-config-to-running": {
+			subject: scrapligopolicy.Subject{
+				Host:  "lab-1",
+				RPC:   "copy-config",
+				Input: "<copy-config><target><running/></target></copy-config>",
+			},
+			denied: true,
+		},
+		"copy-config-to-candidate": {
+			subject: scrapligopolicy.Subject{
+				Host:  "lab-1",
+				RPC:   "copy-config",
+				Input: "<copy-config><target><candidate/></target></copy-config>",
+			},
+		},
+		"cli-rules-skip-rpcs": {
+			subject: scrapligopolicy.Subject{
+				Host:  "lab-1",
+				RPC:   "rpc",
+				Input: "write erase",
+			},
+		},
+	}
+
+	for caseName, caseData := range cases {
+		t.Run(caseName, func(t *testing.T) {
+			err := p.Evaluate(&caseData.subject)
+
+			if !caseData.denied {
+				if err != nil {
+					t.Fatalf("expected input to be allowed, got %v", err)
+				}
+
+				return
+			}
+
+			if !errors.Is(err, scrapligoerrors.ErrPolicyDenied) {
+				t.Fatalf("expected ErrPolicyDenied, got %v", err)
+			}
+
+			if !scrapligoerrors.IsKind(err, scrapligoerrors.Policy) {
+				t.Fatalf("expected policy error kind, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPolicyDefaultDeny(t *testing.T) {
+	p, err := scrapligopolicy.NewPolicy(
+		scrapligopolicy.Deny,
+		scrapligopolicy.Rule{Action: scrapligopolicy.Allow, Pattern: "^show "},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = p.Evaluate(&scrapligopolicy.Subject{Input: "show version"})
+	if err != nil {
+		t.Fatalf("expected input to be allowed, got %v", err)
+	}
+
+	err = p.Evaluate(&scrapligopolicy.Subject{Input: "reload"})
+	if !errors.Is(err, scrapligoerrors.ErrPolicyDenied) {
+		t.Fatalf("expected ErrPolicyDenied, got %v", err)
+	}
+}
+
+func TestNewPolicyInvalid(t *testing.T) {
+	cases := map[string]scrapligopolicy.Rule{
+		"action":  {Action: "maybe", Pattern: ".*"},
+		"pattern": {Action: scrapligopolicy.Deny, Pattern: "("},
+		"hosts":   {Action: scrapligopolicy.Deny, Pattern: ".*", Hosts: []string{"("}},
+	}
+
+	for caseName, rule := range cases {
+		t.Run(caseName, func(t *testing.T) {
+			_, err := scrapligopolicy.NewPolicy(scrapligopolicy.Allow, rule)
+			if !scrapligoerrors.IsKind(err, scrapligoerrors.Options) {
+				t.Fatalf("expected options error, got %v", err)
+			}
+		})
+	}
+}
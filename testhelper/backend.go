@@ -0,0 +1,14 @@
+package testhelper
+
+import "testing"
+
+// SkipGoBackend skips the test when built with the scrapligo_gobackend tag, for tests that
+// exercise libscrapli itself (i.e. the options libscrapli reports back) rather than driver
+// behavior.
+func SkipGoBackend(t *testing.T) {
+	t.Helper()
+
+	if GoBackend {
+		t.Skip("test exercises libscrapli, skipping w/ the go backend")
+	}
+}
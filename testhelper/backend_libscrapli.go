@@ -0,0 +1,8 @@
+//go:build !scrapligo_gobackend
+
+package testhelper
+
+// GoBackend is true when tests are built with the scrapligo_gobackend tag, meaning drivers default
+// to the pure go backend and libscrapli is never loaded (so there are no libscrapli leaks to
+// check).
+const GoBackend = false
@@ -0,0 +1,97 @@
+package otel
+
+import (
+	"context"
+	"time"
+
+	scrapligotracing "github.com/scrapli/scrapligo/v2/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/scrapli/scrapligo/v2"
+
+var _ scrapligotracing.Tracer = (*Tracer)(nil)
+
+// Tracer is a scrapligotracing.Tracer that emits driver spans as opentelemetry spans.
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// NewTracer returns a new Tracer emitting spans with provider, if provider is nil the global
+// (otel.GetTracerProvider) provider is used -- which is a no-op unless the application set one.
+func NewTracer(provider trace.TracerProvider) *Tracer {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+
+	return &Tracer{
+		tracer: provider.Tracer(tracerName),
+	}
+}
+
+// Start starts a (client kind) span, see scrapligotracing.Tracer.
+func (t *Tracer) Start(
+	ctx context.Context,
+	name string,
+	start time.Time,
+	attrs ...scrapligotracing.Attribute,
+) (context.Context, scrapligotracing.Span) { //nolint: ireturn
+	startOpts := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(toKeyValues(attrs)...),
+	}
+
+	if !start.IsZero() {
+		startOpts = append(startOpts, trace.WithTimestamp(start))
+	}
+
+	ctx, span := t.tracer.Start(ctx, name, startOpts...)
+
+	return ctx, &Span{span: span}
+}
+
+// Span is a scrapligotracing.Span wrapping an opentelemetry span.
+type Span struct {
+	span trace.Span
+}
+
+// SetAttributes sets the attributes on the span.
+func (s *Span) SetAttributes(attrs ...scrapligotracing.Attribute) {
+	s.span.SetAttributes(toKeyValues(attrs)...)
+}
+
+// End records err (if not nil) on the span and ends it.
+func (s *Span) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+
+	s.span.End()
+}
+
+func toKeyValues(attrs []scrapligotracing.Attribute) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+
+	for _, attr := range attrs {
+		key := attribute.Key(attr.Key)
+
+		switch v := attr.Value.(type) {
+		case string:
+			kvs = append(kvs, key.String(v))
+		case bool:
+			kvs = append(kvs, key.Bool(v))
+		case int:
+			kvs = append(kvs, key.Int(v))
+		case int64:
+			kvs = append(kvs, key.Int64(v))
+		case []string:
+			kvs = append(kvs, key.StringSlice(v))
+		}
+	}
+
+	return kvs
+}
@@ -0,0 +1,78 @@
+package otel_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	scrapligotracing "github.com/scrapli/scrapligo/v2/tracing"
+	scrapligotracingotel "github.com/scrapli/scrapligo/v2/tracing/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracer(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	tracer := scrapligotracingotel.NewTracer(provider)
+
+	start := time.Now().Add(-time.Second)
+
+	_, span := tracer.Start(
+		context.Background(),
+		"netconf.lock",
+		start,
+		scrapligotracing.Key("string").String("foo"),
+		scrapligotracing.Key("int").Int(1),
+	)
+
+	span.SetAttributes(
+		scrapligotracing.Key("int64").Int64(2),
+		scrapligotracing.Key("bool").Bool(true),
+		scrapligotracing.Key("slice").StringSlice([]string{"a", "b"}),
+	)
+
+	span.End(errors.New("boom"))
+
+	spans := exporter.GetSpans().Snapshots()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	s := spans[0]
+
+	if s.Name() != "netconf.lock" || s.SpanKind() != trace.SpanKindClient {
+		t.Fatalf("expected client span %q, got %s span %q", "netconf.lock", s.SpanKind(), s.Name())
+	}
+
+	if !s.StartTime().Equal(start) {
+		t.Fatalf("expected span to start at %s, got %s", start, s.StartTime())
+	}
+
+	if s.Status().Code != codes.Error || s.Status().Description != "boom" {
+		t.Fatalf("expected error status, got %+v", s.Status())
+	}
+
+	expected := []attribute.KeyValue{
+		attribute.String("string", "foo"),
+		attribute.Int("int", 1),
+		attribute.Int64("int64", 2),
+		attribute.Bool("bool", true),
+		attribute.StringSlice("slice", []string{"a", "b"}),
+	}
+
+	if len(s.Attributes()) != len(expected) {
+		t.Fatalf("expected attributes %v, got %v", expected, s.Attributes())
+	}
+
+	for idx, kv := range s.Attributes() {
+		if kv.Key != expected[idx].Key || kv.Value.Emit() != expected[idx].Value.Emit() {
+			t.Fatalf("expected attribute %v, got %v", expected[idx], kv)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package tracing
+
+import (
+	"context"
+	"time"
+)
+
+// Key is the key of a span attribute.
+type Key string
+
+// Attribute is a single span attribute, the value is a string, bool, int, int64 or []string.
+type Attribute struct {
+	Key   Key
+	Value any
+}
+
+// String returns a string attribute for the key.
+func (k Key) String(v string) Attribute {
+	return Attribute{Key: k, Value: v}
+}
+
+// Int returns an int attribute for the key.
+func (k Key) Int(v int) Attribute {
+	return Attribute{Key: k, Value: v}
+}
+
+// Int64 returns an int64 attribute for the key.
+func (k Key) Int64(v int64) Attribute {
+	return Attribute{Key: k, Value: v}
+}
+
+// Bool returns a bool attribute for the key.
+func (k Key) Bool(v bool) Attribute {
+	return Attribute{Key: k, Value: v}
+}
+
+// StringSlice returns a string slice attribute for the key.
+func (k Key) StringSlice(v []string) Attribute {
+	return Attribute{Key: k, Value: v}
+}
+
+// Tracer is the interface drivers start spans with, see the otel subpackage for an opentelemetry
+// backed implementation. Drivers emit a span per Open/Close, cli SendInput(s)/EnterMode operation
+// and netconf rpc.
+type Tracer interface {
+	// Start starts a span named name as a child of whatever span is in ctx, at start if start is
+	// not zero (now otherwise), and returns the context carrying the span.
+	Start(
+		ctx context.Context,
+		name string,
+		start time.Time,
+		attrs ...Attribute,
+	) (context.Context, Span)
+}
+
+// Span is a span started by a Tracer.
+type Span interface {
+	// SetAttributes sets the attributes on the span.
+	SetAttributes(attrs ...Attribute)
+	// End records err (if not nil) on the span and ends it.
+	End(err error)
+}